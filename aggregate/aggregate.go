@@ -0,0 +1,149 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package aggregate provides a buffer that collects items into batches,
+// flushed by size or by age, as telemetry exporters and batch writers do.
+package aggregate
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/clock"
+)
+
+// An Aggregator buffers items in a heap and passes them to a flush function
+// as a batch, least first, when either the buffer holds Size items or its
+// oldest item has been buffered for MaxAge. The size trigger is checked by
+// Add; the age trigger by Run, which must be running for it to apply.
+//
+// The flush function is called synchronously from Add, Flush or Run, with
+// the Aggregator locked, so batches are flushed one at a time and in order;
+// it must not call the Aggregator's methods.
+//
+// The exported fields configure the aggregator and must not be changed
+// after it is first used. An Aggregator must be created with New or
+// NewFunc. It is safe for concurrent use by multiple goroutines.
+type Aggregator[T any] struct {
+	// Size is the number of items that triggers a flush. If zero, only
+	// the age trigger applies.
+	Size int
+
+	// MaxAge is how long the oldest item may be buffered before a flush.
+	// If zero, only the size trigger applies.
+	MaxAge time.Duration
+
+	// Clock is the source of time. If nil, clock.Real is used.
+	Clock clock.Clock
+
+	flush func(batch []T)
+	less  func(x, y T) bool
+	wake  chan struct{}
+
+	mu     sync.Mutex
+	h      []T
+	oldest time.Time // when the oldest buffered item was added
+}
+
+// New returns an aggregator that passes each batch to flush.
+func New[T cmp.Ordered](flush func(batch []T)) *Aggregator[T] {
+	return NewFunc(flush, cmp.Less[T])
+}
+
+// NewFunc is like [New] but uses a less function to compare items.
+func NewFunc[T any](flush func(batch []T), less func(x, y T) bool) *Aggregator[T] {
+	return &Aggregator[T]{flush: flush, less: less, wake: make(chan struct{}, 1)}
+}
+
+func (a *Aggregator[T]) clock() clock.Clock {
+	if a.Clock == nil {
+		return clock.Real
+	}
+	return a.Clock
+}
+
+// Len returns the number of items buffered.
+func (a *Aggregator[T]) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.h)
+}
+
+// Add buffers x, flushing the buffer if it has reached Size items.
+// The complexity is O(log n) where n = a.Len(), or O(n log n) when the
+// buffer is flushed.
+func (a *Aggregator[T]) Add(x T) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.h) == 0 {
+		a.oldest = a.clock().Now()
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+	}
+	sliceheap.PushFunc(&a.h, x, a.less)
+	if a.Size > 0 && len(a.h) >= a.Size {
+		a.flushLocked()
+	}
+}
+
+// Flush flushes the buffer, if it is not empty.
+func (a *Aggregator[T]) Flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.flushLocked()
+}
+
+func (a *Aggregator[T]) flushLocked() {
+	if len(a.h) == 0 {
+		return
+	}
+	batch := make([]T, 0, len(a.h))
+	for len(a.h) > 0 {
+		batch = append(batch, sliceheap.PopFunc(&a.h, a.less))
+	}
+	a.flush(batch)
+}
+
+// Run flushes the buffer whenever its oldest item reaches MaxAge, until ctx
+// is done, and then flushes any items remaining.
+func (a *Aggregator[T]) Run(ctx context.Context) {
+	defer a.Flush()
+	var t clock.Timer
+	defer func() {
+		if t != nil {
+			t.Stop()
+		}
+	}()
+	for {
+		a.mu.Lock()
+		var timer <-chan time.Time
+		if len(a.h) > 0 && a.MaxAge > 0 {
+			d := a.oldest.Add(a.MaxAge).Sub(a.clock().Now())
+			if d <= 0 {
+				a.flushLocked()
+				a.mu.Unlock()
+				continue
+			}
+			if t == nil {
+				t = a.clock().NewTimer(d)
+			} else {
+				t.Reset(d)
+			}
+			timer = t.C()
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-a.wake:
+		case <-timer:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
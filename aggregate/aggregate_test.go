@@ -0,0 +1,88 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package aggregate
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	flushed chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{flushed: make(chan struct{}, 10)}
+}
+
+func (r *recorder) flush(batch []int) {
+	r.mu.Lock()
+	r.batches = append(r.batches, batch)
+	r.mu.Unlock()
+	r.flushed <- struct{}{}
+}
+
+func (r *recorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.batches)
+}
+
+func TestSize(t *testing.T) {
+	r := newRecorder()
+	a := New(r.flush)
+	a.Size = 3
+	for _, x := range []int{5, 2, 9, 1} {
+		a.Add(x)
+	}
+	a.Flush()
+	a.Flush() // empty: no batch
+	got := r.get()
+	if len(got) != 2 || !slices.Equal(got[0], []int{2, 5, 9}) || !slices.Equal(got[1], []int{1}) {
+		t.Errorf("batches = %v; want [[2 5 9] [1]]", got)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	r := newRecorder()
+	a := NewFunc(r.flush, func(x, y int) bool { return x > y })
+	a.MaxAge = time.Second
+	a.Clock = fake
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+
+	a.Add(1)
+	fake.Advance(500 * time.Millisecond)
+	a.Add(3)
+	// Wait for Run to arm its timer for the oldest item.
+	for fake.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fake.Advance(500 * time.Millisecond)
+	<-r.flushed
+	if got := r.get(); len(got) != 1 || !slices.Equal(got[0], []int{3, 1}) {
+		t.Errorf("batches = %v; want [[3 1]]", got)
+	}
+
+	a.Add(7)
+	cancel()
+	<-done
+	if got := r.get(); len(got) != 2 || !slices.Equal(got[1], []int{7}) {
+		t.Errorf("batches after Run returned = %v; want final [7]", got)
+	}
+}
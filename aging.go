@@ -0,0 +1,113 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"fmt"
+	"time"
+)
+
+// An AgingQueue is a priority queue in which waiting items gain priority,
+// so that a steady stream of high-priority items cannot starve low-priority
+// ones. The effective priority of an item is its base priority plus
+// boost(waited), where waited is how long it has been queued; the item
+// with the highest effective priority is popped first, ties in the order
+// pushed.
+//
+// Rather than recomputing every priority on each operation, the queue
+// re-scores all items once per epoch, ordering them in between by their
+// priorities at the start of the epoch. Shorter epochs track the boost
+// curve more closely at the cost of more frequent O(n) rebuilds.
+type AgingQueue[T any] struct {
+	// Hooks observe items being pushed and popped.
+	Hooks Hooks[T]
+
+	boost func(waited time.Duration) float64
+	epoch time.Duration
+	start time.Time // start of the current epoch
+	h     []agingItem[T]
+	seq   uint64
+}
+
+type agingItem[T any] struct {
+	score    float64 // effective priority at the start of the epoch
+	priority float64
+	enqueued time.Time
+	seq      uint64
+	x        T
+}
+
+func agingLess[T any](x, y agingItem[T]) bool {
+	if x.score != y.score {
+		return x.score > y.score
+	}
+	return x.seq < y.seq
+}
+
+// NewAgingQueue returns an empty queue that boosts waiting items by
+// boost(waited) and re-scores them every epoch. The boost should be
+// non-decreasing; for example,
+//
+//	func(w time.Duration) float64 { return w.Seconds() / 10 }
+//
+// raises an item's priority by one for every ten seconds it waits.
+// NewAgingQueue panics if epoch is not positive.
+func NewAgingQueue[T any](boost func(waited time.Duration) float64, epoch time.Duration) *AgingQueue[T] {
+	if epoch <= 0 {
+		panic(fmt.Sprintf("sliceheap: aging epoch %v not positive", epoch))
+	}
+	return &AgingQueue[T]{boost: boost, epoch: epoch}
+}
+
+// Len returns the number of items in the queue.
+func (q *AgingQueue[T]) Len() int {
+	return len(q.h)
+}
+
+// score returns the effective priority of an item at the start of the
+// current epoch.
+func (q *AgingQueue[T]) score(it agingItem[T]) float64 {
+	return it.priority + q.boost(max(q.start.Sub(it.enqueued), 0))
+}
+
+// advance starts a new epoch if the current one has ended by now.
+func (q *AgingQueue[T]) advance(now time.Time) {
+	if now.Sub(q.start) < q.epoch {
+		return
+	}
+	q.start = now
+	for i := range q.h {
+		q.h[i].score = q.score(q.h[i])
+	}
+	InitFunc(q.h, agingLess[T])
+}
+
+// Push adds x with the given base priority at time now, which must not go
+// backwards between calls.
+// The complexity is O(log n) where n = q.Len(), plus O(n) at the start of
+// each epoch.
+func (q *AgingQueue[T]) Push(x T, priority float64, now time.Time) {
+	q.advance(now)
+	it := agingItem[T]{priority: priority, enqueued: now, seq: q.seq, x: x}
+	it.score = q.score(it)
+	q.seq++
+	PushFunc(&q.h, it, agingLess[T])
+	q.Hooks.push(x)
+}
+
+// Pop removes and returns the item with the highest effective priority at
+// time now, and reports false if the queue is empty.
+// The complexity is O(log n) where n = q.Len(), plus O(n) at the start of
+// each epoch.
+func (q *AgingQueue[T]) Pop(now time.Time) (T, bool) {
+	if len(q.h) == 0 {
+		var zero T
+		return zero, false
+	}
+	q.advance(now)
+	x := PopFunc(&q.h, agingLess[T]).x
+	q.Hooks.leave(x, Popped)
+	return x, true
+}
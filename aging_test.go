@@ -0,0 +1,62 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgingQueue(t *testing.T) {
+	// One point of priority per second waited, re-scored every second.
+	q := NewAgingQueue[string](func(w time.Duration) float64 { return w.Seconds() }, time.Second)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	q.Push("low", 0, t0)
+	q.Push("high", 5, t0)
+	if x, _ := q.Pop(t0); x != "high" {
+		t.Fatalf("Pop() = %q; want high", x)
+	}
+
+	// A stream of new priority-3 items does not starve "low" forever:
+	// after more than 3s of waiting it outranks them.
+	var order []string
+	for i := 1; i <= 6; i++ {
+		now := t0.Add(time.Duration(i) * time.Second)
+		q.Push("new", 3, now)
+		x, _ := q.Pop(now)
+		order = append(order, x)
+	}
+	low := -1
+	for i, x := range order {
+		if x == "low" {
+			low = i
+		}
+	}
+	if low < 0 || low > 3 {
+		t.Errorf("pops %v; want low within the first four", order)
+	}
+
+	if q.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", q.Len())
+	}
+	q.Pop(t0.Add(time.Hour))
+	if _, ok := q.Pop(t0.Add(time.Hour)); ok {
+		t.Error("Pop of empty queue succeeded")
+	}
+}
+
+func TestAgingQueueTies(t *testing.T) {
+	q := NewAgingQueue[int](func(time.Duration) float64 { return 0 }, time.Minute)
+	now := time.Now()
+	for i := range 5 {
+		q.Push(i, 1, now)
+	}
+	for i := range 5 {
+		if x, _ := q.Pop(now); x != i {
+			t.Fatalf("Pop() = %d; want %d", x, i)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "testing"
+
+// TestAllocs enforces the allocation guarantees stated in the package
+// documentation.
+func TestAllocs(t *testing.T) {
+	if debug {
+		t.Skip("debug checks allocate")
+	}
+	h := make([]int, 0, 1024)
+	for i := range 1000 {
+		h = append(h, 1000-i)
+	}
+	Init(h)
+
+	for name, f := range map[string]func(){
+		"Init":    func() { Init(h) },
+		"PushPop": func() { Push(&h, 5); Pop(&h) },
+		"Remove":  func() { Init(h); Push(&h, 7); Remove(&h, len(h)-1) },
+		"Fix":     func() { h[10] = -h[10]; Fix(h, 10) },
+	} {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("%s: %v allocations per run; want 0", name, n)
+		}
+	}
+
+	// Push allocates only to grow the slice.
+	full := make([]int, 8)
+	if n := testing.AllocsPerRun(100, func() {
+		s := full[:8:8]
+		Push(&s, 0)
+	}); n > 1 {
+		t.Errorf("Push on a full slice: %v allocations per run; want at most 1", n)
+	}
+}
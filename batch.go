@@ -0,0 +1,101 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+)
+
+// A Batch records changes to a heap and applies them together. Indices
+// passed to Remove and Update refer to positions in the heap as it was when
+// the batch was created, so they do not shift as changes are recorded.
+// Commit applies the changes with a single rebuild of the heap, unless the
+// batch holds only a few pushes, which are then pushed one at a time.
+//
+// The heap must not be changed by other means between creating a batch
+// and committing it.
+type Batch[T any] struct {
+	h       *[]T
+	less    func(x, y T) bool
+	pushes  []T
+	removed map[int]bool
+	updates map[int]T
+}
+
+// NewBatch returns an empty batch of changes to the heap *h.
+func NewBatch[T cmp.Ordered](h *[]T) *Batch[T] {
+	return NewBatchFunc(h, cmp.Less[T])
+}
+
+// NewBatchFunc is like [NewBatch] but uses a less function to compare elements.
+func NewBatchFunc[T any](h *[]T, less func(x, y T) bool) *Batch[T] {
+	return &Batch[T]{h: h, less: less}
+}
+
+// Push records the addition of x.
+func (b *Batch[T]) Push(x T) {
+	b.pushes = append(b.pushes, x)
+}
+
+// Remove records the removal of the element at index i.
+// It panics if i is out of range or already removed.
+func (b *Batch[T]) Remove(i int) {
+	b.check("Remove", i)
+	if b.removed == nil {
+		b.removed = make(map[int]bool)
+	}
+	b.removed[i] = true
+	delete(b.updates, i)
+}
+
+// Update records the replacement of the element at index i by x.
+// It panics if i is out of range or removed.
+func (b *Batch[T]) Update(i int, x T) {
+	b.check("Update", i)
+	if b.updates == nil {
+		b.updates = make(map[int]T)
+	}
+	b.updates[i] = x
+}
+
+func (b *Batch[T]) check(op string, i int) {
+	if n := len(*b.h); uint(i) >= uint(n) {
+		panicRange("Batch."+op, i, n)
+	}
+	if b.removed[i] {
+		panic(fmt.Sprintf("sliceheap: Batch.%s index %d already removed", op, i))
+	}
+}
+
+// Commit applies the recorded changes to the heap and empties the batch.
+// The complexity is O(n) where n = len(*h), or O(k log n) for a batch of k
+// pushes alone when that is less.
+func (b *Batch[T]) Commit() {
+	h := *b.h
+	k := len(b.pushes)
+	if len(b.removed) == 0 && len(b.updates) == 0 && k*bits.Len(uint(len(h)+k)) < len(h) {
+		for _, x := range b.pushes {
+			PushFunc(&h, x, b.less)
+		}
+	} else {
+		for i, x := range b.updates {
+			h[i] = x
+		}
+		n := 0
+		for i, x := range h {
+			if !b.removed[i] {
+				h[n] = x
+				n++
+			}
+		}
+		clear(h[n:])
+		h = append(h[:n], b.pushes...)
+		InitFunc(h, b.less)
+	}
+	*b.h = h
+	b.pushes, b.removed, b.updates = nil, nil, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	for _, changes := range []int{1, 5, 50, 500} {
+		for _, mode := range []string{"push", "update", "remove"} {
+			var h []int
+			for range 1000 {
+				Push(&h, rand.Intn(10000))
+			}
+			want := slices.Clone(h)
+			b := NewBatch(&h)
+			gone := make(map[int]bool)
+			for range changes {
+				i := rand.Intn(1000)
+				if gone[i] {
+					continue
+				}
+				switch {
+				case mode == "remove" && rand.Intn(3) == 0:
+					b.Remove(i)
+					gone[i] = true
+				case mode != "push" && rand.Intn(2) == 0:
+					x := rand.Intn(10000)
+					b.Update(i, x)
+					want[i] = x
+				default:
+					x := rand.Intn(10000)
+					b.Push(x)
+					want = append(want, x)
+				}
+			}
+			var kept []int
+			for i, x := range want {
+				if !gone[i] {
+					kept = append(kept, x)
+				}
+			}
+			b.Commit()
+			verify(t, h)
+			slices.Sort(kept)
+			if got := slices.Sorted(slices.Values(h)); !slices.Equal(got, kept) {
+				t.Fatalf("%d changes in %s mode: heap holds wrong elements", changes, mode)
+			}
+		}
+	}
+}
+
+func TestBatchPanics(t *testing.T) {
+	h := []int{1, 2, 3}
+	b := NewBatch(&h)
+	b.Remove(1)
+	for _, tt := range []struct {
+		f    func()
+		want string
+	}{
+		{func() { b.Update(1, 5) }, "sliceheap: Batch.Update index 1 already removed"},
+		{func() { b.Remove(3) }, "sliceheap: Batch.Remove index 3 out of range [0,3)"},
+	} {
+		func() {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("panic = %v; want %q", got, tt.want)
+				}
+			}()
+			tt.f()
+		}()
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bench compares priority queue implementations on representative
+// workloads, reporting throughput and allocations, so that users can choose
+// an implementation for their own access pattern. It measures the queues
+// of this module by default, and accepts any other that implements
+// [sliceheap.PriorityQueue].
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/buth/sliceheap"
+)
+
+// An Updater is a queue that can lower the key of a queued ID, as
+// decrease-key does in Dijkstra's algorithm. PushID pushes key for id.
+// DecreaseKey returns an error if id is not queued or the key is not
+// lower.
+type Updater interface {
+	sliceheap.PriorityQueue[int]
+	PushID(id, key int)
+	DecreaseKey(id, key int) error
+}
+
+// An Impl is an implementation to measure. New returns an empty queue of
+// integer keys for a workload of size n. A workload pushes fewer than 2n
+// keys and passes IDs below n to PushID, so an implementation with a fixed
+// capacity can allocate it up front.
+type Impl struct {
+	Name string
+	New  func(n int) sliceheap.PriorityQueue[int]
+}
+
+// A Workload is a sequence of operations of a given size on a heap.
+type Workload struct {
+	Name string
+
+	// NeedsUpdate reports whether Run requires the heap to be an
+	// Updater. Implementations that are not are skipped.
+	NeedsUpdate bool
+
+	// Run applies the workload of size n to h, returning the number of
+	// operations performed.
+	Run func(h sliceheap.PriorityQueue[int], r *rand.Rand, n int) int
+}
+
+// Impls returns the implementations in this module: the sliceheap
+// functions on a slice, OrderedHeap, UpdatableQueue and IndexHeap.
+func Impls() []Impl {
+	return []Impl{
+		{"binary", func(int) sliceheap.PriorityQueue[int] { return new(binary) }},
+		{"ordered", func(int) sliceheap.PriorityQueue[int] { return new(sliceheap.OrderedHeap[int]) }},
+		{"updatable", func(int) sliceheap.PriorityQueue[int] { return newUpdatable() }},
+		{"indexed", func(n int) sliceheap.PriorityQueue[int] { return newIndexed(2 * n) }},
+	}
+}
+
+// Workloads returns the standard workloads: push-heavy, pop-heavy, mixed
+// and decrease-key-heavy.
+func Workloads() []Workload {
+	return []Workload{
+		{Name: "push-heavy", Run: pushHeavy},
+		{Name: "pop-heavy", Run: popHeavy},
+		{Name: "mixed", Run: mixed},
+		{Name: "decrease-key", NeedsUpdate: true, Run: decreaseKey},
+	}
+}
+
+// pushHeavy pushes n keys, popping one for every four pushed.
+func pushHeavy(h sliceheap.PriorityQueue[int], r *rand.Rand, n int) int {
+	for i := range n {
+		h.Push(r.IntN(n))
+		if i%4 == 3 {
+			h.Pop()
+		}
+	}
+	return n + n/4
+}
+
+// popHeavy pushes n keys and then pops them all.
+func popHeavy(h sliceheap.PriorityQueue[int], r *rand.Rand, n int) int {
+	for range n {
+		h.Push(r.IntN(n))
+	}
+	for h.Len() > 0 {
+		h.Pop()
+	}
+	return 2 * n
+}
+
+// mixed keeps about n/2 keys queued, randomly pushing or popping, as an
+// event queue in steady state does.
+func mixed(h sliceheap.PriorityQueue[int], r *rand.Rand, n int) int {
+	for range n / 2 {
+		h.Push(r.IntN(n))
+	}
+	for range n {
+		if h.Len() == 0 || r.IntN(2) == 0 {
+			h.Push(r.IntN(n))
+		} else {
+			h.Pop()
+		}
+	}
+	return n/2 + n
+}
+
+// decreaseKey pushes n keys, lowers 2n of them, and pops them all, as
+// Dijkstra's algorithm on a dense graph does.
+func decreaseKey(h sliceheap.PriorityQueue[int], r *rand.Rand, n int) int {
+	u := h.(Updater)
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = n + r.IntN(n)
+		u.PushID(i, keys[i])
+	}
+	for range 2 * n {
+		id := r.IntN(n)
+		keys[id] -= r.IntN(keys[id]/2 + 1)
+		if err := u.DecreaseKey(id, keys[id]); err != nil {
+			panic(err)
+		}
+	}
+	for h.Len() > 0 {
+		h.Pop()
+	}
+	return 4 * n
+}
+
+// Config configures a comparison.
+type Config struct {
+	Impls     []Impl     // if nil, Impls()
+	Workloads []Workload // if nil, Workloads()
+	Size      int        // workload size; if zero, 10000
+	Rounds    int        // times each workload is run; if zero, 10
+	Seed      uint64
+}
+
+// A Result is the measurement of one implementation on one workload.
+type Result struct {
+	Impl, Workload string
+	Ops            int // operations performed over all rounds
+	Elapsed        time.Duration
+	Allocs         uint64 // heap allocations over all rounds
+}
+
+// OpsPerSec returns the throughput in operations per second.
+func (r Result) OpsPerSec() float64 {
+	return float64(r.Ops) / r.Elapsed.Seconds()
+}
+
+// AllocsPerOp returns the mean number of allocations per operation.
+func (r Result) AllocsPerOp() float64 {
+	return float64(r.Allocs) / float64(r.Ops)
+}
+
+// Compare runs every workload against every implementation and returns the
+// results, grouped by workload. Each round uses a fresh heap and the same
+// random sequence for every implementation.
+func Compare(c Config) []Result {
+	impls, workloads := c.Impls, c.Workloads
+	if impls == nil {
+		impls = Impls()
+	}
+	if workloads == nil {
+		workloads = Workloads()
+	}
+	size, rounds := c.Size, c.Rounds
+	if size == 0 {
+		size = 10000
+	}
+	if rounds == 0 {
+		rounds = 10
+	}
+
+	var results []Result
+	var before, after runtime.MemStats
+	for _, w := range workloads {
+		for _, impl := range impls {
+			if _, ok := impl.New(0).(Updater); w.NeedsUpdate && !ok {
+				continue
+			}
+			res := Result{Impl: impl.Name, Workload: w.Name}
+			for i := range rounds {
+				h := impl.New(size)
+				r := rand.New(rand.NewPCG(c.Seed, uint64(i)))
+				runtime.ReadMemStats(&before)
+				start := time.Now()
+				res.Ops += w.Run(h, r, size)
+				res.Elapsed += time.Since(start)
+				runtime.ReadMemStats(&after)
+				res.Allocs += after.Mallocs - before.Mallocs
+			}
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// Print writes results to w as a table.
+func Print(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "workload\timpl\tops/sec\tallocs/op\t\n")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%.0f\t%.3f\t\n", r.Workload, r.Impl, r.OpsPerSec(), r.AllocsPerOp())
+	}
+	return tw.Flush()
+}
+
+// binary is the sliceheap functions with a less function on a slice.
+type binary []int
+
+func (b *binary) Push(key int) { sliceheap.PushFunc((*[]int)(b), key, cmp.Less[int]) }
+func (b *binary) Pop() int     { return sliceheap.PopFunc((*[]int)(b), cmp.Less[int]) }
+func (b *binary) Peek() int    { return (*b)[0] }
+func (b *binary) Len() int     { return len(*b) }
+
+// updatable is sliceheap.UpdatableQueue. Keys pushed without an ID are
+// given one from next, counting down from -1 so as not to collide with
+// the IDs passed to PushID.
+type updatable struct {
+	q    *sliceheap.UpdatableQueue[int, int]
+	next int
+}
+
+func newUpdatable() *updatable {
+	return &updatable{q: sliceheap.NewUpdatableQueue[int, int](), next: -1}
+}
+
+func (u *updatable) Push(key int) {
+	u.q.PushOrUpdate(u.next, key)
+	u.next--
+}
+
+func (u *updatable) PushID(id, key int)            { u.q.PushOrUpdate(id, key) }
+func (u *updatable) DecreaseKey(id, key int) error { return u.q.DecreaseKey(id, key) }
+func (u *updatable) Len() int                      { return u.q.Len() }
+
+func (u *updatable) Pop() int {
+	_, key, ok := u.q.Pop()
+	if !ok {
+		panic("bench: Pop on empty queue")
+	}
+	return key
+}
+
+func (u *updatable) Peek() int {
+	_, key, ok := u.q.Peek()
+	if !ok {
+		panic("bench: Peek on empty queue")
+	}
+	return key
+}
+
+// indexed is sliceheap.IndexHeap over a fixed set of indices, each holding
+// a key. Push gives keys consecutive indices from 0, so it is not mixed
+// with PushID.
+type indexed struct {
+	keys []int
+	h    *sliceheap.IndexHeap[int]
+	next int
+}
+
+func newIndexed(n int) *indexed {
+	keys := make([]int, n)
+	h := sliceheap.NewIndexHeap(keys)
+	// The keys are equal, so index i is at position i and removing from
+	// the end moves nothing.
+	for i := n - 1; i >= 0; i-- {
+		h.Remove(i)
+	}
+	return &indexed{keys: keys, h: h}
+}
+
+func (x *indexed) Push(key int) {
+	x.PushID(x.next, key)
+	x.next++
+}
+
+func (x *indexed) PushID(id, key int) {
+	x.keys[id] = key
+	x.h.Push(id)
+}
+
+func (x *indexed) DecreaseKey(id, key int) error {
+	if !x.h.Contains(id) {
+		return sliceheap.ErrNoID
+	}
+	return x.h.DecreaseKey(id, key)
+}
+
+func (x *indexed) Len() int { return x.h.Len() }
+
+func (x *indexed) Pop() int {
+	i, ok := x.h.Pop()
+	if !ok {
+		panic("bench: Pop on empty queue")
+	}
+	return x.keys[i]
+}
+
+func (x *indexed) Peek() int {
+	i, ok := x.h.Peek()
+	if !ok {
+		panic("bench: Peek on empty queue")
+	}
+	return x.keys[i]
+}
@@ -0,0 +1,51 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	results := Compare(Config{Size: 500, Rounds: 2})
+	// decrease-key runs only on updatable and indexed.
+	if len(results) != 14 {
+		t.Fatalf("got %d results; want 14", len(results))
+	}
+	for _, r := range results {
+		if r.Ops == 0 || r.Elapsed <= 0 {
+			t.Errorf("%s/%s: %d ops in %v", r.Workload, r.Impl, r.Ops, r.Elapsed)
+		}
+		if r.Workload == "decrease-key" && r.Impl != "updatable" && r.Impl != "indexed" {
+			t.Errorf("decrease-key run on %s", r.Impl)
+		}
+	}
+
+	var b strings.Builder
+	if err := Print(&b, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "decrease-key") || strings.Count(b.String(), "\n") != 15 {
+		t.Errorf("Print output:\n%s", b.String())
+	}
+}
+
+func TestWorkloadsPopInOrder(t *testing.T) {
+	for _, impl := range Impls() {
+		h := impl.New(4)
+		for _, k := range []int{5, 2, 8, 2} {
+			h.Push(k)
+		}
+		last := -1
+		for h.Len() > 0 {
+			k := h.Pop()
+			if k < last {
+				t.Errorf("%s popped %d after %d", impl.Name, k, last)
+			}
+			last = k
+		}
+	}
+}
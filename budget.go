@@ -0,0 +1,149 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A BudgetHeap is a min-heap whose capacity is a budget on the total size of
+// its elements, as measured by a size function, rather than on their
+// number; for example, the bytes held by a cache or buffer. When a push
+// takes the total over the budget, the greatest elements are evicted until
+// it fits again. Of equal elements, the most recently pushed is evicted
+// first.
+//
+// The least and greatest elements are tracked by two heaps over the same
+// entries, so Push, Pop and each eviction cost O(log n).
+type BudgetHeap[T any] struct {
+	// Hooks observe elements entering, being popped from, and being
+	// evicted from the heap.
+	Hooks Hooks[T]
+
+	budget int
+	size   func(T) int
+	less   func(x, y T) bool
+	used   int
+	seq    uint64
+	min    []*budgetEntry[T]
+	max    []*budgetEntry[T]
+}
+
+type budgetEntry[T any] struct {
+	x        T
+	size     int
+	seq      uint64
+	minIndex int
+	maxIndex int
+}
+
+// NewBudgetHeap returns an empty heap whose elements' sizes may total at
+// most budget.
+// It panics if budget < 0.
+func NewBudgetHeap[T cmp.Ordered](budget int, size func(T) int) *BudgetHeap[T] {
+	return NewBudgetHeapFunc(budget, size, cmp.Less[T])
+}
+
+// NewBudgetHeapFunc is like [NewBudgetHeap] but uses a less function to compare elements.
+func NewBudgetHeapFunc[T any](budget int, size func(T) int, less func(x, y T) bool) *BudgetHeap[T] {
+	if budget < 0 {
+		panic(fmt.Sprintf("sliceheap: budget %d < 0", budget))
+	}
+	return &BudgetHeap[T]{budget: budget, size: size, less: less}
+}
+
+func (b *BudgetHeap[T]) minLess(x, y *budgetEntry[T]) bool {
+	if b.less(x.x, y.x) {
+		return true
+	}
+	if b.less(y.x, x.x) {
+		return false
+	}
+	return x.seq < y.seq
+}
+
+func (b *BudgetHeap[T]) maxLess(x, y *budgetEntry[T]) bool {
+	return b.minLess(y, x)
+}
+
+func setMinIndex[T any](e *budgetEntry[T], i int) { e.minIndex = i }
+func setMaxIndex[T any](e *budgetEntry[T], i int) { e.maxIndex = i }
+
+// Len returns the number of elements in the heap.
+func (b *BudgetHeap[T]) Len() int {
+	return len(b.min)
+}
+
+// Used returns the total size of the elements in the heap.
+func (b *BudgetHeap[T]) Used() int {
+	return b.used
+}
+
+// Push adds x and then evicts the greatest elements until the total size is
+// within the budget, returning those evicted. An element larger than the
+// whole budget is evicted at once.
+// The complexity is O((k+1) log n) where k is the number of elements
+// evicted and n = b.Len().
+func (b *BudgetHeap[T]) Push(x T) []T {
+	e := &budgetEntry[T]{x: x, size: b.size(x), seq: b.seq}
+	b.seq++
+	indexheap.Push(&b.min, e, b.minLess, setMinIndex)
+	indexheap.Push(&b.max, e, b.maxLess, setMaxIndex)
+	b.used += e.size
+	b.Hooks.push(x)
+
+	var evicted []T
+	for b.used > b.budget {
+		w := indexheap.Pop(&b.max, b.maxLess, setMaxIndex)
+		indexheap.Remove(&b.min, w.minIndex, b.minLess, setMinIndex)
+		b.used -= w.size
+		evicted = append(evicted, w.x)
+		b.Hooks.leave(w.x, Evicted)
+	}
+	return evicted
+}
+
+// Peek returns the least element without removing it, and reports false if
+// the heap is empty.
+// The complexity is O(1).
+func (b *BudgetHeap[T]) Peek() (T, bool) {
+	if len(b.min) == 0 {
+		var zero T
+		return zero, false
+	}
+	return b.min[0].x, true
+}
+
+// Pop removes and returns the least element, and reports false if the heap
+// is empty.
+// The complexity is O(log n) where n = b.Len().
+func (b *BudgetHeap[T]) Pop() (T, bool) {
+	if len(b.min) == 0 {
+		var zero T
+		return zero, false
+	}
+	e := indexheap.Pop(&b.min, b.minLess, setMinIndex)
+	indexheap.Remove(&b.max, e.maxIndex, b.maxLess, setMaxIndex)
+	b.used -= e.size
+	b.Hooks.leave(e.x, Popped)
+	return e.x, true
+}
+
+// Queue returns the heap as a [PriorityQueue]. The queue's Push discards
+// the elements it evicts, which Hooks still observe, and its Pop and Peek
+// panic if the heap is empty.
+func (b *BudgetHeap[T]) Queue() PriorityQueue[T] {
+	return budgetQueue[T]{b}
+}
+
+type budgetQueue[T any] struct{ b *BudgetHeap[T] }
+
+func (q budgetQueue[T]) Len() int { return q.b.Len() }
+func (q budgetQueue[T]) Push(x T) { q.b.Push(x) }
+func (q budgetQueue[T]) Pop() T   { return nonEmpty(q.b.Pop()) }
+func (q budgetQueue[T]) Peek() T  { return nonEmpty(q.b.Peek()) }
@@ -0,0 +1,66 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBudgetHeap(t *testing.T) {
+	// Strings ordered by value, sized by length, within 10 bytes.
+	b := NewBudgetHeap(10, func(s string) int { return len(s) })
+	for _, s := range []string{"dd", "bbb", "a", "cccc"} {
+		if ev := b.Push(s); ev != nil {
+			t.Fatalf("Push(%q) evicted %v within budget", s, ev)
+		}
+	}
+	if b.Used() != 10 || b.Len() != 4 {
+		t.Fatalf("Used() = %d, Len() = %d; want 10, 4", b.Used(), b.Len())
+	}
+
+	// Adding 3 bytes evicts the greatest elements until 10 bytes remain.
+	if ev := b.Push("ee"); !slices.Equal(ev, []string{"ee"}) {
+		t.Errorf("Push(ee) evicted %v; want [ee]", ev)
+	}
+	if ev := b.Push("aaa"); !slices.Equal(ev, []string{"dd", "cccc"}) {
+		t.Errorf("Push(aaa) evicted %v; want [dd cccc]", ev)
+	}
+	if ev := b.Push(strings.Repeat("z", 11)); len(ev) != 1 || len(ev[0]) != 11 {
+		t.Errorf("Push of oversized element evicted %v; want only it", ev)
+	}
+
+	var got []string
+	for {
+		s, ok := b.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, s)
+	}
+	if want := []string{"a", "aaa", "bbb"}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+	if b.Used() != 0 {
+		t.Errorf("Used() = %d after draining; want 0", b.Used())
+	}
+}
+
+func TestBudgetHeapTies(t *testing.T) {
+	type item struct {
+		pri int
+		id  string
+	}
+	b := NewBudgetHeapFunc(2, func(item) int { return 1 }, func(x, y item) bool { return x.pri < y.pri })
+	b.Push(item{1, "first"})
+	b.Push(item{1, "second"})
+	if ev := b.Push(item{1, "third"}); len(ev) != 1 || ev[0].id != "third" {
+		t.Errorf("evicted %v; want the most recent equal element", ev)
+	}
+	if x, _ := b.Peek(); x.id != "first" {
+		t.Errorf("Peek() = %v; want first", x)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bus provides an in-process publish/subscribe bus whose
+// subscribers receive messages in priority order rather than in the order
+// they were published.
+package bus
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/internal/notify"
+)
+
+// ErrClosed is returned by Publish after the bus is closed, and by Recv
+// once its subscription is closed and drained.
+var ErrClosed = errors.New("bus: closed")
+
+// A Policy says what Publish does when a subscription's queue is full.
+type Policy int
+
+const (
+	// DropWorst evicts the greatest queued message, which may be the
+	// one being published.
+	DropWorst Policy = iota
+
+	// DropNew discards the message being published.
+	DropNew
+
+	// Block waits for the subscriber to make room.
+	Block
+)
+
+// A Bus delivers each message published to a topic to every subscription
+// to that topic. Each subscription queues its messages in a heap and
+// receives the least first; messages that compare equal are received in
+// the order they were published.
+//
+// The exported fields configure the bus and must not be changed after it is
+// first used. A Bus must be created with New or NewFunc. It is safe for
+// concurrent use by multiple goroutines.
+type Bus[T any] struct {
+	// Depth is the number of messages a subscription may queue. If zero,
+	// queues are unbounded.
+	Depth int
+
+	// Policy is applied when a subscription's queue is full.
+	Policy Policy
+
+	less   func(x, y T) bool
+	mu     sync.Mutex
+	topics map[string][]*Subscription[T]
+	closed bool
+}
+
+// New returns a bus on which the least message is received first.
+func New[T cmp.Ordered]() *Bus[T] {
+	return NewFunc(cmp.Less[T])
+}
+
+// NewFunc is like [New] but uses a less function to compare messages.
+func NewFunc[T any](less func(x, y T) bool) *Bus[T] {
+	return &Bus[T]{less: less, topics: make(map[string][]*Subscription[T])}
+}
+
+// Subscribe returns a new subscription to topic.
+func (b *Bus[T]) Subscribe(topic string) *Subscription[T] {
+	depth := b.Depth
+	if depth <= 0 {
+		depth = math.MaxInt
+	}
+	s := &Subscription[T]{
+		b:     b,
+		topic: topic,
+		depth: depth,
+		q:     sliceheap.NewBudgetHeapFunc(depth, func(T) int { return 1 }, b.less),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.closed = true
+		return s
+	}
+	b.topics[topic] = append(b.topics[topic], s)
+	return s
+}
+
+// Publish delivers msg to every current subscription to topic. With the
+// Block policy it waits for room in each full queue in turn, and returns
+// ctx.Err() if ctx is done first, in which case later subscriptions do not
+// receive msg.
+func (b *Bus[T]) Publish(ctx context.Context, topic string, msg T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	subs := b.topics[topic]
+	b.mu.Unlock()
+	for _, s := range subs {
+		if err := s.deliver(ctx, msg, b.Policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes every subscription. Subscribers may still receive the
+// messages queued before Close.
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	topics := b.topics
+	b.topics, b.closed = nil, true
+	b.mu.Unlock()
+	for _, subs := range topics {
+		for _, s := range subs {
+			s.close()
+		}
+	}
+}
+
+// A Subscription is a subscriber's queue of messages for one topic.
+type Subscription[T any] struct {
+	b     *Bus[T]
+	topic string
+	depth int
+
+	mu      sync.Mutex
+	q       *sliceheap.BudgetHeap[T]
+	dropped int
+	closed  bool
+	avail   chan struct{} // closed when a message is queued or on close
+	space   chan struct{} // closed when a message is received or on close
+}
+
+func (s *Subscription[T]) deliver(ctx context.Context, msg T, p Policy) error {
+	s.mu.Lock()
+	for p == Block && s.q.Len() >= s.depth && !s.closed {
+		space := notify.Wait(&s.space)
+		s.mu.Unlock()
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+	switch {
+	case s.closed:
+		return nil
+	case p == DropNew && s.q.Len() >= s.depth:
+		s.dropped++
+		return nil
+	}
+	s.dropped += len(s.q.Push(msg))
+	notify.Close(&s.avail)
+	return nil
+}
+
+// Recv removes and returns the least queued message, waiting for one if the
+// queue is empty. It returns ErrClosed if the subscription is closed and
+// its queue is empty, and ctx.Err() if ctx is done first.
+func (s *Subscription[T]) Recv(ctx context.Context) (T, error) {
+	s.mu.Lock()
+	for {
+		if msg, ok := s.q.Pop(); ok {
+			notify.Close(&s.space)
+			s.mu.Unlock()
+			return msg, nil
+		}
+		if s.closed {
+			s.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		avail := notify.Wait(&s.avail)
+		s.mu.Unlock()
+		select {
+		case <-avail:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		s.mu.Lock()
+	}
+}
+
+// Len returns the number of messages queued.
+func (s *Subscription[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.q.Len()
+}
+
+// Dropped returns the number of messages dropped from or not admitted to
+// the queue because it was full.
+func (s *Subscription[T]) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close unsubscribes s. Messages already queued may still be received.
+func (s *Subscription[T]) Close() {
+	b := s.b
+	b.mu.Lock()
+	subs := b.topics[s.topic]
+	for i, t := range subs {
+		if t == s {
+			b.topics[s.topic] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.topics[s.topic]) == 0 {
+		delete(b.topics, s.topic)
+	}
+	b.mu.Unlock()
+	s.close()
+}
+
+func (s *Subscription[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	notify.Close(&s.avail)
+	notify.Close(&s.space)
+}
@@ -0,0 +1,111 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bus
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func recvAll(t *testing.T, s *Subscription[int]) []int {
+	t.Helper()
+	var got []int
+	for s.Len() > 0 {
+		x, err := s.Recv(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, x)
+	}
+	return got
+}
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+	b := New[int]()
+	a1 := b.Subscribe("alerts")
+	a2 := b.Subscribe("alerts")
+	logs := b.Subscribe("logs")
+	for _, x := range []int{3, 1, 2} {
+		b.Publish(ctx, "alerts", x)
+	}
+	b.Publish(ctx, "logs", 9)
+	b.Publish(ctx, "nobody", 0)
+
+	for _, s := range []*Subscription[int]{a1, a2} {
+		if got := recvAll(t, s); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("alerts received %v; want [1 2 3]", got)
+		}
+	}
+	a2.Close()
+	b.Publish(ctx, "alerts", 4)
+	if a2.Len() != 0 || a1.Len() != 1 {
+		t.Errorf("after unsubscribe: a1.Len() = %d, a2.Len() = %d", a1.Len(), a2.Len())
+	}
+	if _, err := a2.Recv(ctx); err != ErrClosed {
+		t.Errorf("Recv on closed subscription = %v; want ErrClosed", err)
+	}
+
+	b.Close()
+	if x, err := logs.Recv(ctx); x != 9 || err != nil {
+		t.Errorf("Recv after Close = %d, %v; want queued message", x, err)
+	}
+	if _, err := logs.Recv(ctx); err != ErrClosed {
+		t.Errorf("Recv after drain = %v; want ErrClosed", err)
+	}
+	if err := b.Publish(ctx, "logs", 1); err != ErrClosed {
+		t.Errorf("Publish after Close = %v; want ErrClosed", err)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		policy Policy
+		want   []int
+	}{
+		{DropWorst, []int{1, 2, 4}},
+		{DropNew, []int{2, 4, 5}},
+	} {
+		b := New[int]()
+		b.Depth, b.Policy = 3, tt.policy
+		s := b.Subscribe("t")
+		for _, x := range []int{5, 4, 2, 1, 9} {
+			b.Publish(ctx, "t", x)
+		}
+		if got := recvAll(t, s); !slices.Equal(got, tt.want) || s.Dropped() != 2 {
+			t.Errorf("policy %d: received %v, dropped %d; want %v, 2", tt.policy, got, s.Dropped(), tt.want)
+		}
+	}
+}
+
+func TestBlock(t *testing.T) {
+	b := New[int]()
+	b.Depth, b.Policy = 1, Block
+	s := b.Subscribe("t")
+	ctx := context.Background()
+	b.Publish(ctx, "t", 2)
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := b.Publish(short, "t", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Publish to full queue = %v; want deadline exceeded", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- b.Publish(ctx, "t", 1) }()
+	if x, _ := s.Recv(ctx); x != 2 {
+		t.Errorf("Recv = %d; want 2", x)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if x, _ := s.Recv(ctx); x != 1 {
+		t.Errorf("Recv = %d; want 1", x)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "bytes"
+
+// The *Bytes functions are like the functions without the suffix but order
+// byte slices lexically, calling [bytes.Compare] directly rather than
+// through a less function. Heaps of keys in log and record processing spend
+// most of their time comparing byte slices, which these make cheaper.
+
+// InitBytes is like [Init] for a heap of byte slices.
+func InitBytes(h [][]byte) {
+	n := len(h)
+	for i := n/2 - 1; i >= 0; i-- {
+		downBytes(h, i, n)
+	}
+	if debug {
+		check("Init", h, bytesLess)
+	}
+}
+
+// PushBytes is like [Push] for a heap of byte slices.
+func PushBytes(h *[][]byte, x []byte) {
+	*h = append(*h, x)
+	upBytes(*h, len(*h)-1)
+	if debug {
+		check("Push", *h, bytesLess)
+	}
+}
+
+// PopBytes is like [Pop] for a heap of byte slices.
+func PopBytes(h *[][]byte) []byte {
+	n := len(*h) - 1
+	if n < 0 {
+		panic("sliceheap: Pop on empty heap")
+	}
+	x := (*h)[0]
+	(*h)[0] = (*h)[n]
+	(*h)[n] = nil
+	*h = (*h)[:n]
+	downBytes(*h, 0, n)
+	if debug {
+		check("Pop", *h, bytesLess)
+	}
+	return x
+}
+
+// RemoveBytes is like [Remove] for a heap of byte slices.
+func RemoveBytes(h *[][]byte, i int) []byte {
+	n := len(*h) - 1
+	if uint(i) > uint(n) {
+		panicRange("Remove", i, n+1)
+	}
+	x := (*h)[i]
+	if n != i {
+		(*h)[i] = (*h)[n]
+		if !downBytes(*h, i, n) {
+			upBytes(*h, i)
+		}
+	}
+	(*h)[n] = nil
+	*h = (*h)[:n]
+	if debug {
+		check("Remove", *h, bytesLess)
+	}
+	return x
+}
+
+// FixBytes is like [Fix] for a heap of byte slices.
+func FixBytes(h [][]byte, i int) {
+	if uint(i) >= uint(len(h)) {
+		panicRange("Fix", i, len(h))
+	}
+	if !downBytes(h, i, len(h)) {
+		upBytes(h, i)
+	}
+	if debug {
+		check("Fix", h, bytesLess)
+	}
+}
+
+// BytesPrefixLess returns a less function ordering byte slices lexically by
+// their first n bytes only, so that slices with a common n-byte prefix are
+// equivalent. It suits records led by a fixed-length key, such as a
+// timestamp, followed by a payload that need not be compared.
+// BytesPrefixLess panics if n < 0.
+func BytesPrefixLess(n int) func(x, y []byte) bool {
+	if n < 0 {
+		panic("sliceheap: BytesPrefixLess length < 0")
+	}
+	return func(x, y []byte) bool {
+		return bytes.Compare(x[:min(n, len(x))], y[:min(n, len(y))]) < 0
+	}
+}
+
+func bytesLess(x, y []byte) bool {
+	return bytes.Compare(x, y) < 0
+}
+
+func upBytes(h [][]byte, j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || bytes.Compare(h[j], h[i]) >= 0 {
+			break
+		}
+		h[i], h[j] = h[j], h[i]
+		j = i
+	}
+}
+
+func downBytes(h [][]byte, i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && bytes.Compare(h[j2], h[j1]) < 0 {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if bytes.Compare(h[j], h[i]) >= 0 {
+			break
+		}
+		h[i], h[j] = h[j], h[i]
+		i = j
+	}
+	return i > i0
+}
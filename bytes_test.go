@@ -0,0 +1,80 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	var h [][]byte
+	var want [][]byte
+	for i := range 200 {
+		b := fmt.Appendf(nil, "key%d", rand.Intn(100))
+		want = append(want, b)
+		if i < 100 {
+			h = append(h, b)
+			if i == 99 {
+				InitBytes(h)
+			}
+		} else {
+			PushBytes(&h, b)
+		}
+	}
+	RemoveBytes(&h, 17)
+	h[5] = []byte("a")
+	FixBytes(h, 5)
+	if err := VerifyFunc(h, bytesLess); err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][]byte
+	for len(h) > 0 {
+		got = append(got, PopBytes(&h))
+	}
+	if len(got) != 199 || !bytes.Equal(got[0], []byte("a")) || !slices.IsSortedFunc(got, bytes.Compare) {
+		t.Errorf("popped %q; want 199 slices in order starting with a", got)
+	}
+}
+
+func TestBytesPrefixLess(t *testing.T) {
+	less := BytesPrefixLess(2)
+	if less([]byte("abz"), []byte("aby")) || less([]byte("aby"), []byte("abz")) {
+		t.Error("slices with a common prefix not equivalent")
+	}
+	if !less([]byte("a"), []byte("ab")) || !less([]byte("aa"), []byte("ab")) {
+		t.Error("BytesPrefixLess misordered")
+	}
+}
+
+func TestKeyOrder(t *testing.T) {
+	var h []Pair[string, int]
+	for i, k := range []string{"c", "a", "b"} {
+		PushWith[KeyOrder[string, int]](&h, Pair[string, int]{k, i})
+	}
+	if p := PopWith[KeyOrder[string, int]](&h); p.Key != "a" || p.Value != 1 {
+		t.Errorf("PopWith = %v; want {a 1}", p)
+	}
+}
+
+func BenchmarkPushPopBytes(b *testing.B) {
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "record/%08d", rand.Intn(1e8))
+	}
+	h := make([][]byte, 0, len(keys))
+	for range b.N {
+		for _, k := range keys {
+			PushBytes(&h, k)
+		}
+		for len(h) > 0 {
+			PopBytes(&h)
+		}
+	}
+}
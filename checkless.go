@@ -0,0 +1,86 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An OrderError reports that a less function is not a strict weak order,
+// giving the property violated and a counterexample.
+type OrderError struct {
+	Property string // "irreflexivity", "asymmetry", "transitivity", or "transitivity of equivalence"
+	Values   []any  // the counterexample, named a, b, c in the message
+}
+
+func (e *OrderError) Error() string {
+	var detail string
+	switch e.Property {
+	case "irreflexivity":
+		detail = "less(a, a)"
+	case "asymmetry":
+		detail = "less(a, b) and less(b, a)"
+	case "transitivity":
+		detail = "less(a, b) and less(b, c) but not less(a, c)"
+	case "transitivity of equivalence":
+		detail = "a ~ b and b ~ c but not a ~ c (x ~ y when neither is less)"
+	}
+	var vals []string
+	for i, v := range e.Values {
+		vals = append(vals, fmt.Sprintf("%c = %v", 'a'+i, v))
+	}
+	return fmt.Sprintf("sliceheap: less violates %s: %s for %s", e.Property, detail, strings.Join(vals, ", "))
+}
+
+// CheckLess checks that less is a strict weak order over samples, as the
+// heap operations require, and returns an *OrderError with a counterexample
+// if it is not. It is intended for tests of custom less functions, which
+// corrupt heaps silently when they are inconsistent. The samples should
+// include values that compare equal.
+// The complexity is O(n^3) where n = len(samples), with n^2 calls to less.
+func CheckLess[T any](less func(x, y T) bool, samples []T) error {
+	n := len(samples)
+	lt := make([]bool, n*n) // lt[i*n+j] = less(samples[i], samples[j])
+	for i := range samples {
+		for j := range samples {
+			lt[i*n+j] = less(samples[i], samples[j])
+		}
+	}
+	fail := func(property string, idx ...int) error {
+		e := &OrderError{Property: property}
+		for _, i := range idx {
+			e.Values = append(e.Values, samples[i])
+		}
+		return e
+	}
+	eq := func(i, j int) bool { return !lt[i*n+j] && !lt[j*n+i] }
+
+	for i := range n {
+		if lt[i*n+i] {
+			return fail("irreflexivity", i)
+		}
+	}
+	for i := range n {
+		for j := range n {
+			if lt[i*n+j] && lt[j*n+i] {
+				return fail("asymmetry", i, j)
+			}
+		}
+	}
+	for i := range n {
+		for j := range n {
+			for k := range n {
+				if lt[i*n+j] && lt[j*n+k] && !lt[i*n+k] {
+					return fail("transitivity", i, j, k)
+				}
+				if eq(i, j) && eq(j, k) && !eq(i, k) {
+					return fail("transitivity of equivalence", i, j, k)
+				}
+			}
+		}
+	}
+	return nil
+}
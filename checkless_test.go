@@ -0,0 +1,52 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCheckLess(t *testing.T) {
+	ints := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	if err := CheckLess(cmp.Less[int], ints); err != nil {
+		t.Errorf("CheckLess(cmp.Less) = %v", err)
+	}
+	floats := []float64{1, math.NaN(), 0, math.Copysign(0, -1), math.Inf(1)}
+	if err := CheckLess(TotalLess[float64], floats); err != nil {
+		t.Errorf("CheckLess(TotalLess) = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		less     func(x, y float64) bool
+		samples  []float64
+		property string
+	}{
+		{"LessOrEqual", func(x, y float64) bool { return x <= y }, []float64{1, 2}, "irreflexivity"},
+		{"NotEqual", func(x, y float64) bool { return x != y }, []float64{1, 2}, "asymmetry"},
+		{"RockPaperScissors", func(x, y float64) bool { return int(y-x+3)%3 == 1 }, []float64{0, 1, 2}, "transitivity"},
+		{"NaN", func(x, y float64) bool { return x < y }, []float64{1, math.NaN(), 2}, "transitivity of equivalence"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLess(tt.less, tt.samples)
+			var oe *OrderError
+			if !errors.As(err, &oe) || oe.Property != tt.property {
+				t.Fatalf("CheckLess = %v; want %s violation", err, tt.property)
+			}
+		})
+	}
+}
+
+func TestOrderErrorMessage(t *testing.T) {
+	err := CheckLess(func(x, y int) bool { return x <= y }, []int{7})
+	want := "sliceheap: less violates irreflexivity: less(a, a) for a = 7"
+	if err == nil || err.Error() != want {
+		t.Errorf("CheckLess = %v; want %q", err, want)
+	}
+}
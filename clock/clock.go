@@ -0,0 +1,46 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package clock abstracts the passage of time for the time-based queues in
+// this module, so that their behavior can be tested deterministically with
+// a Fake clock.
+package clock
+
+import "time"
+
+// A Clock tells the time and creates timers.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer is a single event, like a *time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer
+	// fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, and reports whether it was
+	// active.
+	Stop() bool
+
+	// Reset changes the timer to fire after d, and reports whether it was
+	// active.
+	Reset(d time.Duration) bool
+}
+
+// Real is the Clock backed by package time.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
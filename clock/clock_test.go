@@ -0,0 +1,75 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func fired(t Timer) bool {
+	select {
+	case <-t.C():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFake(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(t0)
+	a := f.NewTimer(time.Second)
+	b := f.NewTimer(3 * time.Second)
+	c := f.NewTimer(2 * time.Second)
+	if f.Pending() != 3 {
+		t.Fatalf("Pending() = %d; want 3", f.Pending())
+	}
+
+	f.Advance(999 * time.Millisecond)
+	if fired(a) {
+		t.Error("timer fired early")
+	}
+	f.Advance(time.Millisecond)
+	if !fired(a) || fired(c) {
+		t.Error("after 1s, want exactly the 1s timer fired")
+	}
+	if !c.Stop() || c.Stop() {
+		t.Error("Stop of pending timer should report true once")
+	}
+	if !b.Reset(10*time.Second) || f.Pending() != 1 {
+		t.Errorf("Reset of pending timer = false or Pending() = %d", f.Pending())
+	}
+	f.Advance(5 * time.Second)
+	if fired(b) || fired(c) {
+		t.Error("reset or stopped timer fired")
+	}
+	f.Advance(5 * time.Second)
+	if !fired(b) {
+		t.Error("reset timer did not fire")
+	}
+	if got := f.Now(); !got.Equal(t0.Add(11 * time.Second)) {
+		t.Errorf("Now() = %v; want t0+11s", got)
+	}
+
+	if d := f.NewTimer(0); !fired(d) {
+		t.Error("timer with zero duration did not fire at once")
+	}
+}
+
+func TestReal(t *testing.T) {
+	if d := time.Since(Real.Now()); d < 0 || d > time.Minute {
+		t.Errorf("Real.Now() is %v from time.Now()", d)
+	}
+	tm := Real.NewTimer(time.Millisecond)
+	select {
+	case <-tm.C():
+	case <-time.After(5 * time.Second):
+		t.Fatal("real timer did not fire")
+	}
+	if tm.Stop() {
+		t.Error("Stop of fired timer reported true")
+	}
+}
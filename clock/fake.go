@@ -0,0 +1,132 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Fake is a Clock whose time changes only when it is advanced. Timers
+// fire, in order of their deadlines, when Advance moves the time past them.
+//
+// A Fake is safe for concurrent use by multiple goroutines.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer // min-heap of pending timers
+	seq    uint64
+}
+
+type fakeTimer struct {
+	f     *Fake
+	c     chan time.Time
+	when  time.Time
+	seq   uint64 // tie-breaker: creation or reset order
+	index int    // index in f.timers, or -1 if not pending
+}
+
+func timerLess(x, y *fakeTimer) bool {
+	if !x.when.Equal(y.when) {
+		return x.when.Before(y.when)
+	}
+	return x.seq < y.seq
+}
+
+func setIndex(t *fakeTimer, i int) {
+	t.index = i
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the clock's current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer returns a timer that fires once the clock has advanced by d. A
+// timer with d <= 0 fires at once.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{f: f, c: make(chan time.Time, 1), index: -1}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schedule(t, d)
+	return t
+}
+
+// Pending returns the number of timers that have not yet fired or been
+// stopped.
+func (f *Fake) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+// Advance moves the clock forward by d, firing the timers that fall due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.fire()
+}
+
+func (f *Fake) schedule(t *fakeTimer, d time.Duration) {
+	t.when = f.now.Add(d)
+	t.seq = f.seq
+	f.seq++
+	indexheap.Push(&f.timers, t, timerLess, setIndex)
+	f.fire()
+}
+
+func (f *Fake) fire() {
+	for len(f.timers) > 0 && !f.timers[0].when.After(f.now) {
+		t := indexheap.Pop(&f.timers, timerLess, setIndex)
+		select {
+		case t.c <- f.now:
+		default:
+		}
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	f := t.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return t.stop()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	active := t.stop()
+	f.schedule(t, d)
+	return active
+}
+
+// stop removes the timer from the heap and discards any undelivered time,
+// as Stop does for a *time.Timer since Go 1.23.
+func (t *fakeTimer) stop() bool {
+	select {
+	case <-t.c:
+	default:
+	}
+	if t.index < 0 {
+		return false
+	}
+	indexheap.Remove(&t.f.timers, t.index, timerLess, setIndex)
+	return true
+}
@@ -0,0 +1,282 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cmps provides less functions for common types that are not
+// [cmp.Ordered], for use with the *Func functions of package sliceheap.
+// Each defines a strict weak order.
+package cmps
+
+import (
+	"bytes"
+	"math/big"
+	"net/netip"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FoldLess orders strings ignoring case, comparing them rune by rune under
+// Unicode simple case folding, as [strings.EqualFold] does. Strings that
+// differ only in case are equivalent.
+func FoldLess(x, y string) bool {
+	for x != "" && y != "" {
+		var r, s rune
+		if x[0] < utf8.RuneSelf && y[0] < utf8.RuneSelf {
+			r, s = rune(x[0]), rune(y[0])
+			x, y = x[1:], y[1:]
+		} else {
+			var n, m int
+			r, n = utf8.DecodeRuneInString(x)
+			s, m = utf8.DecodeRuneInString(y)
+			x, y = x[n:], y[m:]
+		}
+		if r, s = fold(r), fold(s); r != s {
+			return r < s
+		}
+	}
+	return x == "" && y != ""
+}
+
+// fold maps r to a single representative of its case folding orbit, the
+// least rune in it.
+func fold(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		if 'a' <= r && r <= 'z' {
+			return r - ('a' - 'A')
+		}
+		return r
+	}
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		m = min(m, f)
+	}
+	return m
+}
+
+// BytesLess orders byte slices lexically, as [bytes.Compare] does. A nil
+// slice is equivalent to an empty one.
+func BytesLess(x, y []byte) bool {
+	return bytes.Compare(x, y) < 0
+}
+
+// AddrLess orders IP addresses as [netip.Addr.Less] does: the zero Addr
+// first, then IPv4 before IPv6 addresses, then by address and zone.
+func AddrLess(x, y netip.Addr) bool {
+	return x.Less(y)
+}
+
+// PrefixLess orders IP prefixes by address, then by length, shorter first.
+func PrefixLess(x, y netip.Prefix) bool {
+	if c := x.Addr().Compare(y.Addr()); c != 0 {
+		return c < 0
+	}
+	return x.Bits() < y.Bits()
+}
+
+// TimeLess orders times by the instant they represent, so that times in
+// different locations, or with and without a monotonic clock reading,
+// compare correctly, unlike comparing [time.Time] values with ==.
+func TimeLess(x, y time.Time) bool {
+	return x.Before(y)
+}
+
+// DurationLess orders durations, shortest first.
+func DurationLess(x, y time.Duration) bool {
+	return x < y
+}
+
+// BigIntLess orders integers by value. A nil *big.Int orders before all
+// others.
+func BigIntLess(x, y *big.Int) bool {
+	if x == nil || y == nil {
+		return x == nil && y != nil
+	}
+	return x.Cmp(y) < 0
+}
+
+// BigFloatLess orders floating-point numbers by value, with -0 and +0
+// equivalent. A nil *big.Float orders before all others.
+func BigFloatLess(x, y *big.Float) bool {
+	if x == nil || y == nil {
+		return x == nil && y != nil
+	}
+	return x.Cmp(y) < 0
+}
+
+// BigRatLess orders rational numbers by value. A nil *big.Rat orders before
+// all others.
+func BigRatLess(x, y *big.Rat) bool {
+	if x == nil || y == nil {
+		return x == nil && y != nil
+	}
+	return x.Cmp(y) < 0
+}
+
+// SemverLess orders semantic versions by precedence as defined by Semantic
+// Versioning 2.0.0: by major, minor and patch number, then with a
+// pre-release version before the release, and pre-release versions by
+// their dot-separated identifiers, numeric ones numerically and before
+// alphanumeric ones. Build metadata is ignored, so versions differing only
+// in it are equivalent. A leading "v", as in "v1.2.3", is allowed.
+//
+// Invalid versions order before all valid ones, and lexically among
+// themselves.
+func SemverLess(x, y string) bool {
+	vx, okx := parseSemver(x)
+	vy, oky := parseSemver(y)
+	if !okx || !oky {
+		if okx != oky {
+			return !okx
+		}
+		return x < y
+	}
+	for i := range 3 {
+		if c := compareNum(vx.core[i], vy.core[i]); c != 0 {
+			return c < 0
+		}
+	}
+	if vx.pre == "" || vy.pre == "" {
+		return vx.pre != "" && vy.pre == ""
+	}
+	px, py := strings.Split(vx.pre, "."), strings.Split(vy.pre, ".")
+	for i := 0; i < len(px) && i < len(py); i++ {
+		a, b := px[i], py[i]
+		na, nb := isNum(a), isNum(b)
+		switch {
+		case na && nb:
+			if c := compareNum(a, b); c != 0 {
+				return c < 0
+			}
+		case na != nb:
+			return na
+		case a != b:
+			return a < b
+		}
+	}
+	return len(px) < len(py)
+}
+
+type semver struct {
+	core [3]string // major, minor, patch, without leading zeros
+	pre  string
+}
+
+func parseSemver(v string) (semver, bool) {
+	var s semver
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		if !validIdents(v[i+1:], false) {
+			return s, false
+		}
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		s.pre = v[i+1:]
+		if !validIdents(s.pre, true) {
+			return s, false
+		}
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return s, false
+	}
+	for i, p := range parts {
+		if !isNum(p) || len(p) > 1 && p[0] == '0' {
+			return s, false
+		}
+		s.core[i] = p
+	}
+	return s, true
+}
+
+// validIdents reports whether s is a non-empty dot-separated list of
+// non-empty identifiers of ASCII alphanumerics and hyphens. If pre is set,
+// numeric identifiers must not have leading zeros.
+func validIdents(s string, pre bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for i := 0; i < len(id); i++ {
+			c := id[i]
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+		if pre && isNum(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNum compares decimal numerals without leading zeros, of any
+// length.
+func compareNum(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// NaturalLess orders strings in natural order, in which runs of decimal
+// digits compare by numeric value, so that "file2" orders before "file10".
+// Other bytes compare as by x < y. Strings that differ only in the leading
+// zeros of their numbers, such as "a01" and "a1", order by x < y.
+func NaturalLess(x, y string) bool {
+	a, b := x, y
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			i, j := digits(a), digits(b)
+			na := strings.TrimLeft(a[:i], "0")
+			nb := strings.TrimLeft(b[:j], "0")
+			if c := compareNum(na, nb); c != 0 {
+				return c < 0
+			}
+			a, b = a[i:], b[j:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	if a != "" || b != "" {
+		return a == ""
+	}
+	return x < y
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// digits returns the length of the run of digits at the start of s.
+func digits(s string) int {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,158 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmps
+
+import (
+	"math/big"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap"
+)
+
+// sorted pops every element of xs through a heap ordered by less.
+func sorted[T any](xs []T, less func(x, y T) bool) []T {
+	h := slices.Clone(xs)
+	sliceheap.InitFunc(h, less)
+	var out []T
+	for len(h) > 0 {
+		out = append(out, sliceheap.PopFunc(&h, less))
+	}
+	return out
+}
+
+func TestCheckLess(t *testing.T) {
+	for name, err := range map[string]error{
+		"Fold":     sliceheap.CheckLess(FoldLess, []string{"", "a", "A", "b", "ab", "AB", "Straße", "STRASSE", "ǅ", "ǆ", "Ǆ", "k", "K", "K"}),
+		"Bytes":    sliceheap.CheckLess(BytesLess, [][]byte{nil, {}, {0}, {1}, {0, 1}, {255}}),
+		"Addr":     sliceheap.CheckLess(AddrLess, []netip.Addr{{}, netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1"), netip.MustParseAddr("fe80::1%eth0"), netip.MustParseAddr("1.2.3.4")}),
+		"BigInt":   sliceheap.CheckLess(BigIntLess, []*big.Int{nil, big.NewInt(-5), big.NewInt(0), big.NewInt(7), new(big.Int).Lsh(big.NewInt(1), 100)}),
+		"BigFloat": sliceheap.CheckLess(BigFloatLess, []*big.Float{nil, big.NewFloat(-1), big.NewFloat(0), new(big.Float).Neg(big.NewFloat(0)), big.NewFloat(2.5)}),
+		"Semver":   sliceheap.CheckLess(SemverLess, []string{"1.0.0", "v1.0.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0+build", "2.0.0", "bad", "01.0.0", "1.10.0", "1.2.0"}),
+	} {
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestFoldLess(t *testing.T) {
+	got := sorted([]string{"banana", "Apple", "cherry", "apple2", "BANANA", "a"}, FoldLess)
+	want := []string{"a", "Apple", "apple2", "banana", "BANANA", "cherry"}
+	for i := range got {
+		if FoldLess(got[i], want[i]) || FoldLess(want[i], got[i]) {
+			t.Fatalf("sorted = %q; want %q up to case", got, want)
+		}
+	}
+	if FoldLess("K", "K") || FoldLess("K", "k") {
+		t.Error("Kelvin sign not equivalent to K")
+	}
+}
+
+func TestTimeLess(t *testing.T) {
+	now := time.Now()
+	utc := now.UTC() // same instant, different location, no monotonic reading
+	if TimeLess(now, utc) || TimeLess(utc, now) {
+		t.Error("same instant in different locations not equivalent")
+	}
+	if !TimeLess(now, now.Add(time.Nanosecond)) {
+		t.Error("TimeLess(now, now+1ns) = false")
+	}
+	if !DurationLess(time.Second, time.Minute) {
+		t.Error("DurationLess(1s, 1m) = false")
+	}
+}
+
+func TestPrefixLess(t *testing.T) {
+	in := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/16"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("9.0.0.0/8"),
+	}
+	got := sorted(in, PrefixLess)
+	if got[0] != in[2] || got[1] != in[1] || got[2] != in[0] {
+		t.Errorf("sorted = %v", got)
+	}
+}
+
+func TestSemverLess(t *testing.T) {
+	// The precedence example from the Semantic Versioning specification.
+	want := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0",
+		"v1.9.0", "1.10.0", "2.0.0", "10.0.0",
+	}
+	in := slices.Clone(want)
+	slices.Reverse(in)
+	if got := sorted(in, SemverLess); !slices.Equal(got, want) {
+		t.Errorf("sorted = %q; want %q", got, want)
+	}
+
+	if SemverLess("1.0.0+a", "1.0.0+b") || SemverLess("1.0.0+b", "1.0.0+a") {
+		t.Error("build metadata affects precedence")
+	}
+	for _, bad := range []string{"1.0", "1.0.0-", "1.0.0-01", "01.0.0", "1.0.0+", "x.y.z"} {
+		if !SemverLess(bad, "0.0.0") {
+			t.Errorf("invalid version %q not ordered first", bad)
+		}
+	}
+	if !SemverLess("99999999999999999999.0.0", "100000000000000000000.0.0") {
+		t.Error("long numeric versions compared lexically")
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	want := []string{
+		"", "file", "file01", "file1", "file2", "file2a", "file2b", "file010",
+		"file10", "file10.txt", "host9.example", "host10.example", "x99999999999999999999", "x100000000000000000000",
+	}
+	in := slices.Clone(want)
+	slices.Reverse(in)
+	if got := sorted(in, NaturalLess); !slices.Equal(got, want) {
+		t.Errorf("sorted = %q; want %q", got, want)
+	}
+	if err := sliceheap.CheckLess(NaturalLess, want); err != nil {
+		t.Error(err)
+	}
+}
+
+// accentCollator orders strings ignoring a trailing acute accent, then
+// unaccented first, standing in for a language-specific collation.
+type accentCollator struct{ calls int }
+
+func (c *accentCollator) CompareString(a, b string) int {
+	c.calls++
+	strip := func(s string) (string, int) {
+		if t, ok := strings.CutSuffix(s, "́"); ok {
+			return t, 1
+		}
+		return s, 0
+	}
+	a0, ax := strip(a)
+	b0, bx := strip(b)
+	if c := strings.Compare(a0, b0); c != 0 {
+		return c
+	}
+	return ax - bx
+}
+
+func TestCollateLess(t *testing.T) {
+	c := new(accentCollator)
+	less := CollateLess(c)
+	got := sorted([]string{"f", "é", "e", "d"}, less)
+	if want := []string{"d", "e", "é", "f"}; !slices.Equal(got, want) {
+		t.Errorf("sorted = %q; want %q", got, want)
+	}
+	if c.calls == 0 {
+		t.Error("collator not used")
+	}
+
+	if l := CompareLess(time.Time.Compare); !l(time.Unix(1, 0), time.Unix(2, 0)) {
+		t.Error("CompareLess(time.Time.Compare) misordered")
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmps
+
+// A Collator compares strings by the rules of a language, returning a
+// negative number, zero or a positive number as a orders before, the same
+// as, or after b. A *collate.Collator from golang.org/x/text/collate
+// satisfies Collator.
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+// CollateLess returns a less function that orders strings as c does, so
+// that a heap of user-visible strings orders them correctly for a locale.
+//
+// A *collate.Collator reuses internal buffers across comparisons and so is
+// not safe for concurrent use; nor is the returned function, when c is one.
+// Each comparison recomputes the collation elements of both strings. When
+// strings are compared many times, as in a large heap, it is faster to
+// compute each string's sort key once, with the collator's KeyFromString,
+// and order by key with [BytesLess].
+func CollateLess(c Collator) func(x, y string) bool {
+	return CompareLess(c.CompareString)
+}
+
+// CompareLess returns a less function that reports whether compare(x, y)
+// < 0, adapting three-way comparison functions such as [strings.Compare]
+// or [time.Time.Compare] to the *Func functions of package sliceheap.
+func CompareLess[T any](compare func(x, y T) int) func(x, y T) bool {
+	return func(x, y T) bool {
+		return compare(x, y) < 0
+	}
+}
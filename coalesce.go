@@ -0,0 +1,122 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "time"
+
+// A Coalescer merges events for the same key that arrive within a window
+// and releases one merged event per key when its deadline passes, as
+// notification and cache-invalidation pipelines do to avoid acting on every
+// event of a burst. Deadlines are kept in a [DeadlineHeap].
+//
+// By default a key's deadline is Window after its first pending event. If
+// Latest is set, each event instead pushes the deadline back to Window
+// after it, debouncing the key until it has been quiet for Window or,
+// if MaxWait is set, until MaxWait after its first pending event.
+//
+// The exported fields configure the coalescer and must not be changed while
+// events are pending. The zero value releases each event as soon as
+// Ready is called, keeping the latest event for a key.
+// A Coalescer is not safe for concurrent use.
+type Coalescer[K comparable, E any] struct {
+	Window  time.Duration
+	Latest  bool
+	MaxWait time.Duration
+
+	// Merge combines a pending event with a new one for the same key. If
+	// nil, the new event replaces the pending one.
+	Merge func(pending, event E) E
+
+	pending map[K]*coalesced[E]
+	d       DeadlineHeap[coalesceRef[K]]
+}
+
+type coalesced[E any] struct {
+	event E
+	first time.Time
+	at    time.Time
+	gen   uint64
+}
+
+// A coalesceRef refers to the deadline of a pending key as of a given
+// generation. When the deadline moves, a new reference is pushed and the
+// old one is skipped as stale when it reaches the top of the heap.
+type coalesceRef[K comparable] struct {
+	key K
+	gen uint64
+}
+
+// Len returns the number of keys with a pending event.
+func (c *Coalescer[K, E]) Len() int {
+	return len(c.pending)
+}
+
+// Add records event for key at time now, merging it with any event
+// pending for key.
+// The complexity is O(log n) where n is the number of deadlines held.
+func (c *Coalescer[K, E]) Add(key K, event E, now time.Time) {
+	if c.pending == nil {
+		c.pending = make(map[K]*coalesced[E])
+	}
+	p, ok := c.pending[key]
+	if !ok {
+		p = &coalesced[E]{event: event, first: now, at: now.Add(c.Window)}
+		c.pending[key] = p
+		c.d.PushAt(p.at, coalesceRef[K]{key, p.gen})
+		return
+	}
+	if c.Merge != nil {
+		p.event = c.Merge(p.event, event)
+	} else {
+		p.event = event
+	}
+	if !c.Latest {
+		return
+	}
+	at := now.Add(c.Window)
+	if c.MaxWait > 0 {
+		if limit := p.first.Add(c.MaxWait); at.After(limit) {
+			at = limit
+		}
+	}
+	if at.After(p.at) {
+		p.at = at
+		p.gen++
+		c.d.PushAt(p.at, coalesceRef[K]{key, p.gen})
+	}
+}
+
+// stale reports whether r no longer refers to the current deadline of a
+// pending key.
+func (c *Coalescer[K, E]) stale(r coalesceRef[K]) bool {
+	p, ok := c.pending[r.key]
+	return !ok || p.gen != r.gen
+}
+
+// NextDeadline returns the earliest time at which a merged event will be
+// ready, and reports false if no events are pending.
+// The complexity is O(1), amortized over stale deadlines discarded.
+func (c *Coalescer[K, E]) NextDeadline() (time.Time, bool) {
+	for len(c.d.h) > 0 && c.stale(c.d.h[0].item) {
+		PopFunc(&c.d.h, deadlineLess[coalesceRef[K]])
+	}
+	return c.d.NextDeadline()
+}
+
+// Ready removes and returns the merged events whose deadlines are at or
+// before now, earliest first.
+// The complexity is O(k log n) where k is the number of deadlines passed
+// and n is the number held.
+func (c *Coalescer[K, E]) Ready(now time.Time) []Pair[K, E] {
+	var r []Pair[K, E]
+	for _, ref := range c.d.PopExpired(now) {
+		if c.stale(ref) {
+			continue
+		}
+		r = append(r, Pair[K, E]{ref.key, c.pending[ref.key].event})
+		delete(c.pending, ref.key)
+	}
+	return r
+}
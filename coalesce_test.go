@@ -0,0 +1,64 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCoalescer(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	c := Coalescer[string, []int]{
+		Window: 10 * time.Second,
+		Merge:  func(p, e []int) []int { return append(p, e...) },
+	}
+	c.Add("a", []int{1}, at(0))
+	c.Add("b", []int{2}, at(3))
+	c.Add("a", []int{3}, at(8)) // merged; deadline stays at 10s
+	if d, _ := c.NextDeadline(); !d.Equal(at(10)) {
+		t.Errorf("NextDeadline() = %v; want 10s", d)
+	}
+	got := c.Ready(at(10))
+	if len(got) != 1 || got[0].Key != "a" || !slices.Equal(got[0].Value, []int{1, 3}) {
+		t.Errorf("Ready(10s) = %v; want [{a [1 3]}]", got)
+	}
+	c.Add("a", []int{4}, at(11)) // a new burst for a
+	got = c.Ready(at(30))
+	if len(got) != 2 || got[0].Key != "b" || got[1].Key != "a" || c.Len() != 0 {
+		t.Errorf("Ready(30s) = %v; want b then a", got)
+	}
+}
+
+func TestCoalescerLatest(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	c := Coalescer[string, int]{Window: 5 * time.Second, Latest: true, MaxWait: 12 * time.Second}
+	c.Add("k", 1, at(0))
+	c.Add("k", 2, at(3)) // deadline 8s
+	if got := c.Ready(at(5)); got != nil {
+		t.Errorf("Ready(5s) = %v before quiet period", got)
+	}
+	c.Add("k", 3, at(7))  // deadline 12s, the MaxWait limit
+	c.Add("k", 4, at(10)) // would be 15s; held at 12s
+	if d, _ := c.NextDeadline(); !d.Equal(at(12)) {
+		t.Errorf("NextDeadline() = %v; want 12s", d)
+	}
+	if got := c.Ready(at(12)); len(got) != 1 || got[0].Value != 4 {
+		t.Errorf("Ready(12s) = %v; want [{k 4}]", got)
+	}
+	if _, ok := c.NextDeadline(); ok {
+		t.Error("NextDeadline reported a deadline with nothing pending")
+	}
+
+	var zero Coalescer[int, int]
+	zero.Add(1, 1, t0)
+	zero.Add(1, 2, t0)
+	if got := zero.Ready(t0); len(got) != 1 || got[0].Value != 2 {
+		t.Errorf("zero Coalescer Ready = %v; want [{1 2}]", got)
+	}
+}
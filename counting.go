@@ -0,0 +1,171 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// CountingLess returns a less function that calls less and increments *n
+// each time it is called. Passing it to the Func variants measures the
+// number of comparisons made by an operation, for example when
+// investigating the cost of an expensive comparator; reset *n to zero
+// between operations to count them separately. To count swaps and sift
+// depth as well, use a [Counter].
+//
+// The returned function is not safe for concurrent use.
+func CountingLess[T any](less func(x, y T) bool, n *int) func(x, y T) bool {
+	return func(x, y T) bool {
+		*n++
+		return less(x, y)
+	}
+}
+
+// OpStats counts the work done by heap operations.
+type OpStats struct {
+	Comparisons int // calls to the less function
+	Swaps       int // exchanges of two elements
+	Depth       int // the most levels any element moved in one sift
+}
+
+func (s *OpStats) add(t OpStats) {
+	s.Comparisons += t.Comparisons
+	s.Swaps += t.Swaps
+	s.Depth = max(s.Depth, t.Depth)
+}
+
+// A Counter performs heap operations on a slice, as the Func functions do,
+// and counts the comparisons, swaps and sift depth of each. Last holds the
+// counts for the most recent operation and Total the sums since the
+// Counter was created or Total was last reset; Total.Depth is the greatest
+// depth of any operation.
+//
+// The heap may be changed by other means between operations.
+type Counter[T any] struct {
+	Last, Total OpStats
+
+	h    *[]T
+	less func(x, y T) bool
+	sw   SwapperHeap
+}
+
+// NewCounter returns a counter for operations on the heap *h.
+func NewCounter[T cmp.Ordered](h *[]T) *Counter[T] {
+	return NewCounterFunc(h, cmp.Less[T])
+}
+
+// NewCounterFunc is like [NewCounter] but uses a less function to compare elements.
+func NewCounterFunc[T any](h *[]T, less func(x, y T) bool) *Counter[T] {
+	c := &Counter[T]{h: h, less: less}
+	c.sw = SwapperHeap{Less: c.lessAt, Swap: c.swap}
+	return c
+}
+
+// Len returns the number of elements in the counter's heap.
+func (c *Counter[T]) Len() int {
+	return len(*c.h)
+}
+
+// Init is like [InitFunc] for the counter's heap.
+func (c *Counter[T]) Init() {
+	c.begin()
+	n := len(*c.h)
+	for i := n/2 - 1; i >= 0; i-- {
+		c.down(i, n)
+	}
+	c.end()
+}
+
+// Push is like [PushFunc] for the counter's heap.
+func (c *Counter[T]) Push(x T) {
+	c.begin()
+	*c.h = append(*c.h, x)
+	c.up(len(*c.h) - 1)
+	c.end()
+}
+
+// Pop is like [PopFunc] for the counter's heap.
+func (c *Counter[T]) Pop() T {
+	if len(*c.h) == 0 {
+		panic("sliceheap: Pop on empty heap")
+	}
+	return c.Remove(0)
+}
+
+// Peek returns the least element of the counter's heap without removing
+// it. It makes no comparisons.
+// Peek panics if the heap is empty.
+func (c *Counter[T]) Peek() T {
+	if len(*c.h) == 0 {
+		panic("sliceheap: Peek on empty heap")
+	}
+	return (*c.h)[0]
+}
+
+// Remove is like [RemoveFunc] for the counter's heap.
+func (c *Counter[T]) Remove(i int) T {
+	h := *c.h
+	n := len(h) - 1
+	if uint(i) > uint(n) {
+		panicRange("Remove", i, n+1)
+	}
+	c.begin()
+	x := h[i]
+	if n != i {
+		h[i] = h[n]
+		if !c.down(i, n) {
+			c.up(i)
+		}
+	}
+	var zero T
+	h[n] = zero
+	*c.h = h[:n]
+	c.end()
+	return x
+}
+
+// Fix is like [FixFunc] for the counter's heap.
+func (c *Counter[T]) Fix(i int) {
+	if n := len(*c.h); uint(i) >= uint(n) {
+		panicRange("Fix", i, n)
+	}
+	c.begin()
+	if !c.down(i, len(*c.h)) {
+		c.up(i)
+	}
+	c.end()
+}
+
+func (c *Counter[T]) begin() {
+	c.Last = OpStats{}
+}
+
+func (c *Counter[T]) end() {
+	c.Total.add(c.Last)
+}
+
+func (c *Counter[T]) lessAt(i, j int) bool {
+	c.Last.Comparisons++
+	return c.less((*c.h)[i], (*c.h)[j])
+}
+
+func (c *Counter[T]) swap(i, j int) {
+	h := *c.h
+	h[i], h[j] = h[j], h[i]
+	c.Last.Swaps++
+}
+
+// up and down sift through the SwapperHeap loops. An element moves one
+// level per swap, so the depth of a sift is the number of swaps it made.
+func (c *Counter[T]) up(j int) {
+	swaps := c.Last.Swaps
+	c.sw.up(j)
+	c.Last.Depth = max(c.Last.Depth, c.Last.Swaps-swaps)
+}
+
+func (c *Counter[T]) down(i, n int) bool {
+	swaps := c.Last.Swaps
+	moved := c.sw.down(i, n)
+	c.Last.Depth = max(c.Last.Depth, c.Last.Swaps-swaps)
+	return moved
+}
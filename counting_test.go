@@ -0,0 +1,77 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestCountingLess(t *testing.T) {
+	if debug {
+		t.Skip("debug checks make additional comparisons")
+	}
+	var n int
+	less := CountingLess(cmp.Less[int], &n)
+
+	var h []int
+	for i := 1023; i >= 0; i-- {
+		n = 0
+		PushFunc(&h, i, less)
+		// Each new minimum rises to the root, one comparison per level.
+		depth := 0
+		for m := len(h); m > 1; m /= 2 {
+			depth++
+		}
+		if n != depth {
+			t.Fatalf("Push of new minimum onto %d elements made %d comparisons; want %d", len(h)-1, n, depth)
+		}
+	}
+
+	n = 0
+	PopFunc(&h, less)
+	if n == 0 || n > 2*10 {
+		t.Errorf("Pop from 1024 elements made %d comparisons; want at most 20", n)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	var h []int
+	c := NewCounter(&h)
+	for i := 1023; i >= 0; i-- {
+		c.Push(i)
+		// Each new minimum rises to the root, one comparison and one swap
+		// per level.
+		depth := 0
+		for m := len(h); m > 1; m /= 2 {
+			depth++
+		}
+		if want := (OpStats{depth, depth, depth}); c.Last != want {
+			t.Fatalf("Push of new minimum onto %d elements: %+v; want %+v", len(h)-1, c.Last, want)
+		}
+	}
+	verify(t, h)
+	if c.Total.Depth != 10 {
+		t.Errorf("Total.Depth = %d; want 10", c.Total.Depth)
+	}
+
+	c.Total = OpStats{}
+	c.Pop()
+	if s := c.Last; s.Depth < 1 || s.Swaps != s.Depth || s.Comparisons > 2*10 {
+		t.Errorf("Pop from 1024 elements: %+v; want depth = swaps and at most 20 comparisons", s)
+	}
+	verify(t, h)
+	popSwaps := c.Last.Swaps
+
+	h[100] = -1
+	c.Fix(100)
+	if s := c.Last; s.Depth != 6 || s.Swaps != 6 {
+		t.Errorf("Fix of new minimum at 100: %+v; want depth and swaps 6", s)
+	}
+	if c.Total.Swaps != popSwaps+6 {
+		t.Errorf("Total.Swaps = %d; want the sum over Pop and Fix", c.Total.Swaps)
+	}
+	verify(t, h)
+}
@@ -0,0 +1,72 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "time"
+
+// A DeadlineHeap holds items until their deadlines. Items with equal
+// deadlines are returned in the order they were pushed. The zero time is
+// earlier than any other deadline, so an item pushed with it is always
+// expired.
+//
+// The zero value is an empty heap ready to use.
+type DeadlineHeap[T any] struct {
+	// Hooks observe items being pushed and expiring.
+	Hooks Hooks[T]
+
+	h   []deadlineItem[T]
+	seq uint64
+}
+
+type deadlineItem[T any] struct {
+	at   time.Time
+	seq  uint64
+	item T
+}
+
+func deadlineLess[T any](x, y deadlineItem[T]) bool {
+	if !x.at.Equal(y.at) {
+		return x.at.Before(y.at)
+	}
+	return x.seq < y.seq
+}
+
+// Len returns the number of items in the heap.
+func (d *DeadlineHeap[T]) Len() int {
+	return len(d.h)
+}
+
+// PushAt adds item with deadline t.
+// The complexity is O(log n) where n = d.Len().
+func (d *DeadlineHeap[T]) PushAt(t time.Time, item T) {
+	PushFunc(&d.h, deadlineItem[T]{t, d.seq, item}, deadlineLess[T])
+	d.seq++
+	d.Hooks.push(item)
+}
+
+// NextDeadline returns the earliest deadline, and reports false if the heap
+// is empty.
+// The complexity is O(1).
+func (d *DeadlineHeap[T]) NextDeadline() (time.Time, bool) {
+	if len(d.h) == 0 {
+		return time.Time{}, false
+	}
+	return d.h[0].at, true
+}
+
+// PopExpired removes and returns the items whose deadlines are at or before
+// now, earliest first.
+// The complexity is O(k log n) where k is the number of items returned and
+// n = d.Len().
+func (d *DeadlineHeap[T]) PopExpired(now time.Time) []T {
+	var r []T
+	for len(d.h) > 0 && !d.h[0].at.After(now) {
+		r = append(r, PopFunc(&d.h, deadlineLess[T]).item)
+	}
+	for _, x := range r {
+		d.Hooks.leave(x, Expired)
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDeadlineHeap(t *testing.T) {
+	var d DeadlineHeap[string]
+	if _, ok := d.NextDeadline(); ok {
+		t.Fatal("NextDeadline of empty heap reported a deadline")
+	}
+	if got := d.PopExpired(time.Now()); got != nil {
+		t.Fatalf("PopExpired of empty heap = %v", got)
+	}
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.PushAt(t0.Add(2*time.Second), "c")
+	d.PushAt(t0.Add(time.Second), "a")
+	d.PushAt(t0.Add(3*time.Second), "e")
+	d.PushAt(t0.Add(time.Second), "b") // same deadline as a, pushed later
+	d.PushAt(time.Time{}, "zero")
+	d.PushAt(t0.Add(2*time.Second).In(time.FixedZone("X", 3600)), "d") // same instant as c
+
+	if next, _ := d.NextDeadline(); !next.IsZero() {
+		t.Errorf("NextDeadline() = %v; want the zero time", next)
+	}
+	if got := d.PopExpired(t0); !slices.Equal(got, []string{"zero"}) {
+		t.Errorf("PopExpired(t0) = %v; want [zero]", got)
+	}
+	if got := d.PopExpired(t0.Add(2 * time.Second)); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("PopExpired(t0+2s) = %v; want [a b c d]", got)
+	}
+	if next, ok := d.NextDeadline(); !ok || !next.Equal(t0.Add(3*time.Second)) || d.Len() != 1 {
+		t.Errorf("NextDeadline() = %v, %v with Len() = %d; want t0+3s, true, 1", next, ok, d.Len())
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build sliceheap_debug
+
+package sliceheap
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// When built with the sliceheap_debug tag, every operation verifies the heap
+// invariant afterwards and spot-checks the less function on a few elements,
+// panicking with the details of any violation. This makes each operation
+// O(n), but catches comparators that are inconsistent or that depend on
+// mutable state close to where the damage is done.
+const debug = true
+
+// debugSamples is the number of elements on which less is spot-checked.
+const debugSamples = 4
+
+func check[T any](op string, h []T, less func(x, y T) bool) {
+	if err := VerifyFunc(h, less); err != nil {
+		panic(fmt.Sprintf("%v after %s", err, op))
+	}
+	if len(h) == 0 {
+		return
+	}
+	for range debugSamples {
+		i, j := rand.IntN(len(h)), rand.IntN(len(h))
+		if less(h[i], h[i]) {
+			panic(fmt.Sprintf("sliceheap: less(x, x) is true for [%d] = %v after %s", i, h[i], op))
+		}
+		if less(h[i], h[j]) && less(h[j], h[i]) {
+			panic(fmt.Sprintf("sliceheap: less is not asymmetric for [%d] = %v and [%d] = %v after %s", i, h[i], j, h[j], op))
+		}
+	}
+}
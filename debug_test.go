@@ -0,0 +1,43 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build sliceheap_debug
+
+package sliceheap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugMutatedElement(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.HasPrefix(msg, "sliceheap: heap invariant violated") {
+			t.Errorf("panic = %q; want invariant diagnostic", msg)
+		}
+	}()
+	less := func(x, y *int) bool { return *x < *y }
+	var h []*int
+	for i := range 10 {
+		PushFunc(&h, &i, less)
+	}
+	// Changing an element without calling Fix breaks the heap.
+	*h[0] = 100
+	x := 50
+	PushFunc(&h, &x, less)
+	t.Error("corrupted heap was not detected")
+}
+
+func TestDebugIrreflexive(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "less(x, x) is true") {
+			t.Errorf("panic = %q; want less(x, x) diagnostic", msg)
+		}
+	}()
+	var h []int
+	PushFunc(&h, 1, func(x, y int) bool { return x <= y })
+	t.Error("reflexive less was not detected")
+}
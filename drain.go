@@ -0,0 +1,41 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// DrainBatches returns an iterator that pops the elements of the heap in
+// ascending order, in batches of batchSize elements; the last batch may be
+// shorter. Each batch reuses the same buffer, so it is valid only until the
+// next iteration. If iteration stops early, the remaining elements stay in
+// the heap. Elements pushed onto the heap between batches are included.
+// DrainBatches panics if batchSize < 1.
+// The complexity is O(log n) per element where n = len(*h).
+func DrainBatches[T cmp.Ordered](h *[]T, batchSize int) iter.Seq[[]T] {
+	return DrainBatchesFunc(h, batchSize, cmp.Less[T])
+}
+
+// DrainBatchesFunc is like [DrainBatches] but uses a less function to compare elements.
+func DrainBatchesFunc[T any](h *[]T, batchSize int, less func(x, y T) bool) iter.Seq[[]T] {
+	if batchSize < 1 {
+		panic(fmt.Sprintf("sliceheap: batch size %d < 1", batchSize))
+	}
+	return func(yield func([]T) bool) {
+		var buf []T
+		for len(*h) > 0 {
+			buf = buf[:0]
+			for len(buf) < batchSize && len(*h) > 0 {
+				buf = append(buf, PopFunc(h, less))
+			}
+			if !yield(buf) {
+				return
+			}
+		}
+	}
+}
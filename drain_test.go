@@ -0,0 +1,46 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDrainBatches(t *testing.T) {
+	h := []int{7, 3, 9, 1, 5, 2, 8}
+	Init(h)
+	var got [][]int
+	for b := range DrainBatches(&h, 3) {
+		got = append(got, slices.Clone(b))
+	}
+	want := [][]int{{1, 2, 3}, {5, 7, 8}, {9}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("batches %v; want %v", got, want)
+	}
+	if len(h) != 0 {
+		t.Errorf("len = %d after draining; want 0", len(h))
+	}
+
+	h = []int{4, 3, 2, 1}
+	Init(h)
+	for b := range DrainBatches(&h, 2) {
+		if !slices.Equal(b, []int{1, 2}) {
+			t.Errorf("first batch %v; want [1 2]", b)
+		}
+		break
+	}
+	verify(t, h)
+	if len(h) != 2 {
+		t.Errorf("len = %d after stopping early; want 2", len(h))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("DrainBatches with batch size 0 did not panic")
+		}
+	}()
+	DrainBatches(&h, 0)
+}
@@ -0,0 +1,105 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// DropWorst removes the n greatest elements of the heap and returns them in
+// no particular order. Rather than removing them one at a time, which costs
+// a search of the heap for each, it moves them to the end of the slice with
+// a selection pass and rebuilds the heap once. This suits shedding load
+// from a queue under overload.
+// If n >= len(*h), the heap is emptied. DropWorst panics if n < 0.
+// The complexity is O(n) on average where n = len(*h).
+func DropWorst[T cmp.Ordered](h *[]T, n int) []T {
+	return DropWorstFunc(h, n, cmp.Less[T])
+}
+
+// DropWorstFunc is like [DropWorst] but uses a less function to compare elements.
+func DropWorstFunc[T any](h *[]T, n int, less func(x, y T) bool) []T {
+	if n < 0 {
+		panic(fmt.Sprintf("sliceheap: DropWorst count %d < 0", n))
+	}
+	s := *h
+	if n == 0 {
+		return nil
+	}
+	m := max(len(s)-n, 0)
+	selectTail(s, m, less)
+	out := make([]T, len(s)-m)
+	copy(out, s[m:])
+	clear(s[m:])
+	*h = s[:m]
+	InitFunc(*h, less)
+	return out
+}
+
+// selectTail reorders s so that no element of s[m:] is less than any element
+// of s[:m], by quickselect with a median-of-three pivot.
+func selectTail[T any](s []T, m int, less func(x, y T) bool) {
+	lo, hi := 0, len(s)
+	for hi-lo > 1 && lo < m && m < hi {
+		// Order s[lo], s[mid], s[hi-1] and use the median as pivot. Taking
+		// the lower midpoint keeps j below hi-1, so every pass shrinks the
+		// range.
+		mid := int(uint(lo+hi-1) >> 1)
+		if less(s[mid], s[lo]) {
+			s[mid], s[lo] = s[lo], s[mid]
+		}
+		if less(s[hi-1], s[mid]) {
+			s[hi-1], s[mid] = s[mid], s[hi-1]
+			if less(s[mid], s[lo]) {
+				s[mid], s[lo] = s[lo], s[mid]
+			}
+		}
+		p := s[mid]
+
+		// Hoare partition: afterwards s[lo:j+1] <= p <= s[j+1:hi].
+		i, j := lo-1, hi
+		for {
+			for i++; less(s[i], p); i++ {
+			}
+			for j--; less(p, s[j]); j-- {
+			}
+			if i >= j {
+				break
+			}
+			s[i], s[j] = s[j], s[i]
+		}
+		if m <= j {
+			hi = j + 1
+		} else {
+			lo = j + 1
+		}
+	}
+}
+
+// TrimTo shrinks the heap to its k least elements, discarding the rest, as
+// when bounding a heap for top-k maintenance after a bulk load. The
+// discarded slots are zeroed so that they can be garbage collected; use
+// [DropWorst] to have them returned instead.
+// If k >= len(*h), the heap is unchanged. TrimTo panics if k < 0.
+// The complexity is O(n) on average where n = len(*h).
+func TrimTo[T cmp.Ordered](h *[]T, k int) {
+	TrimToFunc(h, k, cmp.Less[T])
+}
+
+// TrimToFunc is like [TrimTo] but uses a less function to compare elements.
+func TrimToFunc[T any](h *[]T, k int, less func(x, y T) bool) {
+	if k < 0 {
+		panic(fmt.Sprintf("sliceheap: TrimTo size %d < 0", k))
+	}
+	s := *h
+	if k >= len(s) {
+		return
+	}
+	selectTail(s, k, less)
+	clear(s[k:])
+	*h = s[:k]
+	InitFunc(*h, less)
+}
@@ -0,0 +1,78 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDropWorst(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 10, 100, 1000} {
+		for _, n := range []int{0, 1, size / 3, size - 1, size, size + 5} {
+			if n < 0 {
+				continue
+			}
+			var h []int
+			for range size {
+				Push(&h, rand.Intn(size/2+1)) // with duplicates
+			}
+			sorted := slices.Sorted(slices.Values(h))
+
+			got := DropWorst(&h, n)
+			verify(t, h)
+			keep := max(size-n, 0)
+			if len(h) != keep || len(got) != size-keep {
+				t.Fatalf("size %d, DropWorst(%d): len = %d, dropped %d", size, n, len(h), len(got))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, sorted[keep:]) {
+				t.Errorf("size %d, DropWorst(%d) = %v; want %v", size, n, got, sorted[keep:])
+			}
+		}
+	}
+}
+
+func TestDropWorstFunc(t *testing.T) {
+	greater := func(x, y int) bool { return x > y }
+	h := []int{5, 1, 9, 3, 7}
+	InitFunc(h, greater)
+	got := DropWorstFunc(&h, 2, greater)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("DropWorstFunc = %v; want [1 3]", got)
+	}
+	if PopFunc(&h, greater) != 9 {
+		t.Error("heap order lost after DropWorstFunc")
+	}
+}
+
+func TestTrimTo(t *testing.T) {
+	h := rand.Perm(200)
+	Init(h)
+	TrimTo(&h, 500)
+	if len(h) != 200 {
+		t.Fatalf("TrimTo(500) changed len to %d", len(h))
+	}
+	full := h[:cap(h)]
+	TrimTo(&h, 20)
+	verify(t, h)
+	got := slices.Sorted(slices.Values(h))
+	for i, x := range got {
+		if x != i {
+			t.Fatalf("TrimTo(20) kept %v; want 0..19", got)
+		}
+	}
+	for i, x := range full[20:] {
+		if x != 0 {
+			t.Fatalf("discarded slot %d = %d; want zeroed", 20+i, x)
+		}
+	}
+	TrimTo(&h, 0)
+	if len(h) != 0 {
+		t.Errorf("TrimTo(0) left %v", h)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package edf provides an earliest-deadline-first queue.
+package edf
+
+import (
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Handle refers to an item in a Queue.
+type Handle[T any] struct {
+	Value    T
+	deadline time.Time
+	worth    float64
+	seq      uint64
+	index    int // index in the heap, or -1 once removed
+}
+
+// Deadline returns the item's deadline.
+func (h *Handle[T]) Deadline() time.Time {
+	return h.deadline
+}
+
+// Worth returns the value assigned to the item when it was pushed.
+func (h *Handle[T]) Worth() float64 {
+	return h.worth
+}
+
+// A Queue orders items by absolute deadline, earliest first. Items with
+// equal deadlines are ordered by insertion.
+//
+// The zero value is an empty queue ready to use. A Queue is not safe for
+// concurrent use.
+type Queue[T any] struct {
+	h   []*Handle[T]
+	seq uint64
+}
+
+func less[T any](x, y *Handle[T]) bool {
+	if !x.deadline.Equal(y.deadline) {
+		return x.deadline.Before(y.deadline)
+	}
+	return x.seq < y.seq
+}
+
+func setIndex[T any](x *Handle[T], i int) {
+	x.index = i
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue[T]) Len() int {
+	return len(q.h)
+}
+
+// Push adds x to the queue with the given deadline and worth, which is used
+// only when shedding load with ShedLeastWorth.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[T]) Push(x T, deadline time.Time, worth float64) *Handle[T] {
+	h := &Handle[T]{Value: x, deadline: deadline, worth: worth, seq: q.seq}
+	q.seq++
+	indexheap.Push(&q.h, h, less, setIndex)
+	return h
+}
+
+// Peek returns the item with the earliest deadline without removing it.
+// It reports false if the queue is empty.
+func (q *Queue[T]) Peek() (*Handle[T], bool) {
+	if len(q.h) == 0 {
+		return nil, false
+	}
+	return q.h[0], true
+}
+
+// Pop removes the item with the earliest deadline and returns it together
+// with its lateness at now: how long ago its deadline passed, or a negative
+// duration if it is early. It reports false if the queue is empty.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[T]) Pop(now time.Time) (x T, lateness time.Duration, ok bool) {
+	if len(q.h) == 0 {
+		return x, 0, false
+	}
+	h := indexheap.Pop(&q.h, less, setIndex)
+	return h.Value, now.Sub(h.deadline), true
+}
+
+// Update changes the deadline of the item referred to by h. It reports false
+// if the item is no longer in the queue.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[T]) Update(h *Handle[T], deadline time.Time) bool {
+	if h.index < 0 || h.index >= len(q.h) || q.h[h.index] != h {
+		return false
+	}
+	h.deadline = deadline
+	indexheap.Fix(q.h, h.index, less, setIndex)
+	return true
+}
+
+// Remove removes the item referred to by h. It reports false if the item is
+// no longer in the queue.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[T]) Remove(h *Handle[T]) bool {
+	if h.index < 0 || h.index >= len(q.h) || q.h[h.index] != h {
+		return false
+	}
+	indexheap.Remove(&q.h, h.index, less, setIndex)
+	return true
+}
+
+// ShedPolicy chooses which items to drop when shedding load.
+type ShedPolicy int
+
+const (
+	// ShedMostLate drops the items with the earliest deadlines, which are
+	// the latest, or the least likely to be served in time.
+	ShedMostLate ShedPolicy = iota
+
+	// ShedLeastWorth drops the items of least worth, breaking ties in favor
+	// of dropping later deadlines.
+	ShedLeastWorth
+)
+
+// Shed drops items according to policy until at most limit remain, and
+// returns the dropped items.
+// The complexity is O(n log n) where n = q.Len().
+func (q *Queue[T]) Shed(limit int, policy ShedPolicy) []*Handle[T] {
+	k := len(q.h) - max(limit, 0)
+	if k <= 0 {
+		return nil
+	}
+	dropped := make([]*Handle[T], 0, k)
+	switch policy {
+	case ShedMostLate:
+		for range k {
+			dropped = append(dropped, indexheap.Pop(&q.h, less, setIndex))
+		}
+	case ShedLeastWorth:
+		// Collect the k items most worth dropping, then remove them.
+		t := sliceheap.NewTopKFunc(k, func(x, y *Handle[T]) bool {
+			if x.worth != y.worth {
+				return x.worth > y.worth
+			}
+			return less(x, y)
+		})
+		for _, h := range q.h {
+			t.Add(h)
+		}
+		for _, h := range t.Result() {
+			indexheap.Remove(&q.h, h.index, less, setIndex)
+			dropped = append(dropped, h)
+		}
+	}
+	return dropped
+}
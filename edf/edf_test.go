@@ -0,0 +1,91 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edf
+
+import (
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+
+func TestQueue(t *testing.T) {
+	var q Queue[string]
+	q.Push("c", at(30), 0)
+	b := q.Push("b", at(20), 0)
+	q.Push("a", at(10), 0)
+	d := q.Push("d", at(40), 0)
+
+	if !q.Update(d, at(5)) {
+		t.Fatal("Update(d) = false")
+	}
+	if !q.Remove(b) || q.Remove(b) {
+		t.Fatal("Remove(b) did not remove exactly once")
+	}
+	if q.Update(b, at(0)) {
+		t.Error("Update of removed item = true")
+	}
+	if h, ok := q.Peek(); !ok || h.Value != "d" || !h.Deadline().Equal(at(5)) {
+		t.Errorf("Peek() = %v, %v; want d at 5s", h, ok)
+	}
+
+	for _, want := range []struct {
+		v    string
+		late time.Duration
+	}{
+		{"d", 10 * time.Second},
+		{"a", 5 * time.Second},
+		{"c", -15 * time.Second},
+	} {
+		x, late, ok := q.Pop(at(15))
+		if !ok || x != want.v || late != want.late {
+			t.Errorf("Pop() = %s, %v, %v; want %s, %v", x, late, ok, want.v, want.late)
+		}
+	}
+	if _, _, ok := q.Pop(at(15)); ok {
+		t.Error("Pop() on empty queue succeeded")
+	}
+}
+
+func TestShed(t *testing.T) {
+	fill := func() *Queue[string] {
+		q := new(Queue[string])
+		q.Push("a", at(1), 5)
+		q.Push("b", at(2), 1)
+		q.Push("c", at(3), 9)
+		q.Push("d", at(4), 1)
+		q.Push("e", at(5), 3)
+		return q
+	}
+	values := func(hs []*Handle[string]) string {
+		s := ""
+		for _, h := range hs {
+			s += h.Value
+		}
+		return s
+	}
+
+	q := fill()
+	if got := values(q.Shed(3, ShedMostLate)); got != "ab" {
+		t.Errorf("Shed(3, ShedMostLate) dropped %q; want \"ab\"", got)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Len() = %d; want 3", q.Len())
+	}
+
+	q = fill()
+	if got := values(q.Shed(2, ShedLeastWorth)); got != "dbe" {
+		t.Errorf("Shed(2, ShedLeastWorth) dropped %q; want \"dbe\"", got)
+	}
+	if x, _, _ := q.Pop(t0); x != "a" {
+		t.Errorf("Pop() after shedding = %s; want a", x)
+	}
+
+	if got := q.Shed(5, ShedMostLate); got != nil {
+		t.Errorf("Shed under limit dropped %v", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package evict provides score-based eviction for caches.
+package evict
+
+import (
+	"cmp"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Policy tracks a score for each key of a cache and chooses the keys with
+// the lowest scores for eviction. The score can be a use count, giving
+// least-frequently-used eviction, a cost, or anything else that orders keys
+// by how much they are worth keeping.
+//
+// The zero value is an empty policy whose Touch leaves scores unchanged. A
+// Policy is not safe for concurrent use.
+type Policy[K comparable, S cmp.Ordered] struct {
+	rescore func(key K, score S) S
+	m       map[K]*entry[K, S]
+	h       []*entry[K, S]
+}
+
+type entry[K comparable, S cmp.Ordered] struct {
+	key   K
+	score S
+	index int
+}
+
+func less[K comparable, S cmp.Ordered](x, y *entry[K, S]) bool {
+	return x.score < y.score
+}
+
+func setIndex[K comparable, S cmp.Ordered](x *entry[K, S], i int) {
+	x.index = i
+}
+
+// New returns a policy that, on Touch, replaces a key's score with the
+// result of rescore. For least-frequently-used eviction, rescore adds one to
+// the score.
+func New[K comparable, S cmp.Ordered](rescore func(key K, score S) S) *Policy[K, S] {
+	return &Policy[K, S]{rescore: rescore}
+}
+
+// NewLFU returns a least-frequently-used policy, in which Touch adds one to
+// a key's score.
+func NewLFU[K comparable]() *Policy[K, int] {
+	return New(func(_ K, n int) int { return n + 1 })
+}
+
+// Len returns the number of keys tracked.
+func (p *Policy[K, S]) Len() int {
+	return len(p.h)
+}
+
+// Set sets the score of key, adding it if it is not tracked.
+// The complexity is O(log n) where n = p.Len().
+func (p *Policy[K, S]) Set(key K, score S) {
+	if e, ok := p.m[key]; ok {
+		e.score = score
+		indexheap.Fix(p.h, e.index, less, setIndex)
+		return
+	}
+	if p.m == nil {
+		p.m = make(map[K]*entry[K, S])
+	}
+	e := &entry[K, S]{key: key, score: score}
+	p.m[key] = e
+	indexheap.Push(&p.h, e, less, setIndex)
+}
+
+// Score returns the score of key and whether it is tracked.
+func (p *Policy[K, S]) Score(key K) (S, bool) {
+	if e, ok := p.m[key]; ok {
+		return e.score, true
+	}
+	var zero S
+	return zero, false
+}
+
+// Touch records a use of key, rescoring it. It reports whether key is
+// tracked.
+// The complexity is O(log n) where n = p.Len().
+func (p *Policy[K, S]) Touch(key K) bool {
+	e, ok := p.m[key]
+	if !ok {
+		return false
+	}
+	if p.rescore != nil {
+		e.score = p.rescore(key, e.score)
+		indexheap.Fix(p.h, e.index, less, setIndex)
+	}
+	return true
+}
+
+// Remove stops tracking key. It reports whether key was tracked.
+// The complexity is O(log n) where n = p.Len().
+func (p *Policy[K, S]) Remove(key K) bool {
+	e, ok := p.m[key]
+	if !ok {
+		return false
+	}
+	delete(p.m, key)
+	indexheap.Remove(&p.h, e.index, less, setIndex)
+	return true
+}
+
+// Evict stops tracking the n keys with the lowest scores and returns them,
+// lowest first. It returns fewer than n keys if fewer are tracked.
+// The complexity is O(n log m) where m = p.Len().
+func (p *Policy[K, S]) Evict(n int) []K {
+	n = min(n, len(p.h))
+	if n <= 0 {
+		return nil
+	}
+	keys := make([]K, n)
+	for i := range keys {
+		e := indexheap.Pop(&p.h, less, setIndex)
+		delete(p.m, e.key)
+		keys[i] = e.key
+	}
+	return keys
+}
@@ -0,0 +1,57 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package evict
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLFU(t *testing.T) {
+	p := NewLFU[string]()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		p.Set(k, 0)
+	}
+	for _, k := range []string{"a", "a", "a", "c", "c", "d", "e", "e", "e", "e"} {
+		if !p.Touch(k) {
+			t.Fatalf("Touch(%s) = false", k)
+		}
+	}
+	if p.Touch("missing") {
+		t.Error("Touch(missing) = true")
+	}
+	if s, ok := p.Score("a"); !ok || s != 3 {
+		t.Errorf("Score(a) = %d, %v; want 3, true", s, ok)
+	}
+
+	if got, want := p.Evict(2), []string{"b", "d"}; !slices.Equal(got, want) {
+		t.Errorf("Evict(2) = %v; want %v", got, want)
+	}
+	if !p.Remove("c") || p.Remove("c") {
+		t.Error("Remove(c) did not remove exactly once")
+	}
+	if got, want := p.Evict(10), []string{"a", "e"}; !slices.Equal(got, want) {
+		t.Errorf("Evict(10) = %v; want %v", got, want)
+	}
+	if p.Len() != 0 {
+		t.Errorf("Len() = %d; want 0", p.Len())
+	}
+}
+
+func TestSet(t *testing.T) {
+	var p Policy[int, float64]
+	for i := 0; i < 20; i++ {
+		p.Set(i, float64(i))
+	}
+	p.Set(15, -1) // rescore an existing key
+	p.Touch(3)    // no rescore function: unchanged
+
+	if got, want := p.Evict(3), []int{15, 0, 1}; !slices.Equal(got, want) {
+		t.Errorf("Evict(3) = %v; want %v", got, want)
+	}
+	if p.Len() != 17 {
+		t.Errorf("Len() = %d; want 17", p.Len())
+	}
+}
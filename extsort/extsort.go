@@ -0,0 +1,271 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package extsort sorts sequences too large to hold in memory. Elements are
+// collected into sorted runs of bounded length, runs are spilled to temporary
+// files, and the files are merged back together through a heap. SpillHeap
+// applies the same technique to a priority queue.
+package extsort
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+
+	"github.com/buth/sliceheap"
+)
+
+// DefaultMaxRunLen is the run length used when Sorter.MaxRunLen is zero.
+const DefaultMaxRunLen = 1 << 16
+
+// DefaultMaxRuns is the run limit used when SpillHeap.MaxRuns or
+// Sorter.MaxRuns is zero.
+const DefaultMaxRuns = 64
+
+// A Codec converts elements to and from their byte representation in a run
+// file.
+type Codec[T any] interface {
+	// Append appends the encoding of v to b and returns the extended buffer.
+	Append(b []byte, v T) ([]byte, error)
+
+	// Decode decodes an element from b, which holds exactly the bytes
+	// produced by a single call to Append.
+	Decode(b []byte) (T, error)
+}
+
+// A Sorter sorts sequences of T using a bounded amount of memory.
+// Less and Codec must be set; the other fields are optional.
+type Sorter[T any] struct {
+	// Less orders the elements.
+	Less func(x, y T) bool
+
+	// Codec encodes elements spilled to run files.
+	Codec Codec[T]
+
+	// MaxRunLen is the maximum number of elements held in memory while
+	// forming a run. It is a count of elements, not of bytes, so the
+	// memory it allows depends on the size of T; it bounds the memory used
+	// by Sort together with MaxRuns. If zero, DefaultMaxRunLen is used.
+	MaxRunLen int
+
+	// MaxRuns is the maximum number of runs merged at once. Each run
+	// being merged holds an open file and a read buffer. Whenever MaxRuns
+	// runs of the same generation have been written, Sort merges them
+	// into one run of the next generation, and before returning it merges
+	// the smallest runs until at most MaxRuns remain for Result.All. The
+	// number of open files is therefore at most about MaxRuns per
+	// generation, and each element is rewritten once per generation,
+	// O(log n) times in total. If zero, DefaultMaxRuns is used; values
+	// below 2 are treated as 2.
+	MaxRuns int
+
+	// TempDir is the directory in which run files are created. If empty,
+	// the default directory for temporary files is used.
+	TempDir string
+}
+
+// Sort consumes src, spilling sorted runs to temporary files as needed, and
+// returns a Result from which the sorted elements can be read. The sort is
+// stable. The caller must call Close on the Result to remove its files.
+func (s *Sorter[T]) Sort(src iter.Seq[T]) (*Result[T], error) {
+	runLen := s.MaxRunLen
+	if runLen <= 0 {
+		runLen = DefaultMaxRunLen
+	}
+	maxRuns := s.MaxRuns
+	if maxRuns == 0 {
+		maxRuns = DefaultMaxRuns
+	}
+	maxRuns = max(maxRuns, 2)
+
+	r := &Result[T]{less: s.Less, codec: s.Codec}
+	fail := func(err error) (*Result[T], error) {
+		r.Close()
+		return nil, err
+	}
+	var buf []T
+	for x := range src {
+		buf = append(buf, x)
+		if len(buf) == runLen {
+			if err := r.spill(s.TempDir, buf); err != nil {
+				return fail(err)
+			}
+			buf = buf[:0]
+			if err := r.mergeGenerations(s.TempDir, maxRuns); err != nil {
+				return fail(err)
+			}
+		}
+	}
+	if len(r.files) == 0 {
+		slices.SortStableFunc(buf, compare(s.Less))
+		r.mem = buf
+		return r, nil
+	}
+	if len(buf) > 0 {
+		if err := r.spill(s.TempDir, buf); err != nil {
+			return fail(err)
+		}
+	}
+	if k := len(r.files) - maxRuns; k > 0 {
+		// The last runs are the smallest; merging k+1 of them leaves
+		// maxRuns.
+		if err := r.merge(s.TempDir, len(r.files)-k-1); err != nil {
+			return fail(err)
+		}
+	}
+	return r, nil
+}
+
+func compare[T any](less func(x, y T) bool) func(x, y T) int {
+	return func(x, y T) int {
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		}
+		return 0
+	}
+}
+
+// A Result holds the sorted runs produced by Sorter.Sort.
+type Result[T any] struct {
+	less  func(x, y T) bool
+	codec Codec[T]
+	mem   []T // the only run, when nothing was spilled
+	files []*os.File
+	gens  []int // gens[i] is the number of merges that produced files[i]
+	err   error
+}
+
+func (r *Result[T]) spill(dir string, buf []T) error {
+	slices.SortStableFunc(buf, compare(r.less))
+	w, err := createRun(dir, r.codec)
+	if err != nil {
+		return err
+	}
+	r.files = append(r.files, w.f)
+	r.gens = append(r.gens, 0)
+	for _, x := range buf {
+		if err := w.write(x); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// mergeGenerations merges the last maxRuns runs into one while they are
+// all of the same generation. Runs are only ever merged with their
+// neighbours, so the runs stay in input order and the sort stays stable.
+func (r *Result[T]) mergeGenerations(dir string, maxRuns int) error {
+	for n := len(r.gens); n >= maxRuns; n = len(r.gens) {
+		g := r.gens[n-maxRuns:]
+		if slices.ContainsFunc(g, func(x int) bool { return x != g[0] }) {
+			return nil
+		}
+		if err := r.merge(dir, n-maxRuns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge merges the runs from index i on into a single run, one
+// generation later than the latest of them.
+func (r *Result[T]) merge(dir string, i int) error {
+	w, err := createRun(dir, r.codec)
+	if err != nil {
+		return err
+	}
+	for x := range r.merged(r.files[i:]) {
+		if err = w.write(x); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = r.err
+	}
+	if err == nil {
+		err = w.flush()
+	}
+	if err != nil {
+		return errors.Join(err, remove(w.f))
+	}
+	var errs []error
+	for _, f := range r.files[i:] {
+		errs = append(errs, remove(f))
+	}
+	gen := slices.Max(r.gens[i:]) + 1
+	r.files = append(r.files[:i], w.f)
+	r.gens = append(r.gens[:i], gen)
+	return errors.Join(errs...)
+}
+
+// merged returns the merge of the run files, in order.
+func (r *Result[T]) merged(files []*os.File) iter.Seq[T] {
+	seqs := make([]iter.Seq[T], len(files))
+	for i, f := range files {
+		seqs[i] = r.run(f)
+	}
+	return sliceheap.MergeSeqsFunc(r.less, seqs...)
+}
+
+// All returns an iterator over the sorted elements. Iteration stops early if
+// a run file cannot be read; the error is then reported by Err.
+// All may be called more than once.
+func (r *Result[T]) All() iter.Seq[T] {
+	if r.files == nil {
+		return slices.Values(r.mem)
+	}
+	merged := r.merged(r.files)
+	return func(yield func(T) bool) {
+		for x := range merged {
+			if r.err != nil || !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+func (r *Result[T]) run(f *os.File) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		rr := openRun(f, r.codec)
+		for {
+			x, err := rr.next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				r.setErr(err)
+				return
+			}
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+func (r *Result[T]) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Err returns the first error encountered while reading the runs, if any.
+func (r *Result[T]) Err() error {
+	return r.err
+}
+
+// Close removes the run files.
+func (r *Result[T]) Close() error {
+	var errs []error
+	for _, f := range r.files {
+		errs = append(errs, remove(f))
+	}
+	r.files, r.gens, r.mem = nil, nil, nil
+	return errors.Join(errs...)
+}
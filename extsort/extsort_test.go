@@ -0,0 +1,172 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package extsort
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+type intCodec struct{}
+
+func (intCodec) Append(b []byte, v int) ([]byte, error) {
+	return binary.AppendVarint(b, int64(v)), nil
+}
+
+func (intCodec) Decode(b []byte) (int, error) {
+	v, n := binary.Varint(b)
+	if n != len(b) {
+		return 0, errors.New("bad varint")
+	}
+	return int(v), nil
+}
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 10, 11, 1000} {
+		in := make([]int, n)
+		for i := range in {
+			in[i] = rand.Intn(100) - 50
+		}
+
+		dir := t.TempDir()
+		s := &Sorter[int]{
+			Less:      func(x, y int) bool { return x < y },
+			Codec:     intCodec{},
+			MaxRunLen: 10,
+			TempDir:   dir,
+		}
+		r, err := s.Sort(slices.Values(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := slices.Collect(r.All())
+		if err := r.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		want := slices.Clone(in)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("n=%d: Sort got %v; want %v", n, got, want)
+		}
+
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("n=%d: %d files left after Close", n, len(files))
+		}
+	}
+}
+
+type item struct{ key, seq int }
+
+func TestSortStable(t *testing.T) {
+	var in []item
+	for i := 0; i < 100; i++ {
+		in = append(in, item{rand.Intn(5), i})
+	}
+
+	s := &Sorter[item]{
+		Less:      func(x, y item) bool { return x.key < y.key },
+		Codec:     itemCodec{},
+		MaxRunLen: 7,
+		TempDir:   t.TempDir(),
+	}
+	r, err := s.Sort(slices.Values(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	got := slices.Collect(r.All())
+	want := slices.Clone(in)
+	slices.SortStableFunc(want, func(x, y item) int { return x.key - y.key })
+	if !slices.Equal(got, want) {
+		t.Errorf("Sort got %v; want %v", got, want)
+	}
+}
+
+func TestSortMaxRuns(t *testing.T) {
+	var in []item
+	for i := 0; i < 1000; i++ {
+		in = append(in, item{rand.Intn(20), i})
+	}
+	want := slices.Clone(in)
+	slices.SortStableFunc(want, func(x, y item) int { return x.key - y.key })
+
+	for _, maxRuns := range []int{1, 2, 3, 7} {
+		dir := t.TempDir()
+		s := &Sorter[item]{
+			Less:      func(x, y item) bool { return x.key < y.key },
+			Codec:     itemCodec{},
+			MaxRunLen: 10,
+			MaxRuns:   maxRuns,
+			TempDir:   dir,
+		}
+		r, err := s.Sort(slices.Values(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) > max(maxRuns, 2) {
+			t.Errorf("MaxRuns=%d: %d run files left to merge", maxRuns, len(files))
+		}
+		if got := slices.Collect(r.All()); !slices.Equal(got, want) {
+			t.Errorf("MaxRuns=%d: Sort got %v; want %v", maxRuns, got, want)
+		}
+		if err := r.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("MaxRuns=%d: %d files left after Close", maxRuns, len(files))
+		}
+	}
+}
+
+type itemCodec struct{}
+
+func (itemCodec) Append(b []byte, v item) ([]byte, error) {
+	b = binary.AppendVarint(b, int64(v.key))
+	return binary.AppendVarint(b, int64(v.seq)), nil
+}
+
+func (itemCodec) Decode(b []byte) (item, error) {
+	key, n := binary.Varint(b)
+	seq, _ := binary.Varint(b[n:])
+	return item{int(key), int(seq)}, nil
+}
+
+func TestSortDecodeError(t *testing.T) {
+	s := &Sorter[int]{
+		Less:      func(x, y int) bool { return x < y },
+		Codec:     failingCodec{},
+		MaxRunLen: 2,
+		TempDir:   t.TempDir(),
+	}
+	r, err := s.Sort(slices.Values([]int{5, 4, 3, 2, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for range r.All() {
+	}
+	if r.Err() == nil {
+		t.Error("Err() = nil; want decode error")
+	}
+}
+
+type failingCodec struct{ intCodec }
+
+func (failingCodec) Decode(b []byte) (int, error) {
+	return 0, errors.New("decode failed")
+}
@@ -0,0 +1,91 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package extsort
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"slices"
+)
+
+// A run file holds a sequence of elements, each encoded by a Codec and
+// preceded by its length as a uvarint.
+
+// A runWriter appends elements to a new run file.
+type runWriter[T any] struct {
+	f     *os.File
+	w     *bufio.Writer
+	codec Codec[T]
+	b, n  []byte
+}
+
+func createRun[T any](dir string, codec Codec[T]) (*runWriter[T], error) {
+	f, err := os.CreateTemp(dir, "extsort-*")
+	if err != nil {
+		return nil, err
+	}
+	return &runWriter[T]{f: f, w: bufio.NewWriter(f), codec: codec}, nil
+}
+
+func (w *runWriter[T]) write(x T) error {
+	var err error
+	if w.b, err = w.codec.Append(w.b[:0], x); err != nil {
+		return err
+	}
+	w.n = binary.AppendUvarint(w.n[:0], uint64(len(w.b)))
+	if _, err := w.w.Write(w.n); err != nil {
+		return err
+	}
+	_, err = w.w.Write(w.b)
+	return err
+}
+
+func (w *runWriter[T]) flush() error {
+	return w.w.Flush()
+}
+
+// A runReader reads the elements of a run file from the beginning.
+type runReader[T any] struct {
+	f     *os.File
+	r     *bufio.Reader
+	codec Codec[T]
+	b     []byte
+}
+
+func openRun[T any](f *os.File, codec Codec[T]) *runReader[T] {
+	return &runReader[T]{
+		f:     f,
+		r:     bufio.NewReader(io.NewSectionReader(f, 0, 1<<63-1)),
+		codec: codec,
+	}
+}
+
+// next returns the next element of the run, or io.EOF at its end.
+func (r *runReader[T]) next() (T, error) {
+	var zero T
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return zero, err
+	}
+	r.b = slices.Grow(r.b[:0], int(n))[:n]
+	if _, err := io.ReadFull(r.r, r.b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return zero, err
+	}
+	return r.codec.Decode(r.b)
+}
+
+// remove closes and deletes a run file.
+func remove(f *os.File) error {
+	err := f.Close()
+	if rerr := os.Remove(f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
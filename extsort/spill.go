@@ -0,0 +1,233 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package extsort
+
+import (
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/buth/sliceheap"
+)
+
+// DefaultMaxInMemory is the in-memory limit used when SpillHeap.MaxInMemory
+// is zero.
+const DefaultMaxInMemory = 1 << 16
+
+// A SpillHeap is a priority queue that holds a bounded number of elements in
+// memory and spills the rest to disk. When the in-memory tier is full, its
+// larger half is sorted and written to a run file. Pop returns the minimum of
+// the in-memory tier and the heads of the runs, reading the runs back
+// incrementally as their elements are consumed. When the in-memory tier
+// empties while runs remain, Pop refills half of it with the least
+// elements of the runs, so that later pops are again served from memory.
+//
+// Less and Codec must be set before the first call to Push; the other fields
+// are optional. The caller must call Close to remove the run files.
+// A SpillHeap is not safe for concurrent use.
+type SpillHeap[T any] struct {
+	// Less orders the elements.
+	Less func(x, y T) bool
+
+	// Codec encodes elements spilled to run files.
+	Codec Codec[T]
+
+	// MaxInMemory is the maximum number of elements held in the in-memory
+	// tier. If zero, DefaultMaxInMemory is used.
+	MaxInMemory int
+
+	// MaxRuns is the number of run files above which all runs are merged
+	// into one, bounding the number of open files. If zero,
+	// DefaultMaxRuns is used.
+	MaxRuns int
+
+	// TempDir is the directory in which run files are created. If empty,
+	// the default directory for temporary files is used.
+	TempDir string
+
+	mem  []T
+	runs []*spillRun[T] // heap ordered by head
+	n    int
+}
+
+// A spillRun is a run file together with its next unread element.
+type spillRun[T any] struct {
+	head T
+	r    *runReader[T]
+}
+
+func (h *SpillHeap[T]) runLess(x, y *spillRun[T]) bool {
+	return h.Less(x.head, y.head)
+}
+
+// Len returns the number of elements in the heap, in memory and on disk.
+func (h *SpillHeap[T]) Len() int {
+	return h.n
+}
+
+// Push pushes the element x onto the heap, spilling to disk if the in-memory
+// tier is full.
+func (h *SpillHeap[T]) Push(x T) error {
+	if len(h.mem) >= h.maxInMemory() {
+		if err := h.spill(); err != nil {
+			return err
+		}
+	}
+	sliceheap.PushFunc(&h.mem, x, h.Less)
+	h.n++
+	return nil
+}
+
+func (h *SpillHeap[T]) maxInMemory() int {
+	if h.MaxInMemory <= 0 {
+		return DefaultMaxInMemory
+	}
+	return h.MaxInMemory
+}
+
+// spill writes the larger half of the in-memory tier to a new run.
+func (h *SpillHeap[T]) spill() error {
+	slices.SortFunc(h.mem, compare(h.Less))
+	keep := len(h.mem) / 2
+	w, err := createRun(h.TempDir, h.Codec)
+	if err != nil {
+		return err
+	}
+	for _, x := range h.mem[keep:] {
+		if err := w.write(x); err != nil {
+			remove(w.f)
+			return err
+		}
+	}
+	if err := w.flush(); err != nil {
+		remove(w.f)
+		return err
+	}
+	if err := h.addRun(openRun(w.f, h.Codec)); err != nil {
+		return err
+	}
+	// A sorted slice is a valid heap.
+	clear(h.mem[keep:])
+	h.mem = h.mem[:keep]
+
+	maxRuns := h.MaxRuns
+	if maxRuns <= 0 {
+		maxRuns = DefaultMaxRuns
+	}
+	if len(h.runs) > maxRuns {
+		return h.compact()
+	}
+	return nil
+}
+
+// addRun reads the head of r and adds it to the run heap.
+func (h *SpillHeap[T]) addRun(r *runReader[T]) error {
+	x, err := r.next()
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		}
+		return errors.Join(err, remove(r.f))
+	}
+	sliceheap.PushFunc(&h.runs, &spillRun[T]{head: x, r: r}, h.runLess)
+	return nil
+}
+
+// advance replaces the head of the minimum run with its next element,
+// removing the run if it is exhausted.
+func (h *SpillHeap[T]) advance() error {
+	r := h.runs[0]
+	x, err := r.r.next()
+	if err == nil {
+		r.head = x
+		sliceheap.FixFunc(h.runs, 0, h.runLess)
+		return nil
+	}
+	sliceheap.PopFunc(&h.runs, h.runLess)
+	if err == io.EOF {
+		err = nil
+	}
+	return errors.Join(err, remove(r.r.f))
+}
+
+// compact merges all runs into a single run.
+func (h *SpillHeap[T]) compact() error {
+	w, err := createRun(h.TempDir, h.Codec)
+	if err != nil {
+		return err
+	}
+	for len(h.runs) > 0 {
+		if err := w.write(h.runs[0].head); err != nil {
+			remove(w.f)
+			return err
+		}
+		if err := h.advance(); err != nil {
+			remove(w.f)
+			return err
+		}
+	}
+	if err := w.flush(); err != nil {
+		remove(w.f)
+		return err
+	}
+	return h.addRun(openRun(w.f, h.Codec))
+}
+
+// Pop removes and returns the minimum element (according to Less) from the
+// heap. Pop panics if the heap is empty. If a run cannot be read, Pop returns
+// the error and the heap should be discarded.
+func (h *SpillHeap[T]) Pop() (T, error) {
+	if len(h.mem) == 0 && len(h.runs) > 0 {
+		if err := h.refill(); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	if len(h.runs) > 0 && (len(h.mem) == 0 || h.Less(h.runs[0].head, h.mem[0])) {
+		x := h.runs[0].head
+		if err := h.advance(); err != nil {
+			var zero T
+			return zero, err
+		}
+		h.n--
+		return x, nil
+	}
+	x := sliceheap.PopFunc(&h.mem, h.Less)
+	h.n--
+	return x, nil
+}
+
+// refill moves the least elements of the runs into the empty in-memory
+// tier until it is half full. They are taken in ascending order, and a
+// sorted slice is a valid heap.
+func (h *SpillHeap[T]) refill() error {
+	n := max(h.maxInMemory()/2, 1)
+	for len(h.mem) < n && len(h.runs) > 0 {
+		h.mem = append(h.mem, h.runs[0].head)
+		if err := h.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Peek returns the minimum element (according to Less) without removing it.
+// Peek panics if the heap is empty.
+func (h *SpillHeap[T]) Peek() T {
+	if len(h.runs) > 0 && (len(h.mem) == 0 || h.Less(h.runs[0].head, h.mem[0])) {
+		return h.runs[0].head
+	}
+	return h.mem[0]
+}
+
+// Close removes the run files and empties the heap.
+func (h *SpillHeap[T]) Close() error {
+	var errs []error
+	for _, r := range h.runs {
+		errs = append(errs, remove(r.r.f))
+	}
+	h.runs, h.mem, h.n = nil, nil, 0
+	return errors.Join(errs...)
+}
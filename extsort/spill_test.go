@@ -0,0 +1,126 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package extsort
+
+import (
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSpillHeap(t *testing.T) {
+	dir := t.TempDir()
+	h := &SpillHeap[int]{
+		Less:        func(x, y int) bool { return x < y },
+		Codec:       intCodec{},
+		MaxInMemory: 8,
+		MaxRuns:     4,
+		TempDir:     dir,
+	}
+
+	var want []int
+	for i := 0; i < 500; i++ {
+		// Interleave pushes and pops so that runs are consumed while
+		// others are still being written.
+		if i%3 == 2 {
+			x, err := h.Pop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(want)
+			if x != want[0] {
+				t.Fatalf("Pop() = %d; want %d", x, want[0])
+			}
+			want = want[1:]
+			continue
+		}
+		x := rand.Intn(1000)
+		if err := h.Push(x); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, x)
+	}
+	if h.Len() != len(want) {
+		t.Fatalf("Len() = %d; want %d", h.Len(), len(want))
+	}
+
+	slices.Sort(want)
+	for i, w := range want {
+		if p := h.Peek(); p != w {
+			t.Fatalf("%d.th Peek() = %d; want %d", i, p, w)
+		}
+		x, err := h.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != w {
+			t.Fatalf("%d.th Pop() = %d; want %d", i, x, w)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d files left after draining", len(files))
+	}
+}
+
+func TestSpillHeapRefill(t *testing.T) {
+	h := &SpillHeap[int]{
+		Less:        func(x, y int) bool { return x < y },
+		Codec:       intCodec{},
+		MaxInMemory: 8,
+		TempDir:     t.TempDir(),
+	}
+	defer h.Close()
+	for i := 99; i >= 0; i-- {
+		if err := h.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refills := 0
+	for want := 0; want < 100; want++ {
+		empty := len(h.mem) == 0 && len(h.runs) > 0
+		x, err := h.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != want {
+			t.Fatalf("Pop() = %d; want %d", x, want)
+		}
+		if empty {
+			// Half the tier was refilled, and one element popped.
+			if len(h.mem) != 3 {
+				t.Fatalf("after refill, %d elements in memory; want 3", len(h.mem))
+			}
+			refills++
+		}
+	}
+	if refills == 0 {
+		t.Error("the in-memory tier was never refilled")
+	}
+}
+
+func TestSpillHeapClose(t *testing.T) {
+	dir := t.TempDir()
+	h := &SpillHeap[int]{
+		Less:        func(x, y int) bool { return x < y },
+		Codec:       intCodec{},
+		MaxInMemory: 4,
+		TempDir:     dir,
+	}
+	for i := 100; i > 0; i-- {
+		if err := h.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len() after Close = %d; want 0", h.Len())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d files left after Close", len(files))
+	}
+}
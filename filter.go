@@ -0,0 +1,63 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Filter returns a new heap holding the elements of h for which keep
+// returns true. The result is built with a single heapify rather than by
+// pushing the elements one at a time. h is not modified.
+// The complexity is O(n) where n = len(h).
+func Filter[T cmp.Ordered](h []T, keep func(T) bool) []T {
+	return FilterFunc(h, keep, cmp.Less[T])
+}
+
+// FilterFunc is like [Filter] but uses a less function to compare elements.
+func FilterFunc[T any](h []T, keep func(T) bool, less func(x, y T) bool) []T {
+	var r []T
+	for _, x := range h {
+		if keep(x) {
+			r = append(r, x)
+		}
+	}
+	InitFunc(r, less)
+	return r
+}
+
+// FilterInPlace is like [Filter] but removes the elements of *h for which
+// keep returns false, reusing its storage.
+// The complexity is O(n) where n = len(*h).
+func FilterInPlace[T cmp.Ordered](h *[]T, keep func(T) bool) {
+	FilterInPlaceFunc(h, keep, cmp.Less[T])
+}
+
+// FilterInPlaceFunc is like [FilterInPlace] but uses a less function to compare elements.
+func FilterInPlaceFunc[T any](h *[]T, keep func(T) bool, less func(x, y T) bool) {
+	n := len(*h)
+	*h = slices.DeleteFunc(*h, func(x T) bool { return !keep(x) })
+	if len(*h) != n {
+		InitFunc(*h, less)
+	}
+}
+
+// MapHeap returns a new heap holding f applied to each element of h, which
+// need not be a heap. The results are heapified in a single pass.
+// The complexity is O(n) where n = len(h).
+func MapHeap[T any, U cmp.Ordered](h []T, f func(T) U) []U {
+	return MapHeapFunc(h, f, cmp.Less[U])
+}
+
+// MapHeapFunc is like [MapHeap] but uses a less function to compare elements.
+func MapHeapFunc[T, U any](h []T, f func(T) U, less func(x, y U) bool) []U {
+	r := make([]U, len(h))
+	for i, x := range h {
+		r[i] = f(x)
+	}
+	InitFunc(r, less)
+	return r
+}
@@ -0,0 +1,67 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var h []int
+	for range 100 {
+		Push(&h, rand.Intn(1000))
+	}
+	orig := slices.Clone(h)
+	even := func(x int) bool { return x%2 == 0 }
+
+	got := Filter(h, even)
+	verify(t, got)
+	if !slices.Equal(h, orig) {
+		t.Error("Filter modified its argument")
+	}
+	want := slices.DeleteFunc(slices.Clone(orig), func(x int) bool { return !even(x) })
+	slices.Sort(want)
+	sorted := slices.Sorted(slices.Values(got))
+	if !slices.Equal(sorted, want) {
+		t.Errorf("Filter kept %v; want %v", sorted, want)
+	}
+
+	FilterInPlace(&h, even)
+	verify(t, h)
+	if !slices.Equal(slices.Sorted(slices.Values(h)), want) {
+		t.Errorf("FilterInPlace kept %v; want %v", h, want)
+	}
+
+	greater := func(x, y int) bool { return x > y }
+	g := []int{9, 7, 8, 3, 6}
+	FilterInPlaceFunc(&g, func(x int) bool { return x != 9 }, greater)
+	if err := VerifyFunc(g, greater); err != nil || g[0] != 8 {
+		t.Errorf("FilterInPlaceFunc = %v, %v; want max-heap rooted at 8", g, err)
+	}
+	if got := FilterFunc(g, func(int) bool { return false }, greater); len(got) != 0 {
+		t.Errorf("FilterFunc keeping nothing = %v", got)
+	}
+}
+
+func TestMapHeap(t *testing.T) {
+	type job struct {
+		name  string
+		score int
+	}
+	jobs := []job{{"a", 5}, {"b", 2}, {"c", 9}, {"d", 1}}
+
+	scores := MapHeap(jobs, func(j job) int { return j.score })
+	verify(t, scores)
+	if len(scores) != 4 || scores[0] != 1 {
+		t.Errorf("MapHeap = %v; want 4 scores with minimum 1", scores)
+	}
+
+	names := MapHeapFunc(jobs, func(j job) string { return j.name }, func(x, y string) bool { return x > y })
+	if names[0] != "d" {
+		t.Errorf("MapHeapFunc root = %q; want \"d\"", names[0])
+	}
+}
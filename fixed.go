@@ -0,0 +1,96 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// A FixedHeap is a min-heap stored in a caller-owned buffer, whose capacity
+// it never exceeds. After construction no operation allocates, which suits
+// embedded and real-time code; Push reports false rather than growing when
+// the heap is full.
+type FixedHeap[T any] struct {
+	h    []T
+	less func(x, y T) bool
+}
+
+// NewFixedHeap returns an empty heap that stores its elements in buf and
+// holds at most cap(buf) of them. The contents of buf are overwritten.
+func NewFixedHeap[T cmp.Ordered](buf []T) *FixedHeap[T] {
+	return NewFixedHeapFunc(buf, cmp.Less[T])
+}
+
+// NewFixedHeapFunc is like [NewFixedHeap] but uses a less function to compare elements.
+func NewFixedHeapFunc[T any](buf []T, less func(x, y T) bool) *FixedHeap[T] {
+	return &FixedHeap[T]{h: buf[:0], less: less}
+}
+
+// Len returns the number of elements in the heap.
+func (f *FixedHeap[T]) Len() int {
+	return len(f.h)
+}
+
+// Cap returns the maximum number of elements the heap can hold.
+func (f *FixedHeap[T]) Cap() int {
+	return cap(f.h)
+}
+
+// Push adds x to the heap, and reports false, leaving the heap unchanged,
+// if the heap is full.
+// The complexity is O(log n) where n = f.Len().
+func (f *FixedHeap[T]) Push(x T) bool {
+	if len(f.h) == cap(f.h) {
+		return false
+	}
+	PushFunc(&f.h, x, f.less)
+	return true
+}
+
+// Peek returns the least element without removing it, and reports false if
+// the heap is empty.
+// The complexity is O(1).
+func (f *FixedHeap[T]) Peek() (T, bool) {
+	if len(f.h) == 0 {
+		var zero T
+		return zero, false
+	}
+	return f.h[0], true
+}
+
+// Pop removes and returns the least element, and reports false if the heap
+// is empty. The vacated slot of the buffer is zeroed.
+// The complexity is O(log n) where n = f.Len().
+func (f *FixedHeap[T]) Pop() (T, bool) {
+	if len(f.h) == 0 {
+		var zero T
+		return zero, false
+	}
+	x := PopFunc(&f.h, f.less)
+	clear(f.h[len(f.h) : len(f.h)+1])
+	return x, true
+}
+
+// Queue returns the heap as a [PriorityQueue]. The queue's Push panics if
+// the heap is full, and its Pop and Peek panic if the heap is empty.
+func (f *FixedHeap[T]) Queue() PriorityQueue[T] {
+	return fixedQueue[T]{f}
+}
+
+type fixedQueue[T any] struct{ f *FixedHeap[T] }
+
+func (q fixedQueue[T]) Len() int { return q.f.Len() }
+func (q fixedQueue[T]) Pop() T   { return nonEmpty(q.f.Pop()) }
+func (q fixedQueue[T]) Peek() T  { return nonEmpty(q.f.Peek()) }
+
+func (q fixedQueue[T]) Push(x T) {
+	if !q.f.Push(x) {
+		panic("sliceheap: Push on full FixedHeap")
+	}
+}
+
+// Reset removes all elements from the heap, zeroing the buffer.
+func (f *FixedHeap[T]) Reset() {
+	clear(f.h)
+	f.h = f.h[:0]
+}
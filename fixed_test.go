@@ -0,0 +1,56 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "testing"
+
+func TestFixedHeap(t *testing.T) {
+	var buf [4]*int
+	one, two, three, four, five := 1, 2, 3, 4, 5
+	f := NewFixedHeapFunc(buf[:], func(x, y *int) bool { return *x < *y })
+	for _, p := range []*int{&three, &one, &four, &two} {
+		if !f.Push(p) {
+			t.Fatalf("Push(%d) failed with Len() = %d", *p, f.Len())
+		}
+	}
+	if f.Push(&five) || f.Len() != 4 || f.Cap() != 4 {
+		t.Errorf("Push on full heap succeeded, or Len() = %d, Cap() = %d", f.Len(), f.Cap())
+	}
+	for want := 1; want <= 4; want++ {
+		if p, ok := f.Pop(); !ok || *p != want {
+			t.Fatalf("Pop() = %v, %v; want %d", p, ok, want)
+		}
+	}
+	for i, p := range buf {
+		if p != nil {
+			t.Errorf("buf[%d] not zeroed after Pop", i)
+		}
+	}
+	if _, ok := f.Pop(); ok {
+		t.Error("Pop on empty heap reported true")
+	}
+}
+
+func TestFixedHeapAllocs(t *testing.T) {
+	if debug {
+		t.Skip("debug checks allocate")
+	}
+	f := NewFixedHeap(make([]int, 0, 64))
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := range 64 {
+			f.Push(64 - i)
+		}
+		f.Push(0)
+		f.Peek()
+		for f.Len() > 0 {
+			f.Pop()
+		}
+		f.Push(1)
+		f.Reset()
+	})
+	if allocs != 0 {
+		t.Errorf("%v allocations per run; want 0", allocs)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "math"
+
+// TotalLess reports whether x orders before y in a total order of the
+// floating-point values: negative infinity first, then the finite values
+// with -0 before +0, positive infinity, and finally all NaNs, which are
+// equivalent to each other. It follows IEEE 754 totalOrder except that NaNs
+// are ordered last whatever their sign.
+//
+// A less function written as x < y is not a strict weak order when NaNs are
+// present, since a NaN is then incomparable with every value, and a heap
+// built with it can silently lose its ordering. The functions in this
+// package without a Func suffix use [cmp.Less], which is safe but orders
+// NaNs first; use TotalLess with the Func variants to order them last, for
+// example to keep invalid measurements away from the top of a min-heap.
+func TotalLess[F ~float32 | ~float64](x, y F) bool {
+	xnan, ynan := x != x, y != y
+	if xnan || ynan {
+		return !xnan
+	}
+	if x == y {
+		return math.Signbit(float64(x)) && !math.Signbit(float64(y))
+	}
+	return x < y
+}
@@ -0,0 +1,42 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTotalLess(t *testing.T) {
+	nan := math.NaN()
+	negZero := math.Copysign(0, -1)
+	order := []float64{math.Inf(-1), -1, negZero, 0, 1e-300, 1, math.Inf(1), nan}
+	for i, x := range order {
+		for j, y := range order {
+			if got, want := TotalLess(x, y), i < j && !(math.IsNaN(x) && math.IsNaN(y)); got != want {
+				t.Errorf("TotalLess(%v, %v) = %v; want %v", x, y, got, want)
+			}
+		}
+	}
+	if TotalLess(nan, -nan) || TotalLess(-nan, nan) {
+		t.Error("NaNs of different signs are not equivalent")
+	}
+	if !TotalLess(float32(negZero), float32(0)) {
+		t.Error("TotalLess(float32(-0), float32(0)) = false")
+	}
+
+	h := []float64{3, nan, 1, math.Inf(1), nan, negZero, 0, -2}
+	InitFunc(h, TotalLess[float64])
+	var got []float64
+	for len(h) > 0 {
+		got = append(got, PopFunc(&h, TotalLess[float64]))
+	}
+	want := []float64{-2, negZero, 0, 1, 3, math.Inf(1), nan, nan}
+	for i := range want {
+		if math.Float64bits(got[i]) != math.Float64bits(want[i]) && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+			t.Fatalf("popped %v; want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "github.com/buth/sliceheap/internal/indexheap"
+
+// An AStarFrontier is the open set of an A* search: the nodes discovered but
+// not yet expanded, each with its best known cost g from the start and its
+// heuristic estimate h of the cost to the goal. Pop returns the node with the
+// lowest f = g + h, breaking ties in favor of the lower h, which is the node
+// closer to the goal, and then in favor of the node pushed first.
+//
+// The zero value is an empty frontier ready to use. An AStarFrontier is not
+// safe for concurrent use.
+type AStarFrontier[N comparable, W Weight] struct {
+	h   []*open[N, W]
+	m   map[N]*open[N, W]
+	seq uint64
+}
+
+type open[N comparable, W Weight] struct {
+	n     N
+	g, h  W
+	seq   uint64
+	index int
+}
+
+func openLess[N comparable, W Weight](x, y *open[N, W]) bool {
+	if fx, fy := x.g+x.h, y.g+y.h; fx != fy {
+		return fx < fy
+	}
+	if x.h != y.h {
+		return x.h < y.h
+	}
+	return x.seq < y.seq
+}
+
+func setOpenIndex[N comparable, W Weight](x *open[N, W], i int) {
+	x.index = i
+}
+
+// Len returns the number of nodes in the frontier.
+func (f *AStarFrontier[N, W]) Len() int {
+	return len(f.h)
+}
+
+// Contains reports whether n is in the frontier.
+func (f *AStarFrontier[N, W]) Contains(n N) bool {
+	_, ok := f.m[n]
+	return ok
+}
+
+// G returns the cost recorded for n and whether n is in the frontier.
+func (f *AStarFrontier[N, W]) G(n N) (W, bool) {
+	if o, ok := f.m[n]; ok {
+		return o.g, true
+	}
+	var zero W
+	return zero, false
+}
+
+// Push adds n to the frontier with cost g and heuristic h. If n is already in
+// the frontier, its cost is lowered to g if that is an improvement, and its
+// heuristic is left unchanged. Push reports whether n was added or improved.
+// The complexity is O(log n) where n = f.Len().
+func (f *AStarFrontier[N, W]) Push(n N, g, h W) bool {
+	if o, ok := f.m[n]; ok {
+		if g >= o.g {
+			return false
+		}
+		o.g = g
+		indexheap.Up(f.h, o.index, openLess, setOpenIndex)
+		return true
+	}
+	if f.m == nil {
+		f.m = make(map[N]*open[N, W])
+	}
+	o := &open[N, W]{n: n, g: g, h: h, seq: f.seq}
+	f.seq++
+	f.m[n] = o
+	indexheap.Push(&f.h, o, openLess, setOpenIndex)
+	return true
+}
+
+// Pop removes and returns the node with the lowest f score and its cost g.
+// It reports false if the frontier is empty.
+// The complexity is O(log n) where n = f.Len().
+func (f *AStarFrontier[N, W]) Pop() (n N, g W, ok bool) {
+	if len(f.h) == 0 {
+		return n, g, false
+	}
+	o := indexheap.Pop(&f.h, openLess, setOpenIndex)
+	delete(f.m, o.n)
+	return o.n, o.g, true
+}
@@ -0,0 +1,77 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "testing"
+
+func TestAStarFrontier(t *testing.T) {
+	var f AStarFrontier[string, int]
+	f.Push("a", 5, 5) // f=10
+	f.Push("b", 2, 8) // f=10, further from the goal than a
+	f.Push("c", 3, 4) // f=7
+	f.Push("d", 9, 0) // f=9
+	if f.Push("d", 9, 0) {
+		t.Error("Push with equal g reported an improvement")
+	}
+	if !f.Push("b", 0, 8) { // f=8
+		t.Error("Push with better g reported no improvement")
+	}
+	if !f.Contains("b") || f.Contains("z") {
+		t.Error("Contains reports wrong membership")
+	}
+	if g, ok := f.G("b"); !ok || g != 0 {
+		t.Errorf("G(b) = %d, %v; want 0, true", g, ok)
+	}
+
+	for _, want := range []string{"c", "b", "d", "a"} {
+		n, _, ok := f.Pop()
+		if !ok || n != want {
+			t.Errorf("Pop() = %s, %v; want %s", n, ok, want)
+		}
+	}
+	if _, _, ok := f.Pop(); ok || f.Len() != 0 {
+		t.Error("frontier not empty")
+	}
+}
+
+func TestAStarGrid(t *testing.T) {
+	// Find a shortest path across a grid with a wall, using Manhattan
+	// distance as the heuristic.
+	grid := []string{
+		"S....",
+		".###.",
+		"...#.",
+		".#.#.",
+		".#..G",
+	}
+	type pt struct{ r, c int }
+	abs := func(x int) int { return max(x, -x) }
+	goal := pt{4, 4}
+	h := func(p pt) int { return abs(p.r-goal.r) + abs(p.c-goal.c) }
+
+	var f AStarFrontier[pt, int]
+	closed := map[pt]bool{}
+	f.Push(pt{0, 0}, 0, h(pt{0, 0}))
+	for {
+		p, g, ok := f.Pop()
+		if !ok {
+			t.Fatal("goal unreachable")
+		}
+		if p == goal {
+			if g != 8 {
+				t.Errorf("path length %d; want 8", g)
+			}
+			return
+		}
+		closed[p] = true
+		for _, d := range []pt{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+			q := pt{p.r + d.r, p.c + d.c}
+			if q.r < 0 || q.r >= len(grid) || q.c < 0 || q.c >= len(grid[0]) || grid[q.r][q.c] == '#' || closed[q] {
+				continue
+			}
+			f.Push(q, g+1, h(q))
+		}
+	}
+}
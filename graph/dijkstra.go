@@ -0,0 +1,81 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A node is a graph node in a heap ordered by distance.
+type node[N comparable, W Weight] struct {
+	n     N
+	d     W
+	index int // index in the heap, or -1 once settled
+}
+
+func nodeLess[N comparable, W Weight](x, y *node[N, W]) bool {
+	return x.d < y.d
+}
+
+func setIndex[N comparable, W Weight](x *node[N, W], i int) {
+	x.index = i
+}
+
+// Dijkstra computes the shortest paths from source to every node reachable
+// from it in g. It returns the length of each shortest path and each node's
+// predecessor along it; the source has no predecessor.
+// Dijkstra panics if it encounters a negative edge weight.
+// The complexity is O((V+E) log V) for V reachable nodes and E edges among
+// them.
+func Dijkstra[N comparable, W Weight](g Adjacency[N, W], source N) (dist map[N]W, prev map[N]N) {
+	dist = make(map[N]W)
+	prev = make(map[N]N)
+	nodes := map[N]*node[N, W]{source: {n: source}}
+	h := []*node[N, W]{nodes[source]}
+	for len(h) > 0 {
+		u := indexheap.Pop(&h, nodeLess, setIndex)
+		dist[u.n] = u.d
+		for _, e := range g[u.n] {
+			if e.Weight < 0 {
+				panic(fmt.Sprintf("graph: negative edge weight %v from %v to %v", e.Weight, u.n, e.To))
+			}
+			d := u.d + e.Weight
+			v, ok := nodes[e.To]
+			switch {
+			case !ok:
+				v = &node[N, W]{n: e.To, d: d}
+				nodes[e.To] = v
+				indexheap.Push(&h, v, nodeLess, setIndex)
+			case v.index >= 0 && d < v.d:
+				// Decrease key.
+				v.d = d
+				indexheap.Up(h, v.index, nodeLess, setIndex)
+			default:
+				continue
+			}
+			prev[e.To] = u.n
+		}
+	}
+	return dist, prev
+}
+
+// Path returns the path ending at target recorded in prev, as returned by
+// Dijkstra, starting from the source. It returns a path consisting of just
+// target if target is the source or unreachable.
+func Path[N comparable](prev map[N]N, target N) []N {
+	path := []N{target}
+	for {
+		p, ok := prev[path[len(path)-1]]
+		if !ok {
+			break
+		}
+		path = append(path, p)
+	}
+	slices.Reverse(path)
+	return path
+}
@@ -0,0 +1,95 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDijkstra(t *testing.T) {
+	g := Adjacency[string, int]{
+		"a": {{"b", 7}, {"c", 9}, {"f", 14}},
+		"b": {{"a", 7}, {"c", 10}, {"d", 15}},
+		"c": {{"a", 9}, {"b", 10}, {"d", 11}, {"f", 2}},
+		"d": {{"b", 15}, {"c", 11}, {"e", 6}},
+		"e": {{"d", 6}, {"f", 9}},
+		"f": {{"a", 14}, {"c", 2}, {"e", 9}},
+		"z": {{"a", 1}}, // unreachable from a
+	}
+	dist, prev := Dijkstra(g, "a")
+
+	want := map[string]int{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}
+	if !maps.Equal(dist, want) {
+		t.Errorf("dist = %v; want %v", dist, want)
+	}
+	if p := Path(prev, "e"); !slices.Equal(p, []string{"a", "c", "f", "e"}) {
+		t.Errorf("Path(e) = %v; want [a c f e]", p)
+	}
+	if p := Path(prev, "a"); !slices.Equal(p, []string{"a"}) {
+		t.Errorf("Path(a) = %v; want [a]", p)
+	}
+}
+
+// bellmanFord is a simple reference implementation.
+func bellmanFord(g Adjacency[int, float64], n, source int) map[int]float64 {
+	dist := map[int]float64{source: 0}
+	for i := 0; i < n; i++ {
+		for u, edges := range g {
+			du, ok := dist[u]
+			if !ok {
+				continue
+			}
+			for _, e := range edges {
+				if dv, ok := dist[e.To]; !ok || du+e.Weight < dv {
+					dist[e.To] = du + e.Weight
+				}
+			}
+		}
+	}
+	return dist
+}
+
+func TestDijkstraRandom(t *testing.T) {
+	const n = 50
+	g := Adjacency[int, float64]{}
+	for i := 0; i < 200; i++ {
+		u, v := rand.Intn(n), rand.Intn(n)
+		g[u] = append(g[u], Edge[int, float64]{v, float64(rand.Intn(20))})
+	}
+
+	dist, prev := Dijkstra(g, 0)
+	want := bellmanFord(g, n, 0)
+	if !maps.Equal(dist, want) {
+		t.Fatalf("dist = %v; want %v", dist, want)
+	}
+	for v := range dist {
+		path := Path(prev, v)
+		var length float64
+		for i := 1; i < len(path); i++ {
+			best := -1.0
+			for _, e := range g[path[i-1]] {
+				if e.To == path[i] && (best < 0 || e.Weight < best) {
+					best = e.Weight
+				}
+			}
+			length += best
+		}
+		if path[0] != 0 || length != dist[v] {
+			t.Errorf("Path(%d) = %v of length %v; want from 0 of length %v", v, path, length, dist[v])
+		}
+	}
+}
+
+func TestDijkstraNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Dijkstra with negative weight did not panic")
+		}
+	}()
+	Dijkstra(Adjacency[int, int]{0: {{1, -1}}}, 0)
+}
@@ -0,0 +1,24 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package graph provides heap-based graph algorithms.
+package graph
+
+// Weight is the constraint for edge weights.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// An Edge is a weighted edge to the node To.
+type Edge[N comparable, W Weight] struct {
+	To     N
+	Weight W
+}
+
+// An Adjacency is a graph given as the outgoing edges of each node. Nodes
+// that appear only as the target of an edge need not be keys. For an
+// undirected graph, each edge must be listed in both directions.
+type Adjacency[N comparable, W Weight] map[N][]Edge[N, W]
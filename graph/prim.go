@@ -0,0 +1,138 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A PrimMethod selects the priority queue used by MinimumSpanningTree.
+type PrimMethod int
+
+const (
+	// PrimLazy keeps a heap of candidate edges and discards those that
+	// lead back into the tree as they surface. It takes O(E log E) time
+	// and O(E) space.
+	PrimLazy PrimMethod = iota
+
+	// PrimIndexed keeps a heap of nodes keyed by their cheapest edge into
+	// the tree, lowering keys as cheaper edges are found. It takes
+	// O(E log V) time and O(V) space.
+	PrimIndexed
+)
+
+// A TreeEdge is an edge of a spanning tree.
+type TreeEdge[N comparable, W Weight] struct {
+	From, To N
+	Weight   W
+}
+
+// MinimumSpanningTree computes a minimum spanning forest of the undirected
+// graph g, which must list each edge in both directions, using Prim's
+// algorithm. It returns the edges of the forest, each oriented away from the
+// root of its tree, and their total weight.
+//
+// The roots are taken in map order, so when g is disconnected the order of
+// the trees, and the root of each, can differ between calls. Use
+// [MinimumSpanningTreeFrom] for a deterministic result.
+func MinimumSpanningTree[N comparable, W Weight](g Adjacency[N, W], method PrimMethod) (edges []TreeEdge[N, W], total W) {
+	return MinimumSpanningTreeFrom(g, slices.Collect(maps.Keys(g)), method)
+}
+
+// MinimumSpanningTreeFrom is like [MinimumSpanningTree] but grows the trees
+// from roots in order: the first tree from roots[0], the next from the
+// first of roots not yet spanned, and so on. Nodes of g not connected to
+// any of roots are left out. Given every node of g in a fixed order, the
+// result is deterministic.
+func MinimumSpanningTreeFrom[N comparable, W Weight](g Adjacency[N, W], roots []N, method PrimMethod) (edges []TreeEdge[N, W], total W) {
+	inTree := make(map[N]bool)
+	for _, root := range roots {
+		if inTree[root] {
+			continue
+		}
+		switch method {
+		case PrimLazy:
+			edges = primLazy(g, root, inTree, edges)
+		case PrimIndexed:
+			edges = primIndexed(g, root, inTree, edges)
+		default:
+			panic(fmt.Sprintf("graph: unknown PrimMethod %d", method))
+		}
+	}
+	for _, e := range edges {
+		total += e.Weight
+	}
+	return edges, total
+}
+
+func treeEdgeLess[N comparable, W Weight](x, y TreeEdge[N, W]) bool {
+	return x.Weight < y.Weight
+}
+
+func primLazy[N comparable, W Weight](g Adjacency[N, W], root N, inTree map[N]bool, edges []TreeEdge[N, W]) []TreeEdge[N, W] {
+	var h []TreeEdge[N, W]
+	visit := func(u N) {
+		inTree[u] = true
+		for _, e := range g[u] {
+			if !inTree[e.To] {
+				sliceheap.PushFunc(&h, TreeEdge[N, W]{u, e.To, e.Weight}, treeEdgeLess)
+			}
+		}
+	}
+	visit(root)
+	for len(h) > 0 {
+		e := sliceheap.PopFunc(&h, treeEdgeLess)
+		if inTree[e.To] {
+			continue
+		}
+		edges = append(edges, e)
+		visit(e.To)
+	}
+	return edges
+}
+
+func primIndexed[N comparable, W Weight](g Adjacency[N, W], root N, inTree map[N]bool, edges []TreeEdge[N, W]) []TreeEdge[N, W] {
+	// Each node in the heap is keyed by the weight of its cheapest known
+	// edge into the tree.
+	type cand struct {
+		node[N, W]
+		from N
+	}
+	less := func(x, y *cand) bool { return x.d < y.d }
+	set := func(x *cand, i int) { x.index = i }
+
+	var h []*cand
+	cands := make(map[N]*cand)
+	u := root
+	for {
+		inTree[u] = true
+		for _, e := range g[u] {
+			if inTree[e.To] {
+				continue
+			}
+			c, ok := cands[e.To]
+			switch {
+			case !ok:
+				c = &cand{node: node[N, W]{n: e.To, d: e.Weight}, from: u}
+				cands[e.To] = c
+				indexheap.Push(&h, c, less, set)
+			case e.Weight < c.d:
+				c.d, c.from = e.Weight, u
+				indexheap.Up(h, c.index, less, set)
+			}
+		}
+		if len(h) == 0 {
+			return edges
+		}
+		c := indexheap.Pop(&h, less, set)
+		edges = append(edges, TreeEdge[N, W]{c.from, c.n, c.d})
+		u = c.n
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// undirected builds an adjacency listing each edge in both directions.
+func undirected(edges []TreeEdge[int, int]) Adjacency[int, int] {
+	g := Adjacency[int, int]{}
+	for _, e := range edges {
+		g[e.From] = append(g[e.From], Edge[int, int]{e.To, e.Weight})
+		g[e.To] = append(g[e.To], Edge[int, int]{e.From, e.Weight})
+	}
+	return g
+}
+
+// kruskal is a simple reference implementation returning the total weight
+// of a minimum spanning forest.
+func kruskal(edges []TreeEdge[int, int], n int) int {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	sorted := slices.Clone(edges)
+	slices.SortFunc(sorted, func(x, y TreeEdge[int, int]) int { return x.Weight - y.Weight })
+	total := 0
+	for _, e := range sorted {
+		if a, b := find(e.From), find(e.To); a != b {
+			parent[a] = b
+			total += e.Weight
+		}
+	}
+	return total
+}
+
+func TestMinimumSpanningTree(t *testing.T) {
+	const n = 40
+	var edges []TreeEdge[int, int]
+	for i := 0; i < 120; i++ {
+		edges = append(edges, TreeEdge[int, int]{rand.Intn(n), rand.Intn(n), rand.Intn(100)})
+	}
+	g := undirected(edges)
+	want := kruskal(edges, n)
+
+	for _, method := range []PrimMethod{PrimLazy, PrimIndexed} {
+		tree, total := MinimumSpanningTree(g, method)
+		if total != want {
+			t.Errorf("method %d: total = %d; want %d", method, total, want)
+		}
+
+		// The tree edges must be edges of g and must not form a cycle.
+		seen := map[int]bool{}
+		sum := 0
+		for _, e := range tree {
+			found := false
+			for _, ge := range g[e.From] {
+				found = found || ge.To == e.To && ge.Weight == e.Weight
+			}
+			if !found {
+				t.Errorf("method %d: tree edge %v not in graph", method, e)
+			}
+			if seen[e.To] {
+				t.Errorf("method %d: node %d entered twice", method, e.To)
+			}
+			seen[e.To] = true
+			sum += e.Weight
+		}
+		if sum != total {
+			t.Errorf("method %d: edges sum to %d; total = %d", method, sum, total)
+		}
+	}
+}
+
+func TestMinimumSpanningTreeFrom(t *testing.T) {
+	// Three components, one a single node.
+	g := undirected([]TreeEdge[int, int]{{0, 1, 4}, {1, 2, 1}, {0, 2, 2}, {5, 6, 3}})
+	g[9] = nil
+	roots := []int{6, 9, 0, 1, 2, 5}
+	want := []TreeEdge[int, int]{{6, 5, 3}, {0, 2, 2}, {2, 1, 1}}
+	for _, method := range []PrimMethod{PrimLazy, PrimIndexed} {
+		for range 10 {
+			if tree, _ := MinimumSpanningTreeFrom(g, roots, method); !slices.Equal(tree, want) {
+				t.Fatalf("method %d: tree = %v; want %v", method, tree, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "fmt"
+
+// A Reason tells why an element left a container.
+type Reason int
+
+const (
+	Popped   Reason = iota // taken as the next element
+	Removed                // removed explicitly
+	Evicted                // displaced by a better element when full
+	Expired                // its deadline passed
+	Replaced               // superseded by a newer element for the same key
+)
+
+var reasonNames = [...]string{"popped", "removed", "evicted", "expired", "replaced"}
+
+func (r Reason) String() string {
+	if r < 0 || int(r) >= len(reasonNames) {
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+	return reasonNames[r]
+}
+
+// Hooks are optional callbacks, for audit logging or metrics, called by the
+// container types in this package as elements enter and leave them. They
+// are called synchronously, after the container has been updated, and must
+// not modify the container.
+type Hooks[T any] struct {
+	// OnPush is called with each element added.
+	OnPush func(x T)
+
+	// OnLeave is called with each element removed and the reason.
+	OnLeave func(x T, reason Reason)
+}
+
+func (h *Hooks[T]) push(x T) {
+	if h.OnPush != nil {
+		h.OnPush(x)
+	}
+}
+
+func (h *Hooks[T]) leave(x T, reason Reason) {
+	if h.OnLeave != nil {
+		h.OnLeave(x, reason)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordHooks returns hooks that append a description of each event to log.
+func recordHooks[T any](log *[]string) Hooks[T] {
+	return Hooks[T]{
+		OnPush:  func(x T) { *log = append(*log, fmt.Sprintf("+%v", x)) },
+		OnLeave: func(x T, r Reason) { *log = append(*log, fmt.Sprintf("-%v %v", x, r)) },
+	}
+}
+
+func TestHooksTopK(t *testing.T) {
+	var log []string
+	top := NewTopK[int](2)
+	top.Hooks = recordHooks[int](&log)
+	for _, x := range []int{3, 1, 5, 0} {
+		top.Add(x)
+	}
+	want := []string{"+3", "+1", "-1 evicted", "+5"}
+	if !slices.Equal(log, want) {
+		t.Errorf("events %v; want %v", log, want)
+	}
+
+	log = nil
+	keyed := NewKeyedTopK(1, func(x int) int { return x % 10 })
+	keyed.Hooks = recordHooks[int](&log)
+	for _, x := range []int{12, 22, 3} {
+		keyed.Add(x)
+	}
+	want = []string{"+12", "-12 replaced", "+22"}
+	if !slices.Equal(log, want) {
+		t.Errorf("keyed events %v; want %v", log, want)
+	}
+}
+
+func TestHooksQueues(t *testing.T) {
+	var log []string
+	q := NewUpdatableQueue[string, int]()
+	q.Hooks = recordHooks[int](&log)
+	q.PushOrUpdate("a", 1)
+	q.PushOrUpdate("a", 2)
+	q.PushOrUpdate("b", 3)
+	q.Remove("b")
+	q.Pop()
+	want := []string{"+1", "-1 replaced", "+2", "+3", "-3 removed", "-2 popped"}
+	if !slices.Equal(log, want) {
+		t.Errorf("UpdatableQueue events %v; want %v", log, want)
+	}
+
+	log = nil
+	t0 := time.Now()
+	d := DeadlineHeap[string]{Hooks: recordHooks[string](&log)}
+	d.PushAt(t0, "x")
+	d.PushAt(t0.Add(time.Hour), "y")
+	d.PopExpired(t0)
+	want = []string{"+x", "+y", "-x expired"}
+	if !slices.Equal(log, want) {
+		t.Errorf("DeadlineHeap events %v; want %v", log, want)
+	}
+
+	log = nil
+	a := NewAgingQueue[string](func(time.Duration) float64 { return 0 }, time.Second)
+	a.Hooks = recordHooks[string](&log)
+	a.Push("z", 1, t0)
+	a.Pop(t0)
+	want = []string{"+z", "-z popped"}
+	if !slices.Equal(log, want) {
+		t.Errorf("AgingQueue events %v; want %v", log, want)
+	}
+}
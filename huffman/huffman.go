@@ -0,0 +1,92 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package huffman builds Huffman trees and related optimal merge plans by
+// repeatedly combining the two lightest items from a heap.
+package huffman
+
+import "github.com/buth/sliceheap"
+
+// Weight is the constraint for symbol weights.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// A Node is a node of a Huffman tree. A leaf has no children and records the
+// index of its symbol in the weights passed to Build; an internal node has
+// two children, and Symbol -1.
+type Node[W Weight] struct {
+	Weight      W
+	Symbol      int
+	Left, Right *Node[W]
+}
+
+// item is a subtree in the heap. Ties between equal weights are broken by
+// creation order, so that the tree built is deterministic.
+type item[W Weight] struct {
+	n   *Node[W]
+	seq int
+}
+
+func less[W Weight](x, y item[W]) bool {
+	if x.n.Weight != y.n.Weight {
+		return x.n.Weight < y.n.Weight
+	}
+	return x.seq < y.seq
+}
+
+// Build builds a Huffman tree for symbols with the given weights and returns
+// its root together with the code length of each symbol, which is the depth
+// of its leaf. A lone symbol is given a code length of 1. Build returns a nil
+// tree if weights is empty.
+// The complexity is O(n log n) where n = len(weights).
+func Build[W Weight](weights []W) (root *Node[W], lengths []int) {
+	if len(weights) == 0 {
+		return nil, nil
+	}
+	h := make([]item[W], len(weights))
+	for i, w := range weights {
+		h[i] = item[W]{&Node[W]{Weight: w, Symbol: i}, i}
+	}
+	sliceheap.InitFunc(h, less)
+	for seq := len(weights); len(h) > 1; seq++ {
+		x := sliceheap.PopFunc(&h, less)
+		y := sliceheap.PopFunc(&h, less)
+		n := &Node[W]{Weight: x.n.Weight + y.n.Weight, Symbol: -1, Left: x.n, Right: y.n}
+		sliceheap.PushFunc(&h, item[W]{n, seq}, less)
+	}
+	root = h[0].n
+
+	lengths = make([]int, len(weights))
+	var walk func(n *Node[W], depth int)
+	walk = func(n *Node[W], depth int) {
+		if n.Symbol >= 0 {
+			lengths[n.Symbol] = max(depth, 1)
+			return
+		}
+		walk(n.Left, depth+1)
+		walk(n.Right, depth+1)
+	}
+	walk(root, 0)
+	return root, lengths
+}
+
+// OptimalMergeCost returns the minimum total cost of merging files of the
+// given sizes into one, two at a time, where merging two files costs the sum
+// of their sizes. The optimal plan always merges the two smallest files.
+// The complexity is O(n log n) where n = len(sizes).
+func OptimalMergeCost[W Weight](sizes []W) W {
+	h := append([]W(nil), sizes...)
+	sliceheap.Init(h)
+	var cost W
+	for len(h) > 1 {
+		x := sliceheap.Pop(&h)
+		y := sliceheap.Pop(&h)
+		cost += x + y
+		sliceheap.Push(&h, x+y)
+	}
+	return cost
+}
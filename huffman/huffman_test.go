@@ -0,0 +1,71 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package huffman
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	weights := []int{45, 13, 12, 16, 9, 5} // a..f
+	root, lengths := Build(weights)
+
+	if want := []int{1, 3, 3, 3, 4, 4}; !slices.Equal(lengths, want) {
+		t.Errorf("lengths = %v; want %v", lengths, want)
+	}
+	if root.Weight != 100 {
+		t.Errorf("root weight = %d; want 100", root.Weight)
+	}
+
+	// Each internal node weighs the sum of its children, and the leaves
+	// are exactly the symbols.
+	var leaves []int
+	var walk func(n *Node[int])
+	walk = func(n *Node[int]) {
+		if n.Symbol >= 0 {
+			if n.Left != nil || n.Right != nil || n.Weight != weights[n.Symbol] {
+				t.Errorf("bad leaf %+v", n)
+			}
+			leaves = append(leaves, n.Symbol)
+			return
+		}
+		if n.Weight != n.Left.Weight+n.Right.Weight {
+			t.Errorf("internal node weight %d != %d + %d", n.Weight, n.Left.Weight, n.Right.Weight)
+		}
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(root)
+	slices.Sort(leaves)
+	if want := []int{0, 1, 2, 3, 4, 5}; !slices.Equal(leaves, want) {
+		t.Errorf("leaves = %v; want %v", leaves, want)
+	}
+}
+
+func TestBuildSmall(t *testing.T) {
+	if root, lengths := Build[float64](nil); root != nil || lengths != nil {
+		t.Errorf("Build(nil) = %v, %v; want nil, nil", root, lengths)
+	}
+	if _, lengths := Build([]float64{3.5}); !slices.Equal(lengths, []int{1}) {
+		t.Errorf("Build of one symbol gave lengths %v; want [1]", lengths)
+	}
+}
+
+func TestOptimalMergeCost(t *testing.T) {
+	for _, tt := range []struct {
+		sizes []int
+		want  int
+	}{
+		{nil, 0},
+		{[]int{7}, 0},
+		{[]int{2, 3, 4}, 14},
+		{[]int{20, 30, 10, 5, 30}, 205},
+	} {
+		if got := OptimalMergeCost(tt.sizes); got != tt.want {
+			t.Errorf("OptimalMergeCost(%v) = %d; want %d", tt.sizes, got, tt.want)
+		}
+	}
+}
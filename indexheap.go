@@ -0,0 +1,179 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// An IndexHeap is a heap of indices into a slice of keys, ordered by the
+// keys they refer to. It suits data kept as a structure of arrays, such as
+// columns of a table, where the keys are one slice and the payloads others:
+// the heap orders row numbers, and neither the keys nor the payloads are
+// moved or copied into element structs.
+//
+// The IndexHeap refers to the caller's key slice. A key may be changed in
+// place, after which Fix must be called for its index if the index is in
+// the heap.
+type IndexHeap[K any] struct {
+	keys []K
+	less func(x, y K) bool
+	h    []int
+	pos  []int // pos[i] is the position of index i in h, or -1
+}
+
+// ErrKeyDirection is returned by [IndexHeap.DecreaseKey] and
+// [IndexHeap.IncreaseKey] when the new key moves the other way.
+var ErrKeyDirection = errors.New("sliceheap: key changed in the wrong direction")
+
+// NewIndexHeap returns a heap holding every index of keys.
+// The complexity is O(n) where n = len(keys).
+func NewIndexHeap[K cmp.Ordered](keys []K) *IndexHeap[K] {
+	return NewIndexHeapFunc(keys, cmp.Less[K])
+}
+
+// NewIndexHeapFunc is like [NewIndexHeap] but uses a less function to compare elements.
+func NewIndexHeapFunc[K any](keys []K, less func(x, y K) bool) *IndexHeap[K] {
+	h := &IndexHeap[K]{
+		keys: keys,
+		less: less,
+		h:    make([]int, len(keys)),
+		pos:  make([]int, len(keys)),
+	}
+	for i := range h.h {
+		h.h[i] = i
+		h.pos[i] = i
+	}
+	indexheap.Init(h.h, h.lessIndex, h.setPos)
+	return h
+}
+
+func (h *IndexHeap[K]) lessIndex(i, j int) bool {
+	return h.less(h.keys[i], h.keys[j])
+}
+
+func (h *IndexHeap[K]) setPos(i, p int) {
+	h.pos[i] = p
+}
+
+func (h *IndexHeap[K]) check(op string, i int) {
+	if uint(i) >= uint(len(h.keys)) {
+		panicRange(op, i, len(h.keys))
+	}
+}
+
+// Len returns the number of indices in the heap.
+func (h *IndexHeap[K]) Len() int {
+	return len(h.h)
+}
+
+// Contains reports whether index i is in the heap.
+func (h *IndexHeap[K]) Contains(i int) bool {
+	return uint(i) < uint(len(h.pos)) && h.pos[i] >= 0
+}
+
+// Peek returns the index of the least key without removing it, and reports
+// false if the heap is empty.
+// The complexity is O(1).
+func (h *IndexHeap[K]) Peek() (int, bool) {
+	if len(h.h) == 0 {
+		return -1, false
+	}
+	return h.h[0], true
+}
+
+// Pop removes and returns the index of the least key, and reports false if
+// the heap is empty.
+// The complexity is O(log n) where n = h.Len().
+func (h *IndexHeap[K]) Pop() (int, bool) {
+	if len(h.h) == 0 {
+		return -1, false
+	}
+	return indexheap.Pop(&h.h, h.lessIndex, h.setPos), true
+}
+
+// Push adds index i back to the heap.
+// Push panics if i is out of range or already in the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *IndexHeap[K]) Push(i int) {
+	h.check("IndexHeap.Push", i)
+	if h.pos[i] >= 0 {
+		panic(fmt.Sprintf("sliceheap: IndexHeap.Push index %d already in heap", i))
+	}
+	indexheap.Push(&h.h, i, h.lessIndex, h.setPos)
+}
+
+// Remove removes index i from the heap, and reports false if it was not in
+// the heap.
+// Remove panics if i is out of range.
+// The complexity is O(log n) where n = h.Len().
+func (h *IndexHeap[K]) Remove(i int) bool {
+	h.check("IndexHeap.Remove", i)
+	if h.pos[i] < 0 {
+		return false
+	}
+	indexheap.Remove(&h.h, h.pos[i], h.lessIndex, h.setPos)
+	return true
+}
+
+// Fix restores the heap order after the key of index i has changed. It
+// does nothing if i is not in the heap.
+// Fix panics if i is out of range.
+// The complexity is O(log n) where n = h.Len().
+func (h *IndexHeap[K]) Fix(i int) {
+	h.check("IndexHeap.Fix", i)
+	if h.pos[i] >= 0 {
+		indexheap.Fix(h.h, h.pos[i], h.lessIndex, h.setPos)
+	}
+}
+
+// DecreaseKey sets the key of index i to key, which must not order after
+// the current key, and moves i toward the top of the heap. Unlike Fix, it
+// only sifts up. If key orders after the current key, DecreaseKey returns
+// [ErrKeyDirection] and changes nothing.
+// DecreaseKey panics if i is out of range.
+// The complexity is O(log n) where n = h.Len().
+func (h *IndexHeap[K]) DecreaseKey(i int, key K) error {
+	h.check("IndexHeap.DecreaseKey", i)
+	if h.less(h.keys[i], key) {
+		return ErrKeyDirection
+	}
+	h.keys[i] = key
+	if h.pos[i] >= 0 {
+		indexheap.Up(h.h, h.pos[i], h.lessIndex, h.setPos)
+	}
+	return nil
+}
+
+// IncreaseKey is like [IndexHeap.DecreaseKey] for a key that must not order
+// before the current key, and only sifts down.
+func (h *IndexHeap[K]) IncreaseKey(i int, key K) error {
+	h.check("IndexHeap.IncreaseKey", i)
+	if h.less(key, h.keys[i]) {
+		return ErrKeyDirection
+	}
+	h.keys[i] = key
+	if h.pos[i] >= 0 {
+		indexheap.Down(h.h, h.pos[i], h.lessIndex, h.setPos)
+	}
+	return nil
+}
+
+// Queue returns the heap as a [PriorityQueue] of indices. The queue's Push
+// is [IndexHeap.Push], and its Pop and Peek panic if the heap is empty.
+func (h *IndexHeap[K]) Queue() PriorityQueue[int] {
+	return indexQueue[K]{h}
+}
+
+type indexQueue[K any] struct{ h *IndexHeap[K] }
+
+func (q indexQueue[K]) Len() int   { return q.h.Len() }
+func (q indexQueue[K]) Push(i int) { q.h.Push(i) }
+func (q indexQueue[K]) Pop() int   { return nonEmpty(q.h.Pop()) }
+func (q indexQueue[K]) Peek() int  { return nonEmpty(q.h.Peek()) }
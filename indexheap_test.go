@@ -0,0 +1,77 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIndexHeap(t *testing.T) {
+	// Columns of a table: rows are popped by cost.
+	cost := []float64{3.5, 1.25, 9, 0.5, 4}
+	name := []string{"a", "b", "c", "d", "e"}
+	h := NewIndexHeap(cost)
+
+	if i, _ := h.Peek(); name[i] != "d" {
+		t.Errorf("Peek() = %s; want d", name[i])
+	}
+	cost[2] = 0.1
+	h.Fix(2)
+	if !h.Remove(4) || h.Remove(4) || h.Contains(4) {
+		t.Error("Remove(4) did not remove exactly once")
+	}
+
+	var got []string
+	for {
+		i, ok := h.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, name[i])
+	}
+	if want := []string{"c", "d", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+
+	h.Push(4)
+	h.Push(0)
+	if i, _ := h.Pop(); i != 0 || h.Len() != 1 {
+		t.Errorf("Pop() = %d with Len() = %d; want 0, 1", i, h.Len())
+	}
+	h.Fix(1) // not in the heap: ignored
+
+	defer func() {
+		if got := recover(); got != "sliceheap: IndexHeap.Push index 4 already in heap" {
+			t.Errorf("panic = %v", got)
+		}
+	}()
+	h.Push(4)
+}
+
+func TestIndexHeapDecreaseKey(t *testing.T) {
+	keys := []int{50, 40, 30, 20, 10}
+	h := NewIndexHeap(keys)
+	if err := h.DecreaseKey(0, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreaseKey(4, 60); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DecreaseKey(1, 45); err != ErrKeyDirection {
+		t.Errorf("DecreaseKey to a larger key = %v; want ErrKeyDirection", err)
+	}
+	if err := h.IncreaseKey(2, 25); err != ErrKeyDirection {
+		t.Errorf("IncreaseKey to a smaller key = %v; want ErrKeyDirection", err)
+	}
+	var got []int
+	for h.Len() > 0 {
+		i, _ := h.Pop()
+		got = append(got, keys[i])
+	}
+	if want := []int{5, 20, 30, 40, 60}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+}
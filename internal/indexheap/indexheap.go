@@ -0,0 +1,114 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package indexheap provides heap operations that report where elements
+// move. Each operation takes a setIndex function that is called with an
+// element and its new index whenever the element is placed in the heap, and
+// with index -1 when it is removed. Types that need to find their elements
+// later, for Fix or Remove, record the index in the element.
+package indexheap
+
+// Init establishes the heap invariants and records the index of every element.
+// The complexity is O(n) where n = len(h).
+func Init[T any](h []T, less func(x, y T) bool, setIndex func(x T, i int)) {
+	for i, x := range h {
+		setIndex(x, i)
+	}
+	n := len(h)
+	for i := n/2 - 1; i >= 0; i-- {
+		down(h, i, n, less, setIndex)
+	}
+}
+
+// Push pushes the element x onto the heap.
+// The complexity is O(log n) where n = len(h).
+func Push[T any](h *[]T, x T, less func(x, y T) bool, setIndex func(x T, i int)) {
+	*h = append(*h, x)
+	setIndex(x, len(*h)-1)
+	up(*h, len(*h)-1, less, setIndex)
+}
+
+// Pop removes and returns the minimum element (according to less) from the heap.
+// The complexity is O(log n) where n = len(h).
+func Pop[T any](h *[]T, less func(x, y T) bool, setIndex func(x T, i int)) T {
+	return Remove(h, 0, less, setIndex)
+}
+
+// Remove removes and returns the element at index i from the heap.
+// The complexity is O(log n) where n = len(h).
+func Remove[T any](h *[]T, i int, less func(x, y T) bool, setIndex func(x T, i int)) T {
+	n := len(*h) - 1
+	x := (*h)[i]
+	if n != i {
+		(*h)[i] = (*h)[n]
+		setIndex((*h)[i], i)
+		if !down(*h, i, n, less, setIndex) {
+			up(*h, i, less, setIndex)
+		}
+	}
+	var zero T
+	(*h)[n] = zero
+	*h = (*h)[:n]
+	setIndex(x, -1)
+	return x
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value.
+// The complexity is O(log n) where n = len(h).
+func Fix[T any](h []T, i int, less func(x, y T) bool, setIndex func(x T, i int)) {
+	if !down(h, i, len(h), less, setIndex) {
+		up(h, i, less, setIndex)
+	}
+}
+
+// Up moves the element at index i towards the root, for use after its value
+// has decreased.
+// The complexity is O(log n) where n = len(h).
+func Up[T any](h []T, i int, less func(x, y T) bool, setIndex func(x T, i int)) {
+	up(h, i, less, setIndex)
+}
+
+// Down moves the element at index i towards the leaves, for use after its
+// value has increased.
+// The complexity is O(log n) where n = len(h).
+func Down[T any](h []T, i int, less func(x, y T) bool, setIndex func(x T, i int)) {
+	down(h, i, len(h), less, setIndex)
+}
+
+func swap[T any](h []T, i, j int, setIndex func(x T, i int)) {
+	h[i], h[j] = h[j], h[i]
+	setIndex(h[i], i)
+	setIndex(h[j], j)
+}
+
+func up[T any](h []T, j int, less func(x, y T) bool, setIndex func(x T, i int)) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !less(h[j], h[i]) {
+			break
+		}
+		swap(h, i, j, setIndex)
+		j = i
+	}
+}
+
+func down[T any](h []T, i0, n int, less func(x, y T) bool, setIndex func(x T, i int)) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && less(h[j2], h[j1]) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !less(h[j], h[i]) {
+			break
+		}
+		swap(h, i, j, setIndex)
+		i = j
+	}
+	return i > i0
+}
@@ -0,0 +1,78 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package indexheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type item struct {
+	v     int
+	index int
+}
+
+func less(x, y *item) bool { return x.v < y.v }
+
+func setIndex(x *item, i int) { x.index = i }
+
+func verify(t *testing.T, h []*item) {
+	t.Helper()
+	for i, x := range h {
+		if x.index != i {
+			t.Fatalf("element at %d records index %d", i, x.index)
+		}
+		if i > 0 && less(x, h[(i-1)/2]) {
+			t.Fatalf("heap invariant invalidated at %d", i)
+		}
+	}
+}
+
+func TestIndexTracking(t *testing.T) {
+	var items []*item
+	for i := 0; i < 50; i++ {
+		items = append(items, &item{v: rand.Intn(100)})
+	}
+	h := append([]*item(nil), items[:25]...)
+	Init(h, less, setIndex)
+	verify(t, h)
+	for _, x := range items[25:] {
+		Push(&h, x, less, setIndex)
+		verify(t, h)
+	}
+
+	for i := 0; i < 100; i++ {
+		x := items[rand.Intn(len(items))]
+		if x.index < 0 {
+			continue
+		}
+		switch i % 4 {
+		case 0:
+			x.v = rand.Intn(100)
+			Fix(h, x.index, less, setIndex)
+		case 1:
+			x.v -= rand.Intn(10)
+			Up(h, x.index, less, setIndex)
+		case 2:
+			x.v += rand.Intn(10)
+			Down(h, x.index, less, setIndex)
+		case 3:
+			if Remove(&h, x.index, less, setIndex) != x || x.index != -1 {
+				t.Fatalf("Remove did not remove %v", x)
+			}
+		}
+		verify(t, h)
+	}
+
+	prev := -1 << 31
+	for len(h) > 0 {
+		x := Pop(&h, less, setIndex)
+		if x.v < prev || x.index != -1 {
+			t.Fatalf("Pop() = %+v after %d", x, prev)
+		}
+		prev = x.v
+		verify(t, h)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package notify provides the wake-up channels shared by the blocking
+// queues. A waiter takes the channel from Wait under its lock, unlocks and
+// receives from it; a notifier calls Close under the same lock to wake
+// every waiter at once. A nil channel means nobody is waiting.
+package notify
+
+// Close closes *c, if not nil, to wake its waiters.
+func Close(c *chan struct{}) {
+	if *c != nil {
+		close(*c)
+		*c = nil
+	}
+}
+
+// Wait returns the channel *c, creating it if needed.
+func Wait(c *chan struct{}) chan struct{} {
+	if *c == nil {
+		*c = make(chan struct{})
+	}
+	return *c
+}
@@ -0,0 +1,133 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package interval schedules half-open intervals onto a minimal set of
+// resources, such as meetings onto rooms, using a heap of end times.
+package interval
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/buth/sliceheap"
+)
+
+// An Interval is the half-open interval [Start, End).
+type Interval[T any] struct {
+	Start, End T
+}
+
+// An Assigner assigns a stream of intervals, arriving in order of start, to
+// numbered rooms such that no two overlapping intervals share a room and as
+// few rooms as possible are used. Rooms are numbered from 0, and a freed
+// room is reused before a new one is opened.
+//
+// An Assigner is not safe for concurrent use.
+type Assigner[T any] struct {
+	less    func(x, y T) bool
+	busy    []occupied[T] // min-heap by end
+	free    []int         // min-heap of free rooms
+	rooms   int
+	started bool
+	last    T // start of the last interval added
+}
+
+type occupied[T any] struct {
+	end  T
+	room int
+}
+
+// NewAssigner returns an assigner for intervals of ordered values.
+func NewAssigner[T cmp.Ordered]() *Assigner[T] {
+	return NewAssignerFunc(cmp.Less[T])
+}
+
+// NewAssignerFunc is like [NewAssigner] but uses a less function to compare values.
+func NewAssignerFunc[T any](less func(x, y T) bool) *Assigner[T] {
+	return &Assigner[T]{less: less}
+}
+
+func (a *Assigner[T]) endLess(x, y occupied[T]) bool {
+	return a.less(x.end, y.end)
+}
+
+// Add assigns iv to a room and returns the room. It panics if iv starts
+// before the previous interval added.
+// The complexity is O(log k) where k = a.Rooms().
+func (a *Assigner[T]) Add(iv Interval[T]) int {
+	if a.started && a.less(iv.Start, a.last) {
+		panic("interval: intervals added out of order")
+	}
+	a.started, a.last = true, iv.Start
+	// Free every room whose interval ended at or before this one starts.
+	for len(a.busy) > 0 && !a.less(iv.Start, a.busy[0].end) {
+		sliceheap.Push(&a.free, sliceheap.PopFunc(&a.busy, a.endLess).room)
+	}
+	var room int
+	if len(a.free) > 0 {
+		room = sliceheap.Pop(&a.free)
+	} else {
+		room = a.rooms
+		a.rooms++
+	}
+	sliceheap.PushFunc(&a.busy, occupied[T]{iv.End, room}, a.endLess)
+	return room
+}
+
+// Active returns the number of rooms in use by the last interval added and
+// the intervals overlapping it.
+func (a *Assigner[T]) Active() int {
+	return len(a.busy)
+}
+
+// Rooms returns the number of rooms opened, which is the greatest number of
+// intervals that have overlapped at any point.
+func (a *Assigner[T]) Rooms() int {
+	return a.rooms
+}
+
+// AssignRooms assigns each of intervals to a room, returning the room of
+// each interval and the number of rooms used, which is the minimum possible.
+// The complexity is O(n log n) where n = len(intervals).
+func AssignRooms[T cmp.Ordered](intervals []Interval[T]) (rooms []int, n int) {
+	return AssignRoomsFunc(intervals, cmp.Less[T])
+}
+
+// AssignRoomsFunc is like [AssignRooms] but uses a less function to compare values.
+func AssignRoomsFunc[T any](intervals []Interval[T], less func(x, y T) bool) (rooms []int, n int) {
+	order := make([]int, len(intervals))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		switch {
+		case less(intervals[i].Start, intervals[j].Start):
+			return -1
+		case less(intervals[j].Start, intervals[i].Start):
+			return 1
+		}
+		return 0
+	})
+
+	a := NewAssignerFunc(less)
+	rooms = make([]int, len(intervals))
+	for _, i := range order {
+		rooms[i] = a.Add(intervals[i])
+	}
+	return rooms, a.Rooms()
+}
+
+// MaxOverlap returns the greatest number of intervals that overlap at any
+// point.
+// The complexity is O(n log n) where n = len(intervals).
+func MaxOverlap[T cmp.Ordered](intervals []Interval[T]) int {
+	_, n := AssignRooms(intervals)
+	return n
+}
+
+// MaxOverlapFunc is like [MaxOverlap] but uses a less function to compare values.
+func MaxOverlapFunc[T any](intervals []Interval[T], less func(x, y T) bool) int {
+	_, n := AssignRoomsFunc(intervals, less)
+	return n
+}
@@ -0,0 +1,83 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interval
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestAssignRooms(t *testing.T) {
+	ivs := []Interval[int]{
+		{0, 30},
+		{5, 10},
+		{15, 20},
+		{10, 15}, // starts as {5, 10} ends: reuses its room
+		{25, 35},
+	}
+	rooms, n := AssignRooms(ivs)
+	if n != 2 {
+		t.Errorf("AssignRooms used %d rooms; want 2", n)
+	}
+	for i := range ivs {
+		for j := i + 1; j < len(ivs); j++ {
+			overlap := ivs[i].Start < ivs[j].End && ivs[j].Start < ivs[i].End
+			if overlap && rooms[i] == rooms[j] {
+				t.Errorf("overlapping %v and %v share room %d", ivs[i], ivs[j], rooms[i])
+			}
+		}
+	}
+}
+
+func TestMaxOverlap(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		var ivs []Interval[int]
+		for j := 0; j < 30; j++ {
+			s := rand.Intn(100)
+			ivs = append(ivs, Interval[int]{s, s + 1 + rand.Intn(20)})
+		}
+
+		want := 0
+		for p := 0; p < 130; p++ {
+			c := 0
+			for _, iv := range ivs {
+				if iv.Start <= p && p < iv.End {
+					c++
+				}
+			}
+			want = max(want, c)
+		}
+		if got := MaxOverlap(ivs); got != want {
+			t.Fatalf("MaxOverlap(%v) = %d; want %d", ivs, got, want)
+		}
+	}
+}
+
+func TestAssignerTimes(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	a := NewAssignerFunc(time.Time.Before)
+	hour := func(h float64) time.Time { return t0.Add(time.Duration(h * float64(time.Hour))) }
+
+	if r := a.Add(Interval[time.Time]{hour(0), hour(2)}); r != 0 {
+		t.Errorf("first meeting in room %d; want 0", r)
+	}
+	if r := a.Add(Interval[time.Time]{hour(1), hour(3)}); r != 1 {
+		t.Errorf("second meeting in room %d; want 1", r)
+	}
+	if r := a.Add(Interval[time.Time]{hour(2), hour(4)}); r != 0 {
+		t.Errorf("third meeting in room %d; want 0", r)
+	}
+	if a.Active() != 2 || a.Rooms() != 2 {
+		t.Errorf("Active, Rooms = %d, %d; want 2, 2", a.Active(), a.Rooms())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("out-of-order Add did not panic")
+		}
+	}()
+	a.Add(Interval[time.Time]{hour(1), hour(5)})
+}
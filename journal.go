@@ -0,0 +1,174 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// A Journal performs heap operations on a slice and can record them, so
+// that a multi-step update can be abandoned and the heap restored exactly
+// as it was, for example after a speculative scheduling decision turns out
+// to be wrong. Between Begin and Commit or Rollback, each operation logs
+// the previous values of the O(log n) positions it changes.
+//
+// Outside such a transaction a Journal records nothing. The heap must not
+// be changed by other means during a transaction.
+type Journal[T any] struct {
+	h         *[]T
+	less      func(x, y T) bool
+	sw        SwapperHeap
+	recording bool
+	ops       []journalOp[T]
+}
+
+// A journalOp records the length of the heap before an operation and the
+// previous values of the positions the operation wrote.
+type journalOp[T any] struct {
+	n      int
+	writes []journalWrite[T]
+}
+
+type journalWrite[T any] struct {
+	i   int
+	old T
+}
+
+// NewJournal returns a journal for operations on the heap *h.
+func NewJournal[T cmp.Ordered](h *[]T) *Journal[T] {
+	return NewJournalFunc(h, cmp.Less[T])
+}
+
+// NewJournalFunc is like [NewJournal] but uses a less function to compare elements.
+func NewJournalFunc[T any](h *[]T, less func(x, y T) bool) *Journal[T] {
+	j := &Journal[T]{h: h, less: less}
+	j.sw = SwapperHeap{Less: j.lessAt, Swap: j.swap}
+	return j
+}
+
+// Begin starts recording operations, discarding any earlier record.
+func (j *Journal[T]) Begin() {
+	j.recording = true
+	j.ops = j.ops[:0]
+}
+
+// Commit stops recording and discards the record, keeping the changes.
+func (j *Journal[T]) Commit() {
+	j.recording = false
+	clear(j.ops)
+	j.ops = j.ops[:0]
+}
+
+// Rollback undoes every operation since Begin, restoring the heap to the
+// same elements in the same positions, and stops recording.
+// The complexity is O(k log n) for k recorded operations.
+func (j *Journal[T]) Rollback() {
+	h := *j.h
+	for k := len(j.ops) - 1; k >= 0; k-- {
+		op := j.ops[k]
+		if op.n > len(h) {
+			h = h[:op.n] // the removed slot is restored below
+		}
+		for w := len(op.writes) - 1; w >= 0; w-- {
+			h[op.writes[w].i] = op.writes[w].old
+		}
+		h = h[:op.n]
+	}
+	*j.h = h
+	j.Commit()
+}
+
+// begin starts logging a new operation.
+func (j *Journal[T]) begin() {
+	if j.recording {
+		j.ops = append(j.ops, journalOp[T]{n: len(*j.h)})
+	}
+}
+
+// set sets (*j.h)[i] = x, logging the previous value.
+func (j *Journal[T]) set(i int, x T) {
+	h := *j.h
+	if j.recording {
+		op := &j.ops[len(j.ops)-1]
+		op.writes = append(op.writes, journalWrite[T]{i, h[i]})
+	}
+	h[i] = x
+}
+
+// lessAt and swap are the Less and Swap of the SwapperHeap through which
+// the journal sifts, so that swap logs every position a sift writes.
+func (j *Journal[T]) lessAt(a, b int) bool {
+	h := *j.h
+	return j.less(h[a], h[b])
+}
+
+func (j *Journal[T]) swap(a, b int) {
+	h := *j.h
+	x, y := h[a], h[b]
+	j.set(a, y)
+	j.set(b, x)
+}
+
+// Len returns the number of elements in the journal's heap.
+func (j *Journal[T]) Len() int {
+	return len(*j.h)
+}
+
+// Push is like [PushFunc] for the journal's heap.
+func (j *Journal[T]) Push(x T) {
+	j.begin()
+	*j.h = append(*j.h, x)
+	j.sw.up(len(*j.h) - 1)
+}
+
+// Pop is like [PopFunc] for the journal's heap.
+func (j *Journal[T]) Pop() T {
+	if len(*j.h) == 0 {
+		panic("sliceheap: Pop on empty heap")
+	}
+	return j.Remove(0)
+}
+
+// Peek returns the least element of the journal's heap without removing
+// it. Peek panics if the heap is empty.
+func (j *Journal[T]) Peek() T {
+	if len(*j.h) == 0 {
+		panic("sliceheap: Peek on empty heap")
+	}
+	return (*j.h)[0]
+}
+
+// Remove is like [RemoveFunc] for the journal's heap.
+func (j *Journal[T]) Remove(i int) T {
+	h := *j.h
+	n := len(h) - 1
+	if uint(i) > uint(n) {
+		panicRange("Remove", i, n+1)
+	}
+	j.begin()
+	x := h[i]
+	if n != i {
+		j.set(i, h[n])
+	}
+	// Zero the vacated slot through set so that Rollback restores it.
+	var zero T
+	j.set(n, zero)
+	*j.h = h[:n]
+	if n != i && !j.sw.down(i, len(*j.h)) {
+		j.sw.up(i)
+	}
+	return x
+}
+
+// Update replaces the element at index i with x and restores the heap
+// ordering, like setting the element and calling [FixFunc].
+func (j *Journal[T]) Update(i int, x T) {
+	if n := len(*j.h); uint(i) >= uint(n) {
+		panicRange("Update", i, n)
+	}
+	j.begin()
+	j.set(i, x)
+	if !j.sw.down(i, len(*j.h)) {
+		j.sw.up(i)
+	}
+}
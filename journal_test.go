@@ -0,0 +1,76 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	var h []int
+	j := NewJournal(&h)
+	for range 100 {
+		j.Push(rand.Intn(1000))
+	}
+	verify(t, h)
+
+	for trial := range 50 {
+		before := slices.Clone(h)
+		j.Begin()
+		for range rand.Intn(40) {
+			switch rand.Intn(4) {
+			case 0:
+				j.Push(rand.Intn(1000))
+			case 1:
+				if len(h) > 0 {
+					j.Pop()
+				}
+			case 2:
+				if len(h) > 0 {
+					j.Remove(rand.Intn(len(h)))
+				}
+			case 3:
+				if len(h) > 0 {
+					j.Update(rand.Intn(len(h)), rand.Intn(1000))
+				}
+			}
+			verify(t, h)
+		}
+		if trial%2 == 0 {
+			j.Rollback()
+			if !slices.Equal(h, before) {
+				t.Fatalf("trial %d: Rollback restored %v; want %v", trial, h, before)
+			}
+		} else {
+			j.Commit()
+		}
+	}
+
+	// Operations outside a transaction are not recorded.
+	j.Push(-1)
+	j.Begin()
+	j.Rollback()
+	if h[0] != -1 {
+		t.Errorf("Rollback undid an operation made before Begin")
+	}
+}
+
+func TestJournalDrainAndRefill(t *testing.T) {
+	h := []int{1, 2, 3}
+	j := NewJournal(&h)
+	j.Begin()
+	for len(h) > 0 {
+		j.Pop()
+	}
+	for i := range 10 {
+		j.Push(i + 10) // reallocates the slice
+	}
+	j.Rollback()
+	if !slices.Equal(h, []int{1, 2, 3}) {
+		t.Errorf("Rollback restored %v; want [1 2 3]", h)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// LessBy returns a less function ordering elements by the ordered key that
+// key returns for them, smallest first. For example, given a job type with a
+// Priority field:
+//
+//	less := sliceheap.LessBy(func(j Job) int { return j.Priority })
+//
+// Unlike [LessForType], no reflection is involved.
+func LessBy[T any, K cmp.Ordered](key func(T) K) func(x, y T) bool {
+	return func(x, y T) bool {
+		return cmp.Less(key(x), key(y))
+	}
+}
+
+// DescBy is like [LessBy] but orders elements by key, largest first.
+func DescBy[T any, K cmp.Ordered](key func(T) K) func(x, y T) bool {
+	return func(x, y T) bool {
+		return cmp.Less(key(y), key(x))
+	}
+}
+
+// ThenBy returns a less function that orders elements by the first of the
+// given less functions that distinguishes them, so that each breaks the
+// ties of those before it:
+//
+//	less := sliceheap.ThenBy(
+//		sliceheap.DescBy(func(j Job) int { return j.Priority }),
+//		sliceheap.LessBy(func(j Job) string { return j.Name }),
+//	)
+//
+// Elements that no function distinguishes are equal.
+//
+// A less function cannot tell equal elements from greater ones in one
+// call, so ThenBy calls each function up to twice per comparison, and a
+// function built by LessBy evaluates its key up to four times. To order
+// by keys, [ThenCompare] with [CompareBy] costs one key comparison per
+// key.
+func ThenBy[T any](less ...func(x, y T) bool) func(x, y T) bool {
+	less = append([]func(x, y T) bool(nil), less...)
+	return func(x, y T) bool {
+		for _, l := range less {
+			if l(x, y) {
+				return true
+			}
+			if l(y, x) {
+				return false
+			}
+		}
+		return false
+	}
+}
+
+// CompareBy returns a comparison function ordering elements by the ordered
+// key that key returns for them, smallest first, for use with
+// [ThenCompare]. It calls key once for each element compared.
+func CompareBy[T any, K cmp.Ordered](key func(T) K) func(x, y T) int {
+	return func(x, y T) int {
+		return cmp.Compare(key(x), key(y))
+	}
+}
+
+// CompareDescBy is like [CompareBy] but orders elements by key, largest
+// first.
+func CompareDescBy[T any, K cmp.Ordered](key func(T) K) func(x, y T) int {
+	return func(x, y T) int {
+		return cmp.Compare(key(y), key(x))
+	}
+}
+
+// ThenCompare is like [ThenBy] but takes comparison functions, which
+// return a negative number, zero or a positive number as x orders before,
+// with or after y, as [cmp.Compare] does. Each is called at most once per
+// comparison:
+//
+//	less := sliceheap.ThenCompare(
+//		sliceheap.CompareDescBy(func(j Job) int { return j.Priority }),
+//		sliceheap.CompareBy(func(j Job) string { return j.Name }),
+//	)
+func ThenCompare[T any](cmps ...func(x, y T) int) func(x, y T) bool {
+	cmps = append([]func(x, y T) int(nil), cmps...)
+	return func(x, y T) bool {
+		for _, c := range cmps {
+			if r := c(x, y); r != 0 {
+				return r < 0
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLessBy(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+	less := ThenBy(
+		DescBy(func(j job) int { return j.priority }),
+		LessBy(func(j job) string { return j.name }),
+	)
+	h := []job{{"c", 1}, {"b", 2}, {"a", 1}, {"d", 2}, {"e", 3}}
+	InitFunc(h, less)
+	var got []string
+	for len(h) > 0 {
+		got = append(got, PopFunc(&h, less).name)
+	}
+	if s := strings.Join(got, ""); s != "ebdac" {
+		t.Errorf("popped %q; want \"ebdac\"", s)
+	}
+
+	if err := CheckLess(less, []job{{"a", 1}, {"a", 1}, {"b", 1}, {"a", 2}}); err != nil {
+		t.Error(err)
+	}
+	if ThenBy[job]()(job{"a", 1}, job{"b", 2}) {
+		t.Error("ThenBy() with no functions distinguished elements")
+	}
+}
+
+func TestThenCompare(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+	calls := 0
+	priority := func(j job) int { calls++; return j.priority }
+	less := ThenCompare(
+		CompareDescBy(priority),
+		CompareBy(func(j job) string { return j.name }),
+	)
+	h := []job{{"c", 1}, {"b", 2}, {"a", 1}, {"d", 2}, {"e", 3}}
+	InitFunc(h, less)
+	var got []string
+	for len(h) > 0 {
+		got = append(got, PopFunc(&h, less).name)
+	}
+	if s := strings.Join(got, ""); s != "ebdac" {
+		t.Errorf("popped %q; want \"ebdac\"", s)
+	}
+
+	calls = 0
+	less(job{"a", 1}, job{"b", 1})
+	if calls != 2 {
+		t.Errorf("comparing equal priorities evaluated the key %d times; want 2", calls)
+	}
+	if ThenCompare[job]()(job{"a", 1}, job{"b", 2}) {
+		t.Error("ThenCompare() with no functions distinguished elements")
+	}
+}
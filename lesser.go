@@ -0,0 +1,41 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+// Lesser is the constraint for types that define their own ordering with a
+// Less method, which reports whether the receiver orders before other and
+// must be a strict weak order.
+type Lesser[T any] interface {
+	Less(other T) bool
+}
+
+func selfLess[T Lesser[T]](x, y T) bool {
+	return x.Less(y)
+}
+
+// InitSelf is like [Init] but orders elements by their Less method.
+func InitSelf[T Lesser[T]](h []T) {
+	InitFunc(h, selfLess[T])
+}
+
+// PushSelf is like [Push] but orders elements by their Less method.
+func PushSelf[T Lesser[T]](h *[]T, x T) {
+	PushFunc(h, x, selfLess[T])
+}
+
+// PopSelf is like [Pop] but orders elements by their Less method.
+func PopSelf[T Lesser[T]](h *[]T) T {
+	return PopFunc(h, selfLess[T])
+}
+
+// RemoveSelf is like [Remove] but orders elements by their Less method.
+func RemoveSelf[T Lesser[T]](h *[]T, i int) T {
+	return RemoveFunc(h, i, selfLess[T])
+}
+
+// FixSelf is like [Fix] but orders elements by their Less method.
+func FixSelf[T Lesser[T]](h []T, i int) {
+	FixFunc(h, i, selfLess[T])
+}
@@ -0,0 +1,46 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+type event struct {
+	at   time.Time
+	name string
+}
+
+func (e event) Less(other event) bool {
+	return e.at.Before(other.at)
+}
+
+func TestSelf(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := []event{
+		{t0.Add(3 * time.Hour), "c"},
+		{t0.Add(1 * time.Hour), "a"},
+		{t0.Add(4 * time.Hour), "d"},
+	}
+	InitSelf(h)
+	PushSelf(&h, event{t0.Add(2 * time.Hour), "b"})
+	PushSelf(&h, event{t0.Add(5 * time.Hour), "e"})
+
+	i := slices.IndexFunc(h, func(e event) bool { return e.name == "d" })
+	h[i].at = t0
+	FixSelf(h, i)
+	i = slices.IndexFunc(h, func(e event) bool { return e.name == "e" })
+	RemoveSelf(&h, i)
+
+	var got string
+	for len(h) > 0 {
+		got += PopSelf(&h).name
+	}
+	if got != "dabc" {
+		t.Errorf("popped %q; want \"dabc\"", got)
+	}
+}
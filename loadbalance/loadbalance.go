@@ -0,0 +1,147 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package loadbalance tracks the load on a set of backends and picks the
+// least loaded.
+package loadbalance
+
+import (
+	"sync"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Backend is a handle to a backend registered with a LoadTracker.
+type Backend[B any] struct {
+	Value B
+	load  int
+	used  uint64 // when load was last added, for rotating among ties
+	index int    // index in the heap, or -1 once unregistered
+}
+
+// A LoadTracker keeps backends in a heap ordered by their outstanding load.
+// Backends with equal load are ordered by when they were last given work,
+// so that ties are spread round-robin.
+//
+// The zero value is an empty tracker ready to use. A LoadTracker is safe for
+// concurrent use by multiple goroutines.
+type LoadTracker[B any] struct {
+	mu    sync.Mutex
+	h     []*Backend[B]
+	clock uint64
+}
+
+func less[B any](x, y *Backend[B]) bool {
+	if x.load != y.load {
+		return x.load < y.load
+	}
+	return x.used < y.used
+}
+
+func setIndex[B any](x *Backend[B], i int) {
+	x.index = i
+}
+
+// Register adds a backend with no load and returns its handle.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) Register(v B) *Backend[B] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clock++
+	b := &Backend[B]{Value: v, used: t.clock}
+	indexheap.Push(&t.h, b, less, setIndex)
+	return b
+}
+
+// Unregister removes a backend. It reports false if b was not registered.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) Unregister(b *Backend[B]) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.registered(b) {
+		return false
+	}
+	indexheap.Remove(&t.h, b.index, less, setIndex)
+	return true
+}
+
+func (t *LoadTracker[B]) registered(b *Backend[B]) bool {
+	return b.index >= 0 && b.index < len(t.h) && t.h[b.index] == b
+}
+
+// Len returns the number of registered backends.
+func (t *LoadTracker[B]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.h)
+}
+
+// PickLeast returns the least-loaded backend without changing its load.
+// It reports false if no backends are registered.
+func (t *LoadTracker[B]) PickLeast() (*Backend[B], bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.h) == 0 {
+		return nil, false
+	}
+	return t.h[0], true
+}
+
+// Acquire picks the least-loaded backend and adds one to its load in a single
+// step, so that concurrent callers are spread across backends. The caller
+// must call Done when the work is finished. Acquire reports false if no
+// backends are registered.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) Acquire() (*Backend[B], bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.h) == 0 {
+		return nil, false
+	}
+	b := t.h[0]
+	t.add(b, 1)
+	return b, true
+}
+
+// Add adds one to the load of b. It reports false if b is not registered.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) Add(b *Backend[B]) bool {
+	return t.AddN(b, 1)
+}
+
+// Done subtracts one from the load of b. It reports false if b is not
+// registered.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) Done(b *Backend[B]) bool {
+	return t.AddN(b, -1)
+}
+
+// AddN adds delta, which may be negative, to the load of b. It reports false
+// if b is not registered.
+// The complexity is O(log n) where n = t.Len().
+func (t *LoadTracker[B]) AddN(b *Backend[B], delta int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.registered(b) {
+		return false
+	}
+	t.add(b, delta)
+	return true
+}
+
+func (t *LoadTracker[B]) add(b *Backend[B], delta int) {
+	b.load += delta
+	if delta > 0 {
+		t.clock++
+		b.used = t.clock
+	}
+	indexheap.Fix(t.h, b.index, less, setIndex)
+}
+
+// Load returns the current load of b.
+func (t *LoadTracker[B]) Load(b *Backend[B]) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return b.load
+}
@@ -0,0 +1,77 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loadbalance
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLoadTracker(t *testing.T) {
+	var lt LoadTracker[string]
+	a := lt.Register("a")
+	b := lt.Register("b")
+	c := lt.Register("c")
+
+	// Equal loads are handed out round-robin.
+	var picked string
+	for i := 0; i < 6; i++ {
+		x, _ := lt.Acquire()
+		picked += x.Value
+	}
+	if picked != "abcabc" {
+		t.Errorf("Acquire order %q; want \"abcabc\"", picked)
+	}
+
+	lt.Done(b)
+	lt.Done(b)
+	if x, _ := lt.PickLeast(); x != b {
+		t.Errorf("PickLeast() = %s; want b", x.Value)
+	}
+	lt.AddN(b, 5)
+	if x, _ := lt.PickLeast(); x != a {
+		t.Errorf("PickLeast() = %s; want a", x.Value)
+	}
+	if n := lt.Load(b); n != 5 {
+		t.Errorf("Load(b) = %d; want 5", n)
+	}
+
+	if !lt.Unregister(a) || lt.Unregister(a) {
+		t.Error("Unregister(a) did not remove exactly once")
+	}
+	if lt.Add(a) {
+		t.Error("Add on unregistered backend succeeded")
+	}
+	if x, _ := lt.PickLeast(); x != c {
+		t.Errorf("PickLeast() = %s; want c", x.Value)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	var lt LoadTracker[int]
+	var backends []*Backend[int]
+	for i := 0; i < 8; i++ {
+		backends = append(backends, lt.Register(i))
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				b, _ := lt.Acquire()
+				lt.Done(b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, b := range backends {
+		if n := lt.Load(b); n != 0 {
+			t.Errorf("backend %d has load %d; want 0", b.Value, n)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package logmerge merges time-ordered record streams, such as the logs of
+// several shards, into a single stream in time order.
+package logmerge
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/buth/sliceheap"
+)
+
+// A Merger merges sources of records into one stream ordered by time. Each
+// source must be in time order up to Slack: no record may be older than
+// Slack before the newest record preceding it in its source. The records of
+// each source are passed through a reorder buffer, a heap holding the
+// records that a later record could still precede, and the reordered
+// sources are then merged through a heap.
+//
+// Time must be set; the other fields are optional.
+type Merger[T any] struct {
+	// Time returns the timestamp of a record.
+	Time func(T) time.Time
+
+	// Slack is the greatest amount by which a record may be out of order
+	// within its source.
+	Slack time.Duration
+
+	// Late, if not nil, is called with any record that violates the
+	// ordering bound, which is then dropped. Otherwise such records are
+	// emitted as soon as they are read, out of order.
+	Late func(T)
+}
+
+func (m *Merger[T]) less(x, y T) bool {
+	return m.Time(x).Before(m.Time(y))
+}
+
+// Merge returns an iterator over the records of sources in time order.
+// Records with equal timestamps keep the order of their sources.
+func (m *Merger[T]) Merge(sources ...iter.Seq[T]) iter.Seq[T] {
+	seqs := make([]iter.Seq[T], len(sources))
+	for i, s := range sources {
+		seqs[i] = m.reorder(s)
+	}
+	return sliceheap.MergeSeqsFunc(m.less, seqs...)
+}
+
+// reorder returns src with its records sorted, given that they are out of
+// order by at most m.Slack.
+func (m *Merger[T]) reorder(src iter.Seq[T]) iter.Seq[T] {
+	type entry struct {
+		r   T
+		t   time.Time
+		seq uint64
+	}
+	less := func(x, y entry) bool {
+		if !x.t.Equal(y.t) {
+			return x.t.Before(y.t)
+		}
+		return x.seq < y.seq
+	}
+	return func(yield func(T) bool) {
+		var h []entry
+		var seq uint64
+		var newest, emitted time.Time
+		started := false
+		for r := range src {
+			t := m.Time(r)
+			if started && t.Before(emitted) {
+				// A record already emitted is newer than r.
+				if m.Late != nil {
+					m.Late(r)
+					continue
+				}
+				if !yield(r) {
+					return
+				}
+				continue
+			}
+			if !started || t.After(newest) {
+				newest, started = t, true
+			}
+			sliceheap.PushFunc(&h, entry{r, t, seq}, less)
+			seq++
+
+			// No later record can be older than newest - Slack.
+			horizon := newest.Add(-m.Slack)
+			for len(h) > 0 && !h[0].t.After(horizon) {
+				e := sliceheap.PopFunc(&h, less)
+				emitted = e.t
+				if !yield(e.r) {
+					return
+				}
+			}
+		}
+		for len(h) > 0 {
+			if !yield(sliceheap.PopFunc(&h, less).r) {
+				return
+			}
+		}
+	}
+}
+
+// A Reader reads records, one per line, from an io.Reader.
+type Reader[T any] struct {
+	s     *bufio.Scanner
+	parse func(line []byte) (T, error)
+	err   error
+}
+
+// NewReader returns a Reader that reads lines from r and converts them to
+// records with parse.
+func NewReader[T any](r io.Reader, parse func(line []byte) (T, error)) *Reader[T] {
+	return &Reader[T]{s: bufio.NewScanner(r), parse: parse}
+}
+
+// All returns an iterator over the records. Iteration stops at the first
+// read or parse error, which is then reported by Err.
+func (r *Reader[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for r.s.Scan() {
+			x, err := r.parse(r.s.Bytes())
+			if err != nil {
+				r.err = err
+				return
+			}
+			if !yield(x) {
+				return
+			}
+		}
+		r.err = r.s.Err()
+	}
+}
+
+// Err returns the first error encountered by All, if any.
+func (r *Reader[T]) Err() error {
+	return r.err
+}
@@ -0,0 +1,94 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logmerge
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type record struct {
+	at  time.Duration
+	src string
+}
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func recordTime(r record) time.Time { return t0.Add(r.at) }
+
+func source(src string, ats ...int) iter.Seq[record] {
+	var rs []record
+	for _, at := range ats {
+		rs = append(rs, record{time.Duration(at) * time.Second, src})
+	}
+	return slices.Values(rs)
+}
+
+func TestMerge(t *testing.T) {
+	m := &Merger[record]{Time: recordTime, Slack: 3 * time.Second}
+	got := slices.Collect(m.Merge(
+		source("a", 1, 4, 2, 6, 5, 9),
+		source("b", 3, 0, 7, 8),
+	))
+
+	var ats []int
+	for _, r := range got {
+		ats = append(ats, int(r.at/time.Second))
+	}
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(ats, want) {
+		t.Errorf("merged times %v; want %v", ats, want)
+	}
+}
+
+func TestMergeLate(t *testing.T) {
+	var late []record
+	m := &Merger[record]{
+		Time:  recordTime,
+		Slack: time.Second,
+		Late:  func(r record) { late = append(late, r) },
+	}
+	got := slices.Collect(m.Merge(source("a", 5, 6, 7, 1, 8)))
+	if len(got) != 4 || len(late) != 1 || late[0].at != time.Second {
+		t.Errorf("got %v, late %v; want 4 records and the record at 1s late", got, late)
+	}
+}
+
+func TestReader(t *testing.T) {
+	parse := func(line []byte) (record, error) {
+		src, at, _ := strings.Cut(string(line), " ")
+		n, err := strconv.Atoi(at)
+		return record{time.Duration(n) * time.Second, src}, err
+	}
+	a := NewReader(strings.NewReader("a 1\na 3\na 2\n"), parse)
+	b := NewReader(bytes.NewBufferString("b 0\nb 4\n"), parse)
+
+	m := &Merger[record]{Time: recordTime, Slack: time.Second}
+	var srcs string
+	for r := range m.Merge(a.All(), b.All()) {
+		srcs += r.src
+	}
+	if srcs != "baaab" {
+		t.Errorf("merged sources %q; want \"baaab\"", srcs)
+	}
+	if a.Err() != nil || b.Err() != nil {
+		t.Errorf("Err() = %v, %v; want nil", a.Err(), b.Err())
+	}
+
+	bad := NewReader(strings.NewReader("a 1\na x\na 3\n"), parse)
+	n := 0
+	for range bad.All() {
+		n++
+	}
+	var numErr *strconv.NumError
+	if n != 1 || !errors.As(bad.Err(), &numErr) {
+		t.Errorf("read %d records, Err() = %v; want 1 record and a parse error", n, bad.Err())
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// A QuantileTracker tracks a fixed quantile of a stream of elements using a
+// pair of heaps: a max-heap holding the elements at or below the quantile and
+// a min-heap holding the rest.
+type QuantileTracker[T any] struct {
+	q      float64
+	less   func(x, y T) bool
+	lo, hi []T // lo is a max-heap, hi a min-heap
+}
+
+// NewQuantileTracker returns a tracker for the q-quantile, where 0 <= q <= 1.
+// It panics if q is out of range.
+func NewQuantileTracker[T cmp.Ordered](q float64) *QuantileTracker[T] {
+	return NewQuantileTrackerFunc(q, cmp.Less[T])
+}
+
+// NewQuantileTrackerFunc is like [NewQuantileTracker] but uses a less function to compare elements.
+func NewQuantileTrackerFunc[T any](q float64, less func(x, y T) bool) *QuantileTracker[T] {
+	if !(q >= 0 && q <= 1) {
+		panic(fmt.Sprintf("sliceheap: quantile %v out of range [0,1]", q))
+	}
+	return &QuantileTracker[T]{q: q, less: less}
+}
+
+func (t *QuantileTracker[T]) greater(x, y T) bool {
+	return t.less(y, x)
+}
+
+// Len returns the number of elements added to the tracker.
+func (t *QuantileTracker[T]) Len() int {
+	return len(t.lo) + len(t.hi)
+}
+
+// Add adds the element x to the tracker.
+// The complexity is O(log n) where n = t.Len().
+func (t *QuantileTracker[T]) Add(x T) {
+	if len(t.lo) > 0 && t.less(x, t.lo[0]) {
+		PushFunc(&t.lo, x, t.greater)
+	} else {
+		PushFunc(&t.hi, x, t.less)
+	}
+
+	// The quantile is the element of rank floor(q*(n-1)), which is kept at
+	// the root of lo.
+	want := int(t.q*float64(t.Len()-1)) + 1
+	for len(t.lo) > want {
+		PushFunc(&t.hi, PopFunc(&t.lo, t.greater), t.less)
+	}
+	for len(t.lo) < want {
+		PushFunc(&t.lo, PopFunc(&t.hi, t.less), t.greater)
+	}
+}
+
+// Query returns the element at the tracked quantile: the element of rank
+// floor(q*(n-1)) among the n elements added so far, counting from zero.
+// Query panics if no elements have been added.
+// The complexity is O(1).
+func (t *QuantileTracker[T]) Query() T {
+	return t.lo[0]
+}
+
+// A MedianTracker tracks the median of a stream of elements.
+type MedianTracker[T any] struct {
+	QuantileTracker[T]
+}
+
+// NewMedianTracker returns a new median tracker.
+func NewMedianTracker[T cmp.Ordered]() *MedianTracker[T] {
+	return NewMedianTrackerFunc(cmp.Less[T])
+}
+
+// NewMedianTrackerFunc is like [NewMedianTracker] but uses a less function to compare elements.
+func NewMedianTrackerFunc[T any](less func(x, y T) bool) *MedianTracker[T] {
+	return &MedianTracker[T]{QuantileTracker[T]{q: 0.5, less: less}}
+}
+
+// Middle returns the two middle elements of those added so far. They are the
+// same element when an odd number of elements has been added. Query returns
+// the lower of the two.
+// Middle panics if no elements have been added.
+// The complexity is O(1).
+func (t *MedianTracker[T]) Middle() (lo, hi T) {
+	if t.Len()%2 == 1 {
+		return t.lo[0], t.lo[0]
+	}
+	return t.lo[0], t.hi[0]
+}
+
+// A WindowedMedian tracks the median of the most recent elements of a
+// stream, up to a fixed window size. Adding an element to a full window
+// expires the oldest one.
+//
+// Expired elements are deleted lazily: they stay in the heaps until they
+// reach a root, and the heaps are compacted whenever dead elements come to
+// outnumber live ones.
+type WindowedMedian[T any] struct {
+	less   func(x, y T) bool
+	window []windowEntry[T] // ring buffer of live entries, oldest at head
+	head   int
+	n      int    // number of live entries
+	seq    uint64 // sequence number of the next entry
+	lo, hi []windowEntry[T]
+	nlo    int // number of live entries in lo
+}
+
+type windowEntry[T any] struct {
+	v   T
+	seq uint64
+}
+
+// NewWindowedMedian returns a median tracker over a window of the given size.
+// It panics if size < 1.
+func NewWindowedMedian[T cmp.Ordered](size int) *WindowedMedian[T] {
+	return NewWindowedMedianFunc(size, cmp.Less[T])
+}
+
+// NewWindowedMedianFunc is like [NewWindowedMedian] but uses a less function to compare elements.
+func NewWindowedMedianFunc[T any](size int, less func(x, y T) bool) *WindowedMedian[T] {
+	if size < 1 {
+		panic(fmt.Sprintf("sliceheap: window size %d < 1", size))
+	}
+	return &WindowedMedian[T]{less: less, window: make([]windowEntry[T], size)}
+}
+
+// entryLess orders entries by value and then by sequence number, so that
+// every entry, live or dead, has a definite place on one side of the median.
+func (w *WindowedMedian[T]) entryLess(x, y windowEntry[T]) bool {
+	if w.less(x.v, y.v) {
+		return true
+	}
+	return !w.less(y.v, x.v) && x.seq < y.seq
+}
+
+func (w *WindowedMedian[T]) entryGreater(x, y windowEntry[T]) bool {
+	return w.entryLess(y, x)
+}
+
+// dead reports whether e has left the window.
+func (w *WindowedMedian[T]) dead(e windowEntry[T]) bool {
+	return e.seq+uint64(w.n) < w.seq
+}
+
+// Len returns the number of elements in the window.
+func (w *WindowedMedian[T]) Len() int {
+	return w.n
+}
+
+// Add adds the element x to the window, expiring the oldest element if the
+// window is full.
+// The complexity is amortized O(log k) where k is the window size.
+func (w *WindowedMedian[T]) Add(x T) {
+	if w.n == len(w.window) {
+		w.Expire()
+	}
+
+	e := windowEntry[T]{v: x, seq: w.seq}
+	w.seq++
+	w.window[(w.head+w.n)%len(w.window)] = e
+	w.n++
+	if len(w.lo) > 0 && w.entryLess(e, w.lo[0]) {
+		PushFunc(&w.lo, e, w.entryGreater)
+		w.nlo++
+	} else {
+		PushFunc(&w.hi, e, w.entryLess)
+	}
+	w.rebalance()
+}
+
+// Expire removes the oldest element from the window. It is a no-op if the
+// window is empty.
+// The complexity is amortized O(log k) where k is the window size.
+func (w *WindowedMedian[T]) Expire() {
+	if w.n == 0 {
+		return
+	}
+	e := w.window[w.head]
+	w.window[w.head] = windowEntry[T]{}
+	w.head = (w.head + 1) % len(w.window)
+	w.n--
+	// Every entry in lo, dead or alive, orders before every entry in hi.
+	if len(w.lo) > 0 && !w.entryLess(w.lo[0], e) {
+		w.nlo--
+	}
+	w.rebalance()
+}
+
+// rebalance restores the balance of live entries between lo and hi, so that
+// the lower median is the root of lo, and prunes dead roots.
+func (w *WindowedMedian[T]) rebalance() {
+	want := (w.n + 1) / 2
+	for w.nlo > want {
+		w.prune()
+		PushFunc(&w.hi, PopFunc(&w.lo, w.entryGreater), w.entryLess)
+		w.nlo--
+	}
+	for w.nlo < want {
+		w.prune()
+		PushFunc(&w.lo, PopFunc(&w.hi, w.entryLess), w.entryGreater)
+		w.nlo++
+	}
+	w.prune()
+
+	if len(w.lo)+len(w.hi) > 2*w.n+16 {
+		w.lo = w.compact(w.lo, w.entryGreater)
+		w.hi = w.compact(w.hi, w.entryLess)
+	}
+}
+
+// prune pops dead entries from the roots of lo and hi.
+func (w *WindowedMedian[T]) prune() {
+	for len(w.lo) > 0 && w.dead(w.lo[0]) {
+		PopFunc(&w.lo, w.entryGreater)
+	}
+	for len(w.hi) > 0 && w.dead(w.hi[0]) {
+		PopFunc(&w.hi, w.entryLess)
+	}
+}
+
+// compact removes all dead entries from h and re-establishes the heap.
+func (w *WindowedMedian[T]) compact(h []windowEntry[T], less func(x, y windowEntry[T]) bool) []windowEntry[T] {
+	live := h[:0]
+	for _, e := range h {
+		if !w.dead(e) {
+			live = append(live, e)
+		}
+	}
+	clear(h[len(live):])
+	InitFunc(live, less)
+	return live
+}
+
+// Query returns the lower median of the elements in the window.
+// Query panics if the window is empty.
+// The complexity is O(1).
+func (w *WindowedMedian[T]) Query() T {
+	return w.lo[0].v
+}
+
+// Middle returns the two middle elements of the window. They are the same
+// element when the window holds an odd number of elements.
+// Middle panics if the window is empty.
+// The complexity is O(1).
+func (w *WindowedMedian[T]) Middle() (lo, hi T) {
+	if w.n%2 == 1 {
+		return w.lo[0].v, w.lo[0].v
+	}
+	return w.lo[0].v, w.hi[0].v
+}
@@ -0,0 +1,102 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestQuantileTracker(t *testing.T) {
+	for _, q := range []float64{0, 0.1, 0.5, 0.9, 0.99, 1} {
+		qt := NewQuantileTracker[int](q)
+		var seen []int
+		for i := 0; i < 200; i++ {
+			x := rand.Intn(50)
+			qt.Add(x)
+			seen = append(seen, x)
+
+			sorted := slices.Clone(seen)
+			slices.Sort(sorted)
+			want := sorted[int(q*float64(len(sorted)-1))]
+			if got := qt.Query(); got != want {
+				t.Fatalf("q=%v n=%d: Query() = %d; want %d", q, len(seen), got, want)
+			}
+		}
+		if qt.Len() != len(seen) {
+			t.Errorf("Len() = %d; want %d", qt.Len(), len(seen))
+		}
+	}
+}
+
+func TestQuantileTrackerRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewQuantileTracker(1.5) did not panic")
+		}
+	}()
+	NewQuantileTracker[int](1.5)
+}
+
+func TestMedianTracker(t *testing.T) {
+	mt := NewMedianTracker[int]()
+	var seen []int
+	for i := 0; i < 100; i++ {
+		x := rand.Intn(1000)
+		mt.Add(x)
+		seen = append(seen, x)
+
+		sorted := slices.Clone(seen)
+		slices.Sort(sorted)
+		n := len(sorted)
+		lo, hi := mt.Middle()
+		if lo != sorted[(n-1)/2] || hi != sorted[n/2] {
+			t.Fatalf("n=%d: Middle() = %d, %d; want %d, %d", n, lo, hi, sorted[(n-1)/2], sorted[n/2])
+		}
+		if m := mt.Query(); m != lo {
+			t.Fatalf("n=%d: Query() = %d; want %d", n, m, lo)
+		}
+	}
+}
+
+func TestWindowedMedian(t *testing.T) {
+	for _, size := range []int{1, 2, 5, 16} {
+		wm := NewWindowedMedian[int](size)
+		var seen []int
+		for i := 0; i < 300; i++ {
+			if i%17 == 16 {
+				wm.Expire()
+				if len(seen) > 0 {
+					seen = seen[1:]
+				}
+			} else {
+				x := rand.Intn(20)
+				wm.Add(x)
+				seen = append(seen, x)
+				if len(seen) > size {
+					seen = seen[1:]
+				}
+			}
+			if wm.Len() != len(seen) {
+				t.Fatalf("size=%d: Len() = %d; want %d", size, wm.Len(), len(seen))
+			}
+			if len(seen) == 0 {
+				continue
+			}
+
+			sorted := slices.Clone(seen)
+			slices.Sort(sorted)
+			n := len(sorted)
+			lo, hi := wm.Middle()
+			if lo != sorted[(n-1)/2] || hi != sorted[n/2] {
+				t.Fatalf("size=%d i=%d: Middle() = %d, %d; want %d, %d", size, i, lo, hi, sorted[(n-1)/2], sorted[n/2])
+			}
+			if m := wm.Query(); m != lo {
+				t.Fatalf("size=%d i=%d: Query() = %d; want %d", size, i, m, lo)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// A MergeableHeap is a heap H that can meld another heap of the same type
+// into itself, so that code which merges heaps can be written once for
+// every implementation:
+//
+//	func meldAll[H sliceheap.MergeableHeap[H]](dst H, srcs ...H) {
+//		for _, src := range srcs {
+//			dst.Meld(src)
+//		}
+//	}
+//
+// [OrderedHeap] implements MergeableHeap, and the [Meld] function melds
+// heaps held as plain slices.
+type MergeableHeap[H any] interface {
+	// Meld moves the elements of src into the heap. Whether src is left
+	// unchanged or emptied depends on the implementation.
+	Meld(src H)
+}
+
+// Meld moves the elements of the heap src onto the heap *dst, leaving src
+// unchanged. Slice heaps cannot be melded in less than linear time, so
+// Meld appends and then sifts or rebuilds as [PushSeq] does; melding the
+// smaller heap into the larger is the cheaper way round.
+// The complexity is O(min(k log(n+k), n+k)) where n = len(*dst) and
+// k = len(src).
+func Meld[T cmp.Ordered](dst *[]T, src []T) {
+	MeldFunc(dst, src, cmp.Less[T])
+}
+
+// MeldFunc is like [Meld] but uses a less function to compare elements.
+func MeldFunc[T any](dst *[]T, src []T, less func(x, y T) bool) {
+	PushSeqFunc(dst, slices.Values(src), less)
+}
+
+// Meld moves the elements of the heap src into the heap, leaving src
+// unchanged, as the [Meld] function does.
+// The complexity is O(min(k log(n+k), n+k)) where n = h.Len() and
+// k = src.Len().
+func (h *OrderedHeap[T]) Meld(src *OrderedHeap[T]) {
+	Meld((*[]T)(h), *src)
+}
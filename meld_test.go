@@ -0,0 +1,54 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMeld(t *testing.T) {
+	a := []int{5, 1, 9}
+	b := []int{4, 8, 2, 7}
+	Init(a)
+	Init(b)
+	Meld(&a, b)
+	verify(t, a)
+	var got []int
+	for len(a) > 0 {
+		got = append(got, Pop(&a))
+	}
+	if want := []int{1, 2, 4, 5, 7, 8, 9}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+	if b[0] != 2 || len(b) != 4 {
+		t.Errorf("Meld changed src to %v", b)
+	}
+}
+
+func meldAll[H MergeableHeap[H]](dst H, srcs ...H) {
+	for _, src := range srcs {
+		dst.Meld(src)
+	}
+}
+
+func TestOrderedHeapMeld(t *testing.T) {
+	var a, b, c OrderedHeap[int]
+	for _, x := range []int{5, 1, 9} {
+		a.Push(x)
+	}
+	for _, x := range []int{4, 8} {
+		b.Push(x)
+	}
+	c.Push(3)
+	meldAll(&a, &b, &c)
+	verify(t, a)
+	if got := slices.Collect(Drain[int](&a)); !slices.Equal(got, []int{1, 3, 4, 5, 8, 9}) {
+		t.Errorf("popped %v after Meld", got)
+	}
+	if b.Len() != 2 || c.Len() != 1 {
+		t.Errorf("Meld changed src to %v, %v", b, c)
+	}
+}
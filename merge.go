@@ -0,0 +1,194 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+)
+
+// MergeSeqs merges sequences that are each sorted in ascending order into a
+// single ascending sequence. The inputs are consumed lazily, at most one
+// element ahead of the output. Equal elements are yielded in the order of the
+// sequences that produced them.
+// Each element costs O(log k) where k = len(seqs).
+func MergeSeqs[T cmp.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return MergeSeqsFunc(cmp.Less, seqs...)
+}
+
+// MergeSeqsFunc is like [MergeSeqs] but uses a less function to compare elements.
+func MergeSeqsFunc[T any](less func(x, y T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := make([]cursor[T], 0, len(seqs))
+		defer func() {
+			for _, c := range h {
+				c.stop()
+			}
+		}()
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			v, ok := next()
+			if !ok {
+				stop()
+				continue
+			}
+			h = append(h, cursor[T]{v: v, i: i, next: next, stop: stop})
+		}
+
+		cless := func(x, y cursor[T]) bool {
+			if less(x.v, y.v) {
+				return true
+			}
+			return !less(y.v, x.v) && x.i < y.i
+		}
+		InitFunc(h, cless)
+		for len(h) > 0 {
+			if !yield(h[0].v) {
+				return
+			}
+			if v, ok := h[0].next(); ok {
+				h[0].v = v
+				FixFunc(h, 0, cless)
+			} else {
+				PopFunc(&h, cless).stop()
+			}
+		}
+	}
+}
+
+// A cursor is the current head of one of the inputs to a merge.
+type cursor[T any] struct {
+	v    T
+	i    int // input position, for stability
+	next func() (T, bool)
+	stop func()
+}
+
+// MergeSorted merges runs, which must each be sorted in ascending order,
+// appending the merged elements to dst and returning the extended slice.
+// Equal elements keep the order of the runs that hold them.
+// The complexity is O(n log k) where n is the total number of elements and
+// k = len(runs).
+func MergeSorted[T cmp.Ordered](dst []T, runs ...[]T) []T {
+	return MergeSortedFunc(dst, cmp.Less, runs...)
+}
+
+// MergeSortedFunc is like [MergeSorted] but uses a less function to compare elements.
+func MergeSortedFunc[T any](dst []T, less func(x, y T) bool, runs ...[]T) []T {
+	n := 0
+	for _, r := range runs {
+		n += len(r)
+	}
+	dst = slices.Grow(dst, n)
+	for x := range MergeSortedValuesFunc(less, runs...) {
+		dst = append(dst, x)
+	}
+	return dst
+}
+
+// MergeSortedValues returns an iterator over the merged elements of runs,
+// which must each be sorted in ascending order. It is the iterator form of
+// [MergeSorted].
+func MergeSortedValues[T cmp.Ordered](runs ...[]T) iter.Seq[T] {
+	return MergeSortedValuesFunc(cmp.Less, runs...)
+}
+
+// MergeSortedValuesFunc is like [MergeSortedValues] but uses a less function to compare elements.
+func MergeSortedValuesFunc[T any](less func(x, y T) bool, runs ...[]T) iter.Seq[T] {
+	type run struct {
+		s []T // remaining elements
+		i int // input position, for stability
+	}
+	rless := func(x, y run) bool {
+		if less(x.s[0], y.s[0]) {
+			return true
+		}
+		return !less(y.s[0], x.s[0]) && x.i < y.i
+	}
+	return func(yield func(T) bool) {
+		h := make([]run, 0, len(runs))
+		for i, r := range runs {
+			if len(r) > 0 {
+				h = append(h, run{r, i})
+			}
+		}
+		InitFunc(h, rless)
+		for len(h) > 0 {
+			if !yield(h[0].s[0]) {
+				return
+			}
+			if h[0].s = h[0].s[1:]; len(h[0].s) > 0 {
+				FixFunc(h, 0, rless)
+			} else {
+				PopFunc(&h, rless)
+			}
+		}
+	}
+}
+
+// MergeChans merges the values received from chans, each of which must
+// deliver its values in ascending order, and sends them in ascending order on
+// the returned channel. The returned channel is closed once all of chans are
+// closed, or once ctx is done.
+//
+// Before sending a value, MergeChans must hold the next value of every open
+// input, so a stalled input stalls the output.
+func MergeChans[T cmp.Ordered](ctx context.Context, chans ...<-chan T) <-chan T {
+	return MergeChansFunc(ctx, cmp.Less, chans...)
+}
+
+// MergeChansFunc is like [MergeChans] but uses a less function to compare elements.
+func MergeChansFunc[T any](ctx context.Context, less func(x, y T) bool, chans ...<-chan T) <-chan T {
+	type head struct {
+		v T
+		i int // input position, for stability
+	}
+	hless := func(x, y head) bool {
+		if less(x.v, y.v) {
+			return true
+		}
+		return !less(y.v, x.v) && x.i < y.i
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		h := make([]head, 0, len(chans))
+		for i, c := range chans {
+			select {
+			case v, ok := <-c:
+				if ok {
+					h = append(h, head{v, i})
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+		InitFunc(h, hless)
+
+		for len(h) > 0 {
+			select {
+			case out <- h[0].v:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case v, ok := <-chans[h[0].i]:
+				if ok {
+					h[0].v = v
+					FixFunc(h, 0, hless)
+				} else {
+					PopFunc(&h, hless)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,150 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestMergeSeqs(t *testing.T) {
+	seqs := []iter.Seq[int]{
+		slices.Values([]int{1, 4, 7, 10}),
+		slices.Values([]int{}),
+		slices.Values([]int{2, 2, 8}),
+		slices.Values([]int{0, 3, 5, 6, 9, 11}),
+	}
+	got := slices.Collect(MergeSeqs(seqs...))
+	want := []int{0, 1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeSeqs got %v; want %v", got, want)
+	}
+
+	if got := slices.Collect(MergeSeqs[int]()); len(got) != 0 {
+		t.Errorf("MergeSeqs() got %v; want empty", got)
+	}
+}
+
+func TestMergeSeqsFuncStable(t *testing.T) {
+	type item struct{ key, src int }
+	less := func(x, y item) bool { return x.key < y.key }
+	got := slices.Collect(MergeSeqsFunc(less,
+		slices.Values([]item{{1, 0}, {2, 0}}),
+		slices.Values([]item{{1, 1}, {2, 1}}),
+		slices.Values([]item{{1, 2}}),
+	))
+	want := []item{{1, 0}, {1, 1}, {1, 2}, {2, 0}, {2, 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeSeqsFunc got %v; want %v", got, want)
+	}
+}
+
+func TestMergeSeqsBreak(t *testing.T) {
+	stopped := 0
+	seq := func(vs ...int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer func() { stopped++ }()
+			for _, v := range vs {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+
+	var got []int
+	for x := range MergeSeqs(seq(1, 3, 5), seq(2, 4, 6)) {
+		if x > 3 {
+			break
+		}
+		got = append(got, x)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if stopped != 2 {
+		t.Errorf("%d inputs stopped; want 2", stopped)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	runs := [][]int{
+		{1, 4, 7, 10},
+		nil,
+		{2, 2, 8},
+		{0, 3, 5, 6, 9, 11},
+	}
+	got := MergeSorted([]int{-1}, runs...)
+	want := []int{-1, 0, 1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeSorted got %v; want %v", got, want)
+	}
+
+	var vs []int
+	for x := range MergeSortedValues(runs...) {
+		if x > 5 {
+			break
+		}
+		vs = append(vs, x)
+	}
+	if want := []int{0, 1, 2, 2, 3, 4, 5}; !slices.Equal(vs, want) {
+		t.Errorf("MergeSortedValues got %v; want %v", vs, want)
+	}
+}
+
+func TestMergeSortedFuncStable(t *testing.T) {
+	type item struct{ key, src int }
+	less := func(x, y item) bool { return x.key < y.key }
+	got := MergeSortedFunc(nil, less,
+		[]item{{1, 0}, {2, 0}},
+		[]item{{1, 1}, {2, 1}},
+		[]item{{1, 2}},
+	)
+	want := []item{{1, 0}, {1, 1}, {1, 2}, {2, 0}, {2, 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeSortedFunc got %v; want %v", got, want)
+	}
+}
+
+func sendAll[T any](vs ...T) <-chan T {
+	c := make(chan T)
+	go func() {
+		defer close(c)
+		for _, v := range vs {
+			c <- v
+		}
+	}()
+	return c
+}
+
+func TestMergeChans(t *testing.T) {
+	out := MergeChans(context.Background(),
+		sendAll(1, 4, 7, 10),
+		sendAll[int](),
+		sendAll(2, 2, 8),
+		sendAll(0, 3, 5, 6, 9, 11),
+	)
+	var got []int
+	for x := range out {
+		got = append(got, x)
+	}
+	want := []int{0, 1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeChans got %v; want %v", got, want)
+	}
+}
+
+func TestMergeChansCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stalled := make(chan int) // never sends
+	out := MergeChans(ctx, sendAll(1, 2, 3), stalled)
+
+	cancel()
+	for range out {
+		t.Error("received value after cancellation")
+	}
+}
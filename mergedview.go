@@ -0,0 +1,97 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A MergedView presents several heaps as a single priority queue without
+// copying their elements. Peek and Pop look at the roots of all the heaps
+// and act on the least, so a consumer can drain work sharded across, say,
+// per-tenant heaps in global order while producers keep pushing to their
+// own heap. Of equal roots, the one in the earliest heap is taken.
+//
+// A MergedView refers to the owners' slices, so it reflects changes made to
+// the heaps between calls, and must not be used concurrently with them.
+// Since the roots are compared afresh on each call, the heaps may change
+// freely in between.
+type MergedView[T any] struct {
+	hs   []*[]T
+	less func(x, y T) bool
+}
+
+// NewMergedView returns a view merging the heaps hs.
+func NewMergedView[T cmp.Ordered](hs ...*[]T) *MergedView[T] {
+	return NewMergedViewFunc(cmp.Less[T], hs...)
+}
+
+// NewMergedViewFunc is like [NewMergedView] but uses a less function to compare elements.
+func NewMergedViewFunc[T any](less func(x, y T) bool, hs ...*[]T) *MergedView[T] {
+	return &MergedView[T]{hs: hs, less: less}
+}
+
+// Len returns the total number of elements in the heaps.
+// The complexity is O(k) where k is the number of heaps.
+func (v *MergedView[T]) Len() int {
+	n := 0
+	for _, h := range v.hs {
+		n += len(*h)
+	}
+	return n
+}
+
+// Best returns the index, among the heaps passed to the constructor, of the
+// heap whose root is least, or -1 if all the heaps are empty.
+// The complexity is O(k) where k is the number of heaps.
+func (v *MergedView[T]) Best() int {
+	best := -1
+	for i, h := range v.hs {
+		if len(*h) > 0 && (best < 0 || v.less((*h)[0], (*v.hs[best])[0])) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Peek returns the least element of all the heaps, and reports false if
+// they are all empty.
+// The complexity is O(k) where k is the number of heaps.
+func (v *MergedView[T]) Peek() (T, bool) {
+	i := v.Best()
+	if i < 0 {
+		var zero T
+		return zero, false
+	}
+	return (*v.hs[i])[0], true
+}
+
+// Pop removes and returns the least element of all the heaps, and reports
+// false if they are all empty.
+// The complexity is O(k + log n) where k is the number of heaps and n is the
+// length of the heap popped from.
+func (v *MergedView[T]) Pop() (T, bool) {
+	i := v.Best()
+	if i < 0 {
+		var zero T
+		return zero, false
+	}
+	return PopFunc(v.hs[i], v.less), true
+}
+
+// Ascend returns an iterator over the elements of all the heaps in
+// ascending order. The heaps are read when iteration starts, not when
+// Ascend is called, and are not modified.
+// The complexity is O(m log(m+k)) to yield m elements from k heaps.
+func (v *MergedView[T]) Ascend() iter.Seq[T] {
+	seqs := make([]iter.Seq[T], len(v.hs))
+	for i, h := range v.hs {
+		seqs[i] = func(yield func(T) bool) {
+			ascend(*h, v.less)(yield)
+		}
+	}
+	return MergeSeqsFunc(v.less, seqs...)
+}
@@ -0,0 +1,61 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergedView(t *testing.T) {
+	a := []int{5, 1, 9}
+	b := []int{4, 8}
+	var c []int
+	Init(a)
+	Init(b)
+	v := NewMergedView(&a, &b, &c)
+
+	if v.Len() != 5 {
+		t.Errorf("Len() = %d; want 5", v.Len())
+	}
+	if got := slices.Collect(v.Ascend()); !slices.Equal(got, []int{1, 4, 5, 8, 9}) {
+		t.Errorf("Ascend() = %v", got)
+	}
+	if x, _ := v.Pop(); x != 1 || len(a) != 2 {
+		t.Errorf("Pop() = %d with len(a) = %d; want 1, 2", x, len(a))
+	}
+
+	// Changes to the heaps are seen by the view.
+	Push(&c, 2)
+	if i := v.Best(); i != 2 {
+		t.Errorf("Best() = %d; want 2", i)
+	}
+	var got []int
+	for {
+		x, ok := v.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, x)
+	}
+	if !slices.Equal(got, []int{2, 4, 5, 8, 9}) {
+		t.Errorf("popped %v", got)
+	}
+	if _, ok := v.Peek(); ok || v.Best() != -1 {
+		t.Error("Peek reported an element after draining")
+	}
+}
+
+func TestMergedViewAscendLate(t *testing.T) {
+	a, b := []int{4}, []int{2}
+	seq := NewMergedView(&a, &b).Ascend()
+	for _, x := range []int{6, 1} {
+		Push(&a, x)
+	}
+	Push(&b, 3)
+	if got := slices.Collect(seq); !slices.Equal(got, []int{1, 2, 3, 4, 6}) {
+		t.Errorf("Ascend created before the pushes yielded %v", got)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// MergeReaders merges sources of newline-terminated records, each sorted in
+// ascending order, into a single reader of records in ascending order, as
+// when merging sorted log or shard files. Records are ordered by the keys
+// parse extracts from them, without their newlines, and are written out
+// unchanged, each followed by a newline. Records with equal keys keep the
+// order of the sources that hold them.
+//
+// The sources are read lazily, one record ahead of the output. A read error
+// from a source, or an error from parse, wrapped with the index of its
+// source, is returned by Read. A reader abandoned before Read returns an
+// error, including io.EOF, must be closed to release the merge.
+// Each record costs O(log k) where k = len(sources).
+func MergeReaders[T any](less func(x, y T) bool, parse func(rec []byte) (T, error), sources ...io.Reader) io.ReadCloser {
+	return MergeReadersDelim('\n', less, parse, sources...)
+}
+
+// MergeReadersDelim is like [MergeReaders] but with records terminated by
+// delim.
+func MergeReadersDelim[T any](delim byte, less func(x, y T) bool, parse func(rec []byte) (T, error), sources ...io.Reader) io.ReadCloser {
+	next, stop := iter.Pull2(MergeRecords(delim, less, parse, sources...))
+	return &mergedReader{delim: delim, next: next, stop: stop}
+}
+
+// MergeRecords returns an iterator over the records of sources, terminated
+// by delim, merged as by [MergeReadersDelim]. The records are yielded
+// without their delimiters. If an error occurs, it is yielded with a nil
+// record and iteration stops.
+func MergeRecords[T any](delim byte, less func(x, y T) bool, parse func(rec []byte) (T, error), sources ...io.Reader) iter.Seq2[[]byte, error] {
+	seqs := make([]iter.Seq[record[T]], len(sources))
+	for i, r := range sources {
+		seqs[i] = readRecords(i, r, delim, parse)
+	}
+	// An error orders first, so that it is yielded as soon as it is read.
+	rless := func(x, y record[T]) bool {
+		if x.err != nil || y.err != nil {
+			return x.err != nil && y.err == nil
+		}
+		return less(x.key, y.key)
+	}
+	return func(yield func([]byte, error) bool) {
+		for r := range MergeSeqsFunc(rless, seqs...) {
+			if r.err != nil {
+				yield(nil, r.err)
+				return
+			}
+			if !yield(r.rec, nil) {
+				return
+			}
+		}
+	}
+}
+
+// A record is a record read from a source, or the error that ended it.
+type record[T any] struct {
+	rec []byte
+	key T
+	err error
+}
+
+// readRecords returns a sequence of the records of the source r, at
+// position i, ending with a record holding an error if one occurs.
+func readRecords[T any](i int, r io.Reader, delim byte, parse func(rec []byte) (T, error)) iter.Seq[record[T]] {
+	return func(yield func(record[T]) bool) {
+		br := bufio.NewReader(r)
+		for {
+			rec, err := br.ReadBytes(delim)
+			if err == io.EOF && len(rec) > 0 {
+				err = nil // a final record without a delimiter
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(record[T]{err: fmt.Errorf("sliceheap: source %d: %w", i, err)})
+				return
+			}
+			if rec[len(rec)-1] == delim {
+				rec = rec[:len(rec)-1]
+			}
+			key, err := parse(rec)
+			if err != nil {
+				yield(record[T]{err: fmt.Errorf("sliceheap: source %d: %w", i, err)})
+				return
+			}
+			if !yield(record[T]{rec: rec, key: key}) {
+				return
+			}
+		}
+	}
+}
+
+// A mergedReader is an io.Reader of merged records.
+type mergedReader struct {
+	delim byte
+	next  func() ([]byte, error, bool)
+	stop  func()
+	buf   []byte // unread output
+	err   error
+}
+
+func (r *mergedReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		rec, err, ok := r.next()
+		switch {
+		case !ok:
+			r.err = io.EOF
+		case err != nil:
+			r.err = err
+		default:
+			r.buf = append(rec, r.delim)
+			continue
+		}
+		r.stop()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close stops the merge. Later reads return io.ErrClosedPipe.
+func (r *mergedReader) Close() error {
+	r.stop()
+	r.buf = nil
+	if r.err == nil || r.err == io.EOF {
+		r.err = io.ErrClosedPipe
+	}
+	return nil
+}
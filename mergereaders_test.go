@@ -0,0 +1,81 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// timestamp parses the leading number of a record such as "12 message".
+func timestamp(rec []byte) (int, error) {
+	f, _, _ := strings.Cut(string(rec), " ")
+	return strconv.Atoi(f)
+}
+
+func TestMergeReaders(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+	r := MergeReaders(less, timestamp,
+		strings.NewReader("1 a\n4 a\n9 a\n"),
+		iotest.OneByteReader(strings.NewReader("2 b\n4 b\n")),
+		strings.NewReader(""),
+		strings.NewReader("3 c\n10 c"), // no final newline
+	)
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1 a\n2 b\n3 c\n4 a\n4 b\n9 a\n10 c\n"
+	if string(got) != want {
+		t.Errorf("merged:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMergeRecords(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+	var got []string
+	for rec, err := range MergeRecords(0, less, timestamp,
+		strings.NewReader("5 x\x007 x\x00"),
+		strings.NewReader("6 y\x00"),
+	) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(rec))
+	}
+	if strings.Join(got, ",") != "5 x,6 y,7 x" {
+		t.Errorf("records = %q", got)
+	}
+
+	var err error
+	for _, err = range MergeRecords('\n', less, timestamp,
+		strings.NewReader("1 ok\n"),
+		strings.NewReader("2 ok\nbad\n"),
+	) {
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || !strings.Contains(err.Error(), "source 1") {
+		t.Errorf("error = %v; want parse error from source 1", err)
+	}
+}
+
+func TestMergeReadersClose(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+	r := MergeReaders(less, timestamp, strings.NewReader("1 a\n3 a\n"), strings.NewReader("2 b\n"))
+	buf := make([]byte, 4)
+	if n, err := r.Read(buf); err != nil || string(buf[:n]) != "1 a\n" {
+		t.Fatalf("Read = %q, %v; want the first record", buf[:n], err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(buf); err != io.ErrClosedPipe {
+		t.Errorf("Read after Close = %v; want io.ErrClosedPipe", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+// Package mmapheap provides a heap whose elements live in a memory-mapped
+// file rather than on the Go heap, so that very large heaps can be paged in
+// and out by the operating system.
+//
+// Elements are stored in their in-memory representation, so the element type
+// must be of fixed size and contain no pointers: booleans, numbers, and
+// arrays and structs built from them. A file written by one program can be
+// reopened by another only if both agree on the element type and are running
+// on the same architecture.
+package mmapheap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"unsafe"
+
+	"github.com/buth/sliceheap"
+)
+
+// headerSize is the number of bytes preceding the elements in the file. The
+// header records the number of elements in the heap and is large enough that
+// the elements that follow it are suitably aligned.
+const headerSize = 64
+
+// minCap is the initial capacity, in elements, of a new file.
+const minCap = 1024
+
+// A Heap is a heap of T stored in a memory-mapped file.
+// A Heap is not safe for concurrent use.
+type Heap[T any] struct {
+	f    *os.File
+	data []byte // the whole mapping, header included
+	s    []T    // view of the elements; cap(s) is the file's capacity
+	less func(x, y T) bool
+}
+
+// Open opens the named file as a heap ordered by less, creating it if it does
+// not exist. The contents of an existing file are assumed to satisfy the heap
+// invariants for less.
+func Open[T any](name string, less func(x, y T) bool) (*Heap[T], error) {
+	t := reflect.TypeFor[T]()
+	if err := checkType(t); err != nil {
+		return nil, err
+	}
+	if t.Size() == 0 {
+		return nil, fmt.Errorf("mmapheap: zero-sized element type %v", t)
+	}
+	if t.Align() > headerSize {
+		return nil, fmt.Errorf("mmapheap: element type %v has unsupported alignment %d", t, t.Align())
+	}
+
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	h := &Heap[T]{f: f, less: less}
+	size := fi.Size()
+	if size == 0 || size == headerSize {
+		// A new file, or one with a header and no room for elements.
+		size = headerSize + minCap*int64(t.Size())
+		if err := f.Truncate(size); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if size < headerSize || (size-headerSize)%int64(t.Size()) != 0 {
+		f.Close()
+		return nil, fmt.Errorf("mmapheap: %s: size %d is not a whole number of %v elements", name, size, t)
+	}
+	if err := h.mmap(int(size)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if n := binary.NativeEndian.Uint64(h.data); n > uint64(cap(h.s)) {
+		h.Close()
+		return nil, fmt.Errorf("mmapheap: %s: corrupt header: length %d exceeds capacity %d", name, n, cap(h.s))
+	}
+	return h, nil
+}
+
+func (h *Heap[T]) mmap(size int) error {
+	data, err := syscall.Mmap(int(h.f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	h.data = data
+	n := int(binary.NativeEndian.Uint64(data))
+	c := (size - headerSize) / int(reflect.TypeFor[T]().Size())
+	h.s = unsafe.Slice((*T)(unsafe.Pointer(&data[headerSize])), c)[:min(n, c)]
+	return nil
+}
+
+// checkType reports an error if values of type t contain pointers.
+func checkType(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return nil
+	case reflect.Array:
+		return checkType(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if err := checkType(t.Field(i).Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("mmapheap: element type contains %v, which is not pointer-free", t)
+}
+
+func (h *Heap[T]) setLen() {
+	binary.NativeEndian.PutUint64(h.data, uint64(len(h.s)))
+}
+
+// grow doubles the capacity of the file, to at least minCap elements.
+func (h *Heap[T]) grow() error {
+	size := headerSize + max(2*cap(h.s), minCap)*int(reflect.TypeFor[T]().Size())
+	if err := syscall.Munmap(h.data); err != nil {
+		return err
+	}
+	h.data, h.s = nil, nil
+	if err := h.f.Truncate(int64(size)); err != nil {
+		return err
+	}
+	return h.mmap(size)
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.s)
+}
+
+// Push pushes the element x onto the heap, growing the file if necessary.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Push(x T) error {
+	if h.data == nil {
+		return errClosed
+	}
+	if len(h.s) == cap(h.s) {
+		if err := h.grow(); err != nil {
+			return err
+		}
+	}
+	sliceheap.PushFunc(&h.s, x, h.less)
+	h.setLen()
+	return nil
+}
+
+// Pop removes and returns the minimum element (according to less) from the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Pop() T {
+	x := sliceheap.PopFunc(&h.s, h.less)
+	h.setLen()
+	return x
+}
+
+// Peek returns the minimum element (according to less) without removing it.
+func (h *Heap[T]) Peek() T {
+	return h.s[0]
+}
+
+// Remove removes and returns the element at index i from the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Remove(i int) T {
+	x := sliceheap.RemoveFunc(&h.s, i, h.less)
+	h.setLen()
+	return x
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Fix(i int) {
+	sliceheap.FixFunc(h.s, i, h.less)
+}
+
+// Slice returns the elements of the heap, in heap order, as a slice backed
+// directly by the mapped file. Elements may be modified through the slice,
+// after which Fix must be called. The slice must not be used after the next
+// call to Push or Close, either of which may unmap its memory.
+func (h *Heap[T]) Slice() []T {
+	return h.s
+}
+
+// Close unmaps and closes the file. The heap's contents remain in the file
+// and can be reopened with Open.
+func (h *Heap[T]) Close() error {
+	var err error
+	if h.data != nil {
+		err = syscall.Munmap(h.data)
+		h.data, h.s = nil, nil
+	}
+	return errors.Join(err, h.f.Close())
+}
+
+var errClosed = errors.New("mmapheap: heap is closed")
@@ -0,0 +1,101 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package mmapheap
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type entry struct {
+	Key   int64
+	Value [3]uint16
+}
+
+func lessEntry(x, y entry) bool { return x.Key < y.Key }
+
+func TestHeap(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "heap")
+	h, err := Open(name, lessEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 3 * minCap // forces the file to grow
+	for i := 0; i < n; i++ {
+		if err := h.Push(entry{Key: rand.Int63n(1000)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if h.Len() != n {
+		t.Fatalf("Len() = %d; want %d", h.Len(), n)
+	}
+	for i := 0; i < n/2; i++ {
+		h.Pop()
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = Open(name, lessEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if h.Len() != n-n/2 {
+		t.Fatalf("reopened Len() = %d; want %d", h.Len(), n-n/2)
+	}
+	s := h.Slice()
+	s[len(s)-1].Key = -1
+	h.Fix(len(s) - 1)
+	if x := h.Peek(); x.Key != -1 {
+		t.Errorf("Peek() after Fix = %v; want key -1", x)
+	}
+	prev := h.Pop().Key
+	for h.Len() > 0 {
+		x := h.Pop().Key
+		if x < prev {
+			t.Fatalf("Pop() = %d after %d", x, prev)
+		}
+		prev = x
+	}
+}
+
+func TestOpenHeaderOnly(t *testing.T) {
+	// A file holding only an empty header has no room for elements.
+	name := filepath.Join(t.TempDir(), "heap")
+	if err := os.WriteFile(name, make([]byte, headerSize), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := Open(name, lessEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if err := h.Push(entry{Key: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if x := h.Pop(); x.Key != 1 {
+		t.Errorf("Pop() = %v; want key 1", x)
+	}
+}
+
+func TestOpenPointerType(t *testing.T) {
+	type bad struct {
+		Key  int
+		Name string
+	}
+	name := filepath.Join(t.TempDir(), "heap")
+	if _, err := Open(name, func(x, y bad) bool { return x.Key < y.Key }); err == nil {
+		t.Error("Open with string field succeeded; want error")
+	}
+	if _, err := Open(name, func(x, y *int) bool { return *x < *y }); err == nil {
+		t.Error("Open with pointer type succeeded; want error")
+	}
+}
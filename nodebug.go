@@ -0,0 +1,11 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !sliceheap_debug
+
+package sliceheap
+
+const debug = false
+
+func check[T any](op string, h []T, less func(x, y T) bool) {}
@@ -0,0 +1,60 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// An OrderedHeap is a min-heap of ordered elements, ordered by [cmp.Less].
+// It is a slice kept in heap order by its methods, so it can be indexed and
+// ranged over directly, and the root h[0] is its minimum element.
+//
+// The zero value is an empty heap ready to use, so an OrderedHeap can be
+// embedded in another struct or declared as a variable without
+// initialization:
+//
+//	var h sliceheap.OrderedHeap[int]
+//	h.Push(3)
+//	h.Push(1)
+//	min := h.Pop() // 1
+type OrderedHeap[T cmp.Ordered] []T
+
+// Len returns the number of elements in the heap.
+func (h OrderedHeap[T]) Len() int {
+	return len(h)
+}
+
+// Grow is like [Grow] for the heap.
+func (h *OrderedHeap[T]) Grow(n int) {
+	Grow((*[]T)(h), n)
+}
+
+// Push pushes the element x onto the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *OrderedHeap[T]) Push(x T) {
+	Push((*[]T)(h), x)
+}
+
+// Pop removes and returns the minimum element from the heap.
+// The complexity is O(log n) where n = h.Len().
+// Pop panics if the heap is empty.
+func (h *OrderedHeap[T]) Pop() T {
+	return Pop((*[]T)(h))
+}
+
+// Peek returns the minimum element without removing it.
+// Peek panics if the heap is empty.
+func (h OrderedHeap[T]) Peek() T {
+	if len(h) == 0 {
+		panic("sliceheap: Peek on empty heap")
+	}
+	return h[0]
+}
+
+// Record returns a [Recorder] for the heap, starting from its current
+// contents. Operations made through the Recorder change the heap and are
+// recorded in its trace; operations made on the heap directly are not.
+func (h *OrderedHeap[T]) Record() *Recorder[T] {
+	return NewRecorder((*[]T)(h))
+}
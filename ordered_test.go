@@ -0,0 +1,44 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "testing"
+
+func TestOrderedHeapZero(t *testing.T) {
+	var h OrderedHeap[int]
+	if h.Len() != 0 {
+		t.Fatalf("Len() = %d; want 0", h.Len())
+	}
+	for _, x := range []int{5, 2, 8, 2, 1, 9} {
+		h.Push(x)
+		verify(t, h)
+	}
+	if h.Len() != 6 || h[0] != 1 {
+		t.Fatalf("Len() = %d, h[0] = %d; want 6, 1", h.Len(), h[0])
+	}
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+		verify(t, h)
+	}
+	want := []int{1, 2, 2, 5, 8, 9}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("popped %v; want %v", got, want)
+		}
+	}
+}
+
+func TestOrderedHeapEmbedded(t *testing.T) {
+	var s struct {
+		name string
+		OrderedHeap[string]
+	}
+	s.Push("b")
+	s.Push("a")
+	if got := s.Pop(); got != "a" {
+		t.Errorf("Pop() = %q; want \"a\"", got)
+	}
+}
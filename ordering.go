@@ -0,0 +1,69 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// An Ordering is a type whose Less method defines the order of a heap, named
+// in the type rather than passed as a less function. Its zero value is
+// used, so it is normally an empty struct, as are [Min] and [Max]. The
+// *With functions take the ordering as their first type parameter:
+//
+//	sliceheap.PushWith[sliceheap.Max[int]](&h, x)
+//
+// The *With functions call the *Func functions with the ordering's Less
+// method. They are a convenience, not an optimization, and can be slower:
+// orderings with the same shape share an instantiation, so Less is called
+// through a dictionary and is not inlined.
+type Ordering[T any] interface {
+	Less(x, y T) bool
+}
+
+// Min orders a heap with the least element at the root, as [Init] does.
+type Min[T cmp.Ordered] struct{}
+
+// Less reports whether x < y, with NaNs ordered before other values as by
+// [cmp.Less].
+func (Min[T]) Less(x, y T) bool {
+	return cmp.Less(x, y)
+}
+
+// Max orders a heap with the greatest element at the root.
+type Max[T cmp.Ordered] struct{}
+
+// Less reports whether x > y, with NaNs ordered after other values.
+func (Max[T]) Less(x, y T) bool {
+	return cmp.Less(y, x)
+}
+
+// InitWith is like [Init] but orders elements by the ordering O.
+func InitWith[O Ordering[T], T any](h []T) {
+	InitFunc(h, orderingLess[O, T])
+}
+
+// PushWith is like [Push] but orders elements by the ordering O.
+func PushWith[O Ordering[T], T any](h *[]T, x T) {
+	PushFunc(h, x, orderingLess[O, T])
+}
+
+// PopWith is like [Pop] but orders elements by the ordering O.
+func PopWith[O Ordering[T], T any](h *[]T) T {
+	return PopFunc(h, orderingLess[O, T])
+}
+
+// RemoveWith is like [Remove] but orders elements by the ordering O.
+func RemoveWith[O Ordering[T], T any](h *[]T, i int) T {
+	return RemoveFunc(h, i, orderingLess[O, T])
+}
+
+// FixWith is like [Fix] but orders elements by the ordering O.
+func FixWith[O Ordering[T], T any](h []T, i int) {
+	FixFunc(h, i, orderingLess[O, T])
+}
+
+func orderingLess[O Ordering[T], T any](x, y T) bool {
+	var o O
+	return o.Less(x, y)
+}
@@ -0,0 +1,67 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestOrderingWith(t *testing.T) {
+	h := rand.Perm(100)
+	InitWith[Max[int]](h)
+	if err := VerifyFunc(h, Max[int]{}.Less); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 50 {
+		PushWith[Max[int]](&h, 100+i)
+	}
+	RemoveWith[Max[int]](&h, 7)
+	h[3] = -1
+	FixWith[Max[int]](h, 3)
+	if err := VerifyFunc(h, Max[int]{}.Less); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for len(h) > 0 {
+		got = append(got, PopWith[Max[int]](&h))
+	}
+	if !slices.IsSortedFunc(got, func(x, y int) int { return y - x }) || len(got) != 149 {
+		t.Errorf("popped %v; want 149 elements in descending order", got)
+	}
+
+	m := []string{"c", "a", "b"}
+	InitWith[Min[string]](m)
+	if x := PopWith[Min[string]](&m); x != "a" {
+		t.Errorf("PopWith[Min] = %q; want a", x)
+	}
+}
+
+func BenchmarkPushPopMaxWith(b *testing.B) {
+	h := make([]int, 0, 1000)
+	for range b.N {
+		for j := range 1000 {
+			PushWith[Max[int]](&h, j)
+		}
+		for len(h) > 0 {
+			PopWith[Max[int]](&h)
+		}
+	}
+}
+
+func BenchmarkPushPopMaxFunc(b *testing.B) {
+	greater := func(x, y int) bool { return x > y }
+	h := make([]int, 0, 1000)
+	for range b.N {
+		for j := range 1000 {
+			PushFunc(&h, j, greater)
+		}
+		for len(h) > 0 {
+			PopFunc(&h, greater)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "cmp"
+
+// A Pair is a key and an associated value, such as a map entry, for use as
+// a heap element.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// ByKey is a less function ordering pairs by key.
+func ByKey[K cmp.Ordered, V any](x, y Pair[K, V]) bool {
+	return cmp.Less(x.Key, y.Key)
+}
+
+// ByValue is a less function ordering pairs by value.
+func ByValue[K any, V cmp.Ordered](x, y Pair[K, V]) bool {
+	return cmp.Less(x.Value, y.Value)
+}
+
+// FromMap returns a heap, ordered by less, of the entries of m. For
+// example, FromMap(counts, ByValue) returns the entries with the smallest
+// counts first.
+// The complexity is O(n) where n = len(m).
+func FromMap[M ~map[K]V, K comparable, V any](m M, less func(x, y Pair[K, V]) bool) []Pair[K, V] {
+	h := make([]Pair[K, V], 0, len(m))
+	for k, v := range m {
+		h = append(h, Pair[K, V]{k, v})
+	}
+	InitFunc(h, less)
+	return h
+}
+
+// KeyOrder is an [Ordering] of pairs by key, for use with the *With
+// functions. Unlike [ByKey], it is fixed by the type rather than passed as
+// a less function.
+type KeyOrder[K cmp.Ordered, V any] struct{}
+
+// Less reports whether x's key orders before y's.
+func (KeyOrder[K, V]) Less(x, y Pair[K, V]) bool {
+	return cmp.Less(x.Key, y.Key)
+}
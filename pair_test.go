@@ -0,0 +1,32 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromMap(t *testing.T) {
+	counts := map[string]int{"b": 3, "a": 7, "d": 1, "c": 5}
+
+	h := FromMap(counts, ByValue)
+	var got []string
+	for len(h) > 0 {
+		got = append(got, PopFunc(&h, ByValue).Key)
+	}
+	if s := strings.Join(got, ""); s != "dbca" {
+		t.Errorf("by value popped %s; want dbca", s)
+	}
+
+	h = FromMap(counts, ByKey)
+	got = got[:0]
+	for len(h) > 0 {
+		got = append(got, PopFunc(&h, ByKey[string, int]).Key)
+	}
+	if s := strings.Join(got, ""); s != "abcd" {
+		t.Errorf("by key popped %s; want abcd", s)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// A PercentileTracker computes a high percentile, such as the 99th, of a
+// stream over consecutive windows of a fixed number of samples. Rather than
+// storing every sample, it keeps only the largest samples of the current
+// window, which for the p-th percentile are about the top (1-p) fraction of
+// the window. This makes it cheap for the tail percentiles used in latency
+// monitoring.
+//
+// The samples at or above the percentile are kept in a min-heap whose root is
+// the percentile. Below them, a max-heap holds the samples that may yet be
+// promoted as the window fills; it is trimmed whenever it grows to twice the
+// number of promotions remaining.
+type PercentileTracker[T any] struct {
+	p      float64
+	window int
+	less   func(x, y T) bool
+	hi     []T // min-heap of the samples at or above the percentile
+	lo     []T // max-heap of candidates for promotion to hi
+	n      int // samples in the current window
+	last   T
+	full   bool // whether a window has completed
+}
+
+// NewPercentileTracker returns a tracker for the p-th percentile, where
+// 0 < p <= 1, over windows of windowSize samples.
+// It panics if p or windowSize is out of range.
+func NewPercentileTracker[T cmp.Ordered](p float64, windowSize int) *PercentileTracker[T] {
+	return NewPercentileTrackerFunc(p, windowSize, cmp.Less[T])
+}
+
+// NewPercentileTrackerFunc is like [NewPercentileTracker] but uses a less function to compare elements.
+func NewPercentileTrackerFunc[T any](p float64, windowSize int, less func(x, y T) bool) *PercentileTracker[T] {
+	if !(p > 0 && p <= 1) {
+		panic(fmt.Sprintf("sliceheap: percentile %v out of range (0,1]", p))
+	}
+	if windowSize < 1 {
+		panic(fmt.Sprintf("sliceheap: window size %d < 1", windowSize))
+	}
+	return &PercentileTracker[T]{p: p, window: windowSize, less: less}
+}
+
+func (t *PercentileTracker[T]) greater(x, y T) bool {
+	return t.less(y, x)
+}
+
+// keep returns the number of largest samples that must be kept to know the
+// percentile of n samples. The percentile is the sample of nearest rank
+// ceil(p*n), counting from one, which is the smallest of the top
+// n-ceil(p*n)+1 samples.
+func (t *PercentileTracker[T]) keep(n int) int {
+	return n - int(math.Ceil(t.p*float64(n))) + 1
+}
+
+// Add adds the sample x to the current window, starting a new window if the
+// current one is full.
+// The complexity is amortized O(log k) where k is the number of samples kept.
+func (t *PercentileTracker[T]) Add(x T) {
+	if t.n == t.window {
+		t.last, t.full = t.hi[0], true
+		clear(t.hi)
+		clear(t.lo)
+		t.hi, t.lo, t.n = t.hi[:0], t.lo[:0], 0
+	}
+	t.n++
+	if len(t.hi) > 0 && t.less(t.hi[0], x) {
+		PushFunc(&t.hi, x, t.less)
+		PushFunc(&t.lo, PopFunc(&t.hi, t.less), t.greater)
+	} else {
+		PushFunc(&t.lo, x, t.greater)
+	}
+	// keep(n) grows by at most one with each sample.
+	if len(t.hi) < t.keep(t.n) {
+		PushFunc(&t.hi, PopFunc(&t.lo, t.greater), t.less)
+	}
+
+	// At most this many samples will be promoted from lo before the
+	// window is full, so only the largest that many are needed.
+	if l := t.keep(t.window) - t.keep(t.n); len(t.lo) > 2*l+8 {
+		slices.SortFunc(t.lo, func(x, y T) int {
+			switch {
+			case t.less(y, x):
+				return -1
+			case t.less(x, y):
+				return 1
+			}
+			return 0
+		})
+		// A slice sorted in descending order is a valid max-heap.
+		clear(t.lo[l:])
+		t.lo = t.lo[:l]
+	}
+}
+
+// Query returns the p-th percentile of the samples in the current window,
+// and reports false if the window is empty.
+// The complexity is O(1).
+func (t *PercentileTracker[T]) Query() (T, bool) {
+	if t.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return t.hi[0], true
+}
+
+// Last returns the p-th percentile of the most recent complete window, and
+// reports false if no window has completed.
+func (t *PercentileTracker[T]) Last() (T, bool) {
+	return t.last, t.full
+}
@@ -0,0 +1,49 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func nearestRank(s []int, p float64) int {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s[int(math.Ceil(p*float64(len(s))))-1]
+}
+
+func TestPercentileTracker(t *testing.T) {
+	for _, p := range []float64{0.5, 0.9, 0.99, 1} {
+		const window = 200
+		pt := NewPercentileTracker[int](p, window)
+		if _, ok := pt.Query(); ok {
+			t.Error("Query() on empty tracker succeeded")
+		}
+
+		var cur, prev []int
+		for i := 0; i < 3*window+10; i++ {
+			if len(cur) == window {
+				prev, cur = cur, nil
+			}
+			x := rand.Intn(1000)
+			pt.Add(x)
+			cur = append(cur, x)
+
+			if got, _ := pt.Query(); got != nearestRank(cur, p) {
+				t.Fatalf("p=%v i=%d: Query() = %d; want %d", p, i, got, nearestRank(cur, p))
+			}
+			got, ok := pt.Last()
+			if ok != (prev != nil) || ok && got != nearestRank(prev, p) {
+				t.Fatalf("p=%v i=%d: Last() = %d, %v", p, i, got, ok)
+			}
+		}
+		if n, max := len(pt.hi)+len(pt.lo), 3*(int((1-p)*window)+1)+8; n > max {
+			t.Errorf("p=%v: %d samples kept; want at most %d", p, n, max)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pgroup provides a group of goroutines, in the style of
+// golang.org/x/sync/errgroup, whose functions run on a bounded number of
+// workers in priority order.
+package pgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/buth/sliceheap"
+)
+
+// A Group runs functions passed to Go on at most a fixed number of
+// goroutines at a time. Whenever a worker is free it takes the queued
+// function with the highest priority; functions of equal priority run in
+// the order they were queued. The first function to return a non-nil error
+// cancels the group: its context, if made by WithContext, is canceled, and
+// functions still queued are discarded without running.
+//
+// A Group must be created with New or WithContext.
+type Group struct {
+	workers int
+	cancel  context.CancelCauseFunc
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	q       []task
+	seq     uint64
+	running int
+	err     error
+}
+
+type task struct {
+	priority int
+	seq      uint64
+	f        func() error
+}
+
+func less(x, y task) bool {
+	if x.priority != y.priority {
+		return x.priority > y.priority
+	}
+	return x.seq < y.seq
+}
+
+// New returns a group running functions on at most workers goroutines.
+// It panics if workers < 1.
+func New(workers int) *Group {
+	if workers < 1 {
+		panic(fmt.Sprintf("pgroup: workers %d < 1", workers))
+	}
+	return &Group{workers: workers}
+}
+
+// WithContext is like [New] but also returns a context derived from ctx,
+// which is canceled when a function returns an error or when Wait returns.
+func WithContext(ctx context.Context, workers int) (*Group, context.Context) {
+	g := New(workers)
+	ctx, g.cancel = context.WithCancelCause(ctx)
+	return g, ctx
+}
+
+// Go queues f to run with the given priority. If the group has been
+// canceled by an error, f is discarded.
+func (g *Group) Go(priority int, f func() error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return
+	}
+	sliceheap.PushFunc(&g.q, task{priority, g.seq, f}, less)
+	g.seq++
+	if g.running < g.workers {
+		g.running++
+		g.wg.Add(1)
+		go g.work()
+	}
+}
+
+func (g *Group) work() {
+	defer g.wg.Done()
+	for {
+		g.mu.Lock()
+		if len(g.q) == 0 || g.err != nil {
+			g.running--
+			g.mu.Unlock()
+			return
+		}
+		t := sliceheap.PopFunc(&g.q, less)
+		g.mu.Unlock()
+
+		if err := t.f(); err != nil {
+			g.fail(err)
+		}
+	}
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return
+	}
+	g.err = err
+	clear(g.q)
+	g.q = g.q[:0]
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+}
+
+// Wait waits until every function queued has run or been discarded, then
+// returns the first non-nil error returned, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
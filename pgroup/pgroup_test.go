@@ -0,0 +1,86 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgroup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPriorityOrder(t *testing.T) {
+	g := New(1)
+	var mu sync.Mutex
+	var order []int
+	block := make(chan struct{})
+	g.Go(100, func() error { <-block; return nil }) // occupies the worker
+	for i, p := range []int{1, 5, 3, 5, 9} {
+		g.Go(p, func() error {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			return nil
+		})
+	}
+	close(block)
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 1, 3, 2, 0}; !slices.Equal(order, want) {
+		t.Errorf("ran %v; want %v", order, want)
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	g, ctx := WithContext(context.Background(), 1)
+	block := make(chan struct{})
+	ran := false
+	g.Go(10, func() error { <-block; return errBoom })
+	g.Go(1, func() error { ran = true; return nil })
+	close(block)
+	if err := g.Wait(); err != errBoom {
+		t.Errorf("Wait() = %v; want %v", err, errBoom)
+	}
+	if ran {
+		t.Error("queued function ran after an error")
+	}
+	if context.Cause(ctx) != errBoom {
+		t.Errorf("context cause = %v; want %v", context.Cause(ctx), errBoom)
+	}
+	g.Go(1, func() error { ran = true; return nil })
+	if g.Wait(); ran {
+		t.Error("function queued after an error ran")
+	}
+}
+
+func TestWorkerLimit(t *testing.T) {
+	g, ctx := WithContext(context.Background(), 3)
+	var mu sync.Mutex
+	active, peak := 0, 0
+	for i := range 50 {
+		g.Go(i%7, func() error {
+			mu.Lock()
+			active++
+			peak = max(peak, active)
+			mu.Unlock()
+			mu.Lock()
+			active--
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency %d; want at most 3", peak)
+	}
+	if ctx.Err() == nil {
+		t.Error("context not canceled after Wait")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pipe provides a bounded, priority-ordered pipe between
+// goroutines: the analogue of a buffered channel that delivers the best
+// value first rather than the oldest.
+package pipe
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/internal/notify"
+)
+
+// ErrClosed is returned by Send on a closed pipe, and by Recv once a closed
+// pipe is drained.
+var ErrClosed = errors.New("pipe: closed")
+
+// A Pipe connects producers to consumers through a heap of bounded
+// capacity. Send blocks while the pipe is full, applying backpressure to
+// producers; Recv blocks while it is empty and returns the least value
+// held, values that compare equal being received in the order they were
+// sent.
+//
+// A Pipe must be created with New or NewFunc. It is safe for concurrent use
+// by multiple goroutines.
+type Pipe[T any] struct {
+	capacity int
+	less     func(x, y T) bool
+
+	mu     sync.Mutex
+	h      []item[T]
+	seq    uint64
+	closed bool
+	avail  chan struct{} // closed when a value is sent or on close
+	space  chan struct{} // closed when a value is received or on close
+}
+
+type item[T any] struct {
+	x   T
+	seq uint64
+}
+
+// New returns a pipe holding up to capacity values, from which the least
+// is received first.
+// It panics if capacity < 1.
+func New[T cmp.Ordered](capacity int) *Pipe[T] {
+	return NewFunc(capacity, cmp.Less[T])
+}
+
+// NewFunc is like [New] but uses a less function to compare values.
+func NewFunc[T any](capacity int, less func(x, y T) bool) *Pipe[T] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("pipe: capacity %d < 1", capacity))
+	}
+	return &Pipe[T]{capacity: capacity, less: less}
+}
+
+func (p *Pipe[T]) lessItem(x, y item[T]) bool {
+	if p.less(x.x, y.x) {
+		return true
+	}
+	if p.less(y.x, x.x) {
+		return false
+	}
+	return x.seq < y.seq
+}
+
+// Send adds x to the pipe, waiting while it is full. It returns ErrClosed
+// if the pipe is closed, and ctx.Err() if ctx is done before there is
+// room.
+func (p *Pipe[T]) Send(ctx context.Context, x T) error {
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return ErrClosed
+		}
+		if len(p.h) < p.capacity {
+			break
+		}
+		space := notify.Wait(&p.space)
+		p.mu.Unlock()
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	sliceheap.PushFunc(&p.h, item[T]{x, p.seq}, p.lessItem)
+	p.seq++
+	notify.Close(&p.avail)
+	p.mu.Unlock()
+	return nil
+}
+
+// Recv removes and returns the least value in the pipe, waiting while it
+// is empty. It returns ErrClosed if the pipe is closed and empty, and
+// ctx.Err() if ctx is done before a value is sent.
+func (p *Pipe[T]) Recv(ctx context.Context) (T, error) {
+	p.mu.Lock()
+	for len(p.h) == 0 {
+		if p.closed {
+			p.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		avail := notify.Wait(&p.avail)
+		p.mu.Unlock()
+		select {
+		case <-avail:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	x := sliceheap.PopFunc(&p.h, p.lessItem).x
+	notify.Close(&p.space)
+	p.mu.Unlock()
+	return x, nil
+}
+
+// Len returns the number of values in the pipe.
+func (p *Pipe[T]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.h)
+}
+
+// Close closes the pipe for sending. Blocked and later calls to Send
+// return ErrClosed; the values already in the pipe may still be received.
+// Close is idempotent.
+func (p *Pipe[T]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	notify.Close(&p.avail)
+	notify.Close(&p.space)
+}
@@ -0,0 +1,91 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipe(t *testing.T) {
+	ctx := context.Background()
+	p := New[int](3)
+	for _, x := range []int{5, 1, 3} {
+		if err := p.Send(ctx, x); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := p.Send(short, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send to full pipe = %v; want deadline exceeded", err)
+	}
+
+	sent := make(chan error)
+	go func() { sent <- p.Send(ctx, 2) }()
+	if x, _ := p.Recv(ctx); x != 1 {
+		t.Errorf("Recv = %d; want 1", x)
+	}
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+
+	p.Close()
+	if err := p.Send(ctx, 0); err != ErrClosed {
+		t.Errorf("Send after Close = %v; want ErrClosed", err)
+	}
+	for _, want := range []int{2, 3, 5} {
+		if x, err := p.Recv(ctx); x != want || err != nil {
+			t.Errorf("Recv = %d, %v; want %d", x, err, want)
+		}
+	}
+	if _, err := p.Recv(ctx); err != ErrClosed {
+		t.Errorf("Recv on drained pipe = %v; want ErrClosed", err)
+	}
+}
+
+func TestPipeConcurrent(t *testing.T) {
+	ctx := context.Background()
+	p := New[int](4)
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 250 {
+				if err := p.Send(ctx, w*250+i); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		p.Close()
+	}()
+
+	seen := make(map[int]bool)
+	for {
+		x, err := p.Recv(ctx)
+		if err == ErrClosed {
+			break
+		}
+		seen[x] = true
+	}
+	if len(seen) != 1000 {
+		t.Errorf("received %d distinct values; want 1000", len(seen))
+	}
+
+	empty := New[int](1)
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := empty.Recv(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Recv on empty pipe = %v; want deadline exceeded", err)
+	}
+}
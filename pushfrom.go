@@ -0,0 +1,117 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// PushSeq pushes the elements of seq onto the heap. When seq adds more
+// elements than the heap held, they are appended and the heap is rebuilt
+// as by Init, which is cheaper than pushing them one at a time.
+// The complexity is O(min(k log(n+k), n+k)) where n = len(*h) and k is the
+// number of elements added.
+func PushSeq[T cmp.Ordered](h *[]T, seq iter.Seq[T]) {
+	PushSeqFunc(h, seq, cmp.Less[T])
+}
+
+// PushSeqFunc is like [PushSeq] but uses a less function to compare elements.
+func PushSeqFunc[T any](h *[]T, seq iter.Seq[T], less func(x, y T) bool) {
+	n := len(*h)
+	for x := range seq {
+		*h = append(*h, x)
+	}
+	if k := len(*h) - n; k > n {
+		InitFunc(*h, less)
+	} else {
+		for i := n; i < len(*h); i++ {
+			up(*h, i, less)
+		}
+		if debug {
+			check("PushSeq", *h, less)
+		}
+	}
+}
+
+// PushFrom receives elements from ch and pushes them onto the heap until ch
+// is closed, when it returns nil, or ctx is done, when it returns the
+// context's error. The heap must not be used by other goroutines until
+// PushFrom returns; [PushFromCond] allows them to.
+// The complexity is O(log n) per element, where n = len(*h).
+func PushFrom[T cmp.Ordered](ctx context.Context, h *[]T, ch <-chan T) error {
+	return PushFromFunc(ctx, h, ch, cmp.Less[T])
+}
+
+// PushFromFunc is like [PushFrom] but uses a less function to compare elements.
+func PushFromFunc[T any](ctx context.Context, h *[]T, ch <-chan T, less func(x, y T) bool) error {
+	for {
+		select {
+		case x, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			PushFunc(h, x, less)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// maxFromBatch is the most elements PushFromCond adds under one lock.
+const maxFromBatch = 256
+
+// PushFromCond is like [PushFrom] but allows other goroutines to use the
+// heap while it runs, provided they hold c.L. It receives every element
+// already waiting on ch, up to a limit, without holding the lock, then
+// locks c.L, adds the batch as [PushSeq] does, unlocks, and calls
+// c.Broadcast, so consumers can wait on c for elements to arrive.
+// The complexity is O(log n) per element, where n = len(*h).
+func PushFromCond[T cmp.Ordered](ctx context.Context, c *sync.Cond, h *[]T, ch <-chan T) error {
+	return PushFromCondFunc(ctx, c, h, ch, cmp.Less[T])
+}
+
+// PushFromCondFunc is like [PushFromCond] but uses a less function to compare elements.
+func PushFromCondFunc[T any](ctx context.Context, c *sync.Cond, h *[]T, ch <-chan T, less func(x, y T) bool) error {
+	var batch []T
+	for {
+		select {
+		case x, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			batch, ok = receiveBatch(append(batch[:0], x), ch)
+			c.L.Lock()
+			PushSeqFunc(h, slices.Values(batch), less)
+			c.L.Unlock()
+			c.Broadcast()
+			if !ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// receiveBatch appends to batch the elements waiting on ch, up to
+// maxFromBatch in all, and reports false if ch was found closed.
+func receiveBatch[T any](batch []T, ch <-chan T) ([]T, bool) {
+	for len(batch) < maxFromBatch {
+		select {
+		case x, ok := <-ch:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, x)
+		default:
+			return batch, true
+		}
+	}
+	return batch, true
+}
@@ -0,0 +1,91 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPushSeq(t *testing.T) {
+	for _, k := range []int{0, 1, 5, 50, 500} {
+		var h []int
+		for range 50 {
+			Push(&h, rand.Intn(100))
+		}
+		add := make([]int, k)
+		for i := range add {
+			add[i] = rand.Intn(100)
+		}
+		PushSeq(&h, slices.Values(add))
+		verify(t, h)
+		if len(h) != 50+k {
+			t.Errorf("len = %d after adding %d to 50; want %d", len(h), k, 50+k)
+		}
+	}
+}
+
+func TestPushFrom(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for _, x := range []int{4, 1, 3, 2} {
+			ch <- x
+		}
+		close(ch)
+	}()
+	var h []int
+	if err := PushFrom(context.Background(), &h, ch); err != nil {
+		t.Fatal(err)
+	}
+	verify(t, h)
+	if len(h) != 4 || h[0] != 1 {
+		t.Errorf("heap %v; want 4 elements with minimum 1", h)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := PushFrom(ctx, &h, make(chan int)); err != context.Canceled {
+		t.Errorf("PushFrom with canceled context = %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestPushFromCond(t *testing.T) {
+	var mu sync.Mutex
+	c := sync.NewCond(&mu)
+	var h []int
+	ch := make(chan int, 100)
+	done := make(chan error)
+	go func() { done <- PushFromCond(context.Background(), c, &h, ch) }()
+
+	// A consumer pops concurrently, waiting on c for elements.
+	go func() {
+		for i := range 1000 {
+			ch <- 1000 - i
+		}
+		close(ch)
+	}()
+	popped := 0
+	mu.Lock()
+	for popped < 1000 {
+		for len(h) == 0 {
+			c.Wait()
+		}
+		Pop(&h)
+		popped++
+	}
+	mu.Unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := PushFromCond(ctx, c, &h, make(chan int)); err != context.Canceled {
+		t.Errorf("PushFromCond with canceled context = %v; want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "iter"
+
+// A PriorityQueue is a queue that pops its least element first. It is the
+// method set shared by [OrderedHeap], [Journal], [Recorder] and [Counter],
+// so code that only pushes and pops can accept any of them, or another
+// implementation, without change; the scheduler package's NewWithQueue
+// takes one. Pop and Peek panic if the queue is empty.
+//
+// Heaps whose Pop and Peek report an empty heap instead of panicking have
+// a Queue method returning them as a PriorityQueue: [FixedHeap.Queue],
+// [BudgetHeap.Queue] and [IndexHeap.Queue].
+type PriorityQueue[T any] interface {
+	Len() int
+	Push(x T)
+	Pop() T
+	Peek() T
+}
+
+// Drain returns an iterator that pops the elements of q in ascending
+// order. If iteration stops early, the remaining elements stay in q.
+// Elements pushed onto q during iteration are included.
+func Drain[T any](q PriorityQueue[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for q.Len() > 0 {
+			if !yield(q.Pop()) {
+				return
+			}
+		}
+	}
+}
+
+// nonEmpty returns x, panicking if ok is false. It adapts the result of a
+// Pop or Peek that reports an empty heap.
+func nonEmpty[T any](x T, ok bool) T {
+	if !ok {
+		panic("sliceheap: Pop or Peek on empty heap")
+	}
+	return x
+}
@@ -0,0 +1,65 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+var (
+	_ PriorityQueue[int] = (*OrderedHeap[int])(nil)
+	_ PriorityQueue[int] = (*Journal[int])(nil)
+	_ PriorityQueue[int] = (*Recorder[int])(nil)
+	_ PriorityQueue[int] = (*Counter[int])(nil)
+)
+
+func TestDrain(t *testing.T) {
+	var s []int
+	// The keys of the index heap increase with the index, so it pops
+	// indices in the same order as the other queues pop elements.
+	index := NewIndexHeap(make([]int, 10))
+	for i := range 10 {
+		index.DecreaseKey(i, i-10)
+		index.Remove(i)
+	}
+	for name, q := range map[string]PriorityQueue[int]{
+		"OrderedHeap": new(OrderedHeap[int]),
+		"Journal":     NewJournal(&[]int{}),
+		"Recorder":    NewRecorder(&s),
+		"Counter":     NewCounter(&[]int{}),
+		"FixedHeap":   NewFixedHeap(make([]int, 0, 4)).Queue(),
+		"BudgetHeap":  NewBudgetHeap(100, func(int) int { return 1 }).Queue(),
+		"IndexHeap":   index.Queue(),
+	} {
+		for _, x := range []int{5, 2, 8, 1} {
+			q.Push(x)
+		}
+		if x := q.Peek(); x != 1 {
+			t.Errorf("%s: Peek() = %d; want 1", name, x)
+		}
+		if got, want := slices.Collect(Drain(q)), []int{1, 2, 5, 8}; !slices.Equal(got, want) {
+			t.Errorf("%s: Drain = %v; want %v", name, got, want)
+		}
+	}
+}
+
+func TestQueueAdapterPanics(t *testing.T) {
+	shouldPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		f()
+	}
+	q := NewFixedHeap(make([]int, 0, 1)).Queue()
+	shouldPanic("Pop of empty FixedHeap queue", func() { q.Pop() })
+	shouldPanic("Peek of empty FixedHeap queue", func() { q.Peek() })
+	q.Push(1)
+	shouldPanic("Push onto full FixedHeap queue", func() { q.Push(2) })
+	shouldPanic("Pop of empty IndexHeap queue", func() { NewIndexHeap[int](nil).Queue().Pop() })
+}
@@ -0,0 +1,116 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package quota provides a shared priority queue that keeps any one
+// producer from monopolizing it.
+package quota
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/buth/sliceheap"
+)
+
+// ErrQuota is returned by Push when a producer has Limit items queued.
+var ErrQuota = errors.New("quota: producer limit exceeded")
+
+// A Queue is a priority queue shared by producers identified by keys of type
+// K. Items with higher priorities are popped first, and items of equal
+// priority in the order they were pushed. A producer that already has Share
+// items queued has further items demoted by Demotion, so that it cannot
+// crowd out other producers at the same priority, and one that has Limit
+// items queued has further pushes rejected.
+//
+// The exported fields configure the queue and must not be changed after it
+// is first used. The zero value is a queue with no quotas.
+// A Queue is safe for concurrent use by multiple goroutines.
+type Queue[K comparable, T any] struct {
+	// Share is the number of items a producer may have queued at their
+	// own priority. If zero, items are never demoted.
+	Share int
+
+	// Demotion is subtracted from the priority of items pushed beyond a
+	// producer's share.
+	Demotion int
+
+	// Limit is the number of items a producer may have queued. If zero,
+	// there is no limit.
+	Limit int
+
+	mu     sync.Mutex
+	h      []item[K, T]
+	seq    uint64
+	counts map[K]int
+}
+
+type item[K comparable, T any] struct {
+	priority int
+	seq      uint64
+	key      K
+	x        T
+}
+
+func less[K comparable, T any](x, y item[K, T]) bool {
+	if x.priority != y.priority {
+		return x.priority > y.priority
+	}
+	return x.seq < y.seq
+}
+
+// Len returns the number of items queued.
+func (q *Queue[K, T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.h)
+}
+
+// Queued returns the number of items queued by the producer key.
+func (q *Queue[K, T]) Queued(key K) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.counts[key]
+}
+
+// Push queues x for the producer key with the given priority, demoted if
+// the producer has exceeded its share. It returns ErrQuota, and does not
+// queue x, if the producer has reached its limit.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[K, T]) Push(key K, x T, priority int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := q.counts[key]
+	if q.Limit > 0 && n >= q.Limit {
+		return ErrQuota
+	}
+	if q.Share > 0 && n >= q.Share {
+		priority -= q.Demotion
+	}
+	if q.counts == nil {
+		q.counts = make(map[K]int)
+	}
+	q.counts[key] = n + 1
+	sliceheap.PushFunc(&q.h, item[K, T]{priority, q.seq, key, x}, less)
+	q.seq++
+	return nil
+}
+
+// Pop removes and returns the item with the highest priority and its
+// producer, and reports false if the queue is empty.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[K, T]) Pop() (K, T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.h) == 0 {
+		var zero item[K, T]
+		return zero.key, zero.x, false
+	}
+	it := sliceheap.PopFunc(&q.h, less)
+	if n := q.counts[it.key] - 1; n > 0 {
+		q.counts[it.key] = n
+	} else {
+		delete(q.counts, it.key)
+	}
+	return it.key, it.x, true
+}
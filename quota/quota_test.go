@@ -0,0 +1,83 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quota
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestDemotion(t *testing.T) {
+	q := &Queue[string, int]{Share: 2, Demotion: 1}
+	for i := range 5 {
+		q.Push("noisy", i, 5)
+	}
+	q.Push("quiet", 100, 5)
+	q.Push("quiet", 101, 5)
+
+	var got []string
+	for {
+		key, _, ok := q.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, key)
+	}
+	// The noisy producer's items beyond its share of two wait behind the
+	// quiet producer's.
+	want := []string{"noisy", "noisy", "quiet", "quiet", "noisy", "noisy", "noisy"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("popped %v; want %v", got, want)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	q := &Queue[int, int]{Limit: 3}
+	for i := range 3 {
+		if err := q.Push(1, i, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Push(1, 3, 0); err != ErrQuota {
+		t.Errorf("Push beyond limit = %v; want %v", err, ErrQuota)
+	}
+	if err := q.Push(2, 0, 0); err != nil {
+		t.Errorf("Push by other producer = %v", err)
+	}
+	q.Pop()
+	if q.Queued(1) != 2 {
+		t.Errorf("Queued(1) = %d; want 2", q.Queued(1))
+	}
+	if err := q.Push(1, 4, 0); err != nil {
+		t.Errorf("Push after Pop = %v", err)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	var q Queue[int, int]
+	var wg sync.WaitGroup
+	for p := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				q.Push(p, i, i%3)
+			}
+		}()
+	}
+	wg.Wait()
+	n := 0
+	for {
+		if _, _, ok := q.Pop(); !ok {
+			break
+		}
+		n++
+	}
+	if n != 800 || q.Len() != 0 || q.Queued(0) != 0 {
+		t.Errorf("popped %d; want 800 with none left", n)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ratelimit provides a token-bucket rate limiter that admits waiting
+// callers in priority order.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Limiter admits events at a sustained rate, allowing bursts of up to a
+// fixed size. Callers that cannot be admitted at once wait in a queue and
+// are admitted highest priority first as tokens become available; callers
+// of equal priority are admitted in the order they began waiting.
+//
+// A Limiter is safe for concurrent use by multiple goroutines.
+type Limiter struct {
+	// Clock is the source of time. If nil, clock.Real is used. It must
+	// not be changed after the limiter is first used.
+	Clock clock.Clock
+
+	rate  float64 // tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time // time tokens was last updated, or zero before first use
+	waiters []*waiter
+	seq     uint64
+	timer   clock.Timer // fires the next dispatch; nil if none is pending
+}
+
+type waiter struct {
+	priority int
+	seq      uint64
+	index    int
+	ready    chan struct{} // closed when admitted
+}
+
+func less(x, y *waiter) bool {
+	if x.priority != y.priority {
+		return x.priority > y.priority
+	}
+	return x.seq < y.seq
+}
+
+func setIndex(x *waiter, i int) {
+	x.index = i
+}
+
+// New returns a limiter admitting rate events per second with bursts of up to
+// burst events. The limiter starts with a full bucket.
+// It panics if rate is not positive or burst < 1.
+func New(rate float64, burst int) *Limiter {
+	if !(rate > 0) || burst < 1 {
+		panic(fmt.Sprintf("ratelimit: invalid rate %v or burst %d", rate, burst))
+	}
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (l *Limiter) clock() clock.Clock {
+	if l.Clock == nil {
+		return clock.Real
+	}
+	return l.Clock
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (l *Limiter) refill(now time.Time) {
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// Allow reports whether an event may happen now, consuming a token if so.
+// It never admits an event ahead of a waiting caller.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.clock().Now())
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
+
+// Wait blocks until the caller is admitted or ctx is done, in which case it
+// returns ctx.Err(). Waiting callers are admitted highest priority first.
+func (l *Limiter) Wait(ctx context.Context, priority int) error {
+	l.mu.Lock()
+	l.refill(l.clock().Now())
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	w := &waiter{priority: priority, seq: l.seq, ready: make(chan struct{})}
+	l.seq++
+	indexheap.Push(&l.waiters, w, less, setIndex)
+	l.schedule()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.index >= 0 {
+			indexheap.Remove(&l.waiters, w.index, less, setIndex)
+		} else {
+			// Admitted as ctx was done: return the token.
+			l.tokens++
+			l.dispatch()
+		}
+		return ctx.Err()
+	}
+}
+
+// Waiting returns the number of callers waiting in Wait.
+func (l *Limiter) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+// dispatch admits as many waiters as there are tokens, then schedules the
+// next dispatch if any remain. l.mu must be held.
+func (l *Limiter) dispatch() {
+	l.refill(l.clock().Now())
+	for len(l.waiters) > 0 && l.tokens >= 1 {
+		l.tokens--
+		w := indexheap.Pop(&l.waiters, less, setIndex)
+		close(w.ready)
+	}
+	l.schedule()
+}
+
+// schedule arranges for dispatch to run when the next token is available,
+// if anyone is waiting for it. l.mu must be held.
+func (l *Limiter) schedule() {
+	if len(l.waiters) == 0 {
+		return
+	}
+	d := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if l.timer == nil {
+		l.timer = l.clock().NewTimer(d)
+		go l.run(l.timer)
+	} else {
+		l.timer.Reset(d)
+	}
+}
+
+// run dispatches each time t fires, until no one is left waiting.
+func (l *Limiter) run(t clock.Timer) {
+	for range t.C() {
+		l.mu.Lock()
+		l.dispatch()
+		if len(l.waiters) == 0 {
+			l.timer = nil
+			l.mu.Unlock()
+			return
+		}
+		l.mu.Unlock()
+	}
+}
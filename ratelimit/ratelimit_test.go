@@ -0,0 +1,103 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+)
+
+func TestAllow(t *testing.T) {
+	l := New(1, 3)
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d = false within burst", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("Allow() = true with empty bucket")
+	}
+}
+
+func TestPriorityOrder(t *testing.T) {
+	l := New(20, 1) // a token every 50ms
+	if !l.Allow() {
+		t.Fatal("Allow() = false on full bucket")
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for _, p := range []int{1, 3, 2, 3, 0} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background(), p); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+		}()
+	}
+	// Let every caller join the queue before the first token arrives.
+	for l.Waiting() < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	wg.Wait()
+
+	if want := []int{3, 3, 2, 1, 0}; !slices.Equal(order, want) {
+		t.Errorf("admitted in order %v; want %v", order, want)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	l := New(0.001, 1)
+	l.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 0); err != context.DeadlineExceeded {
+		t.Errorf("Wait() = %v; want DeadlineExceeded", err)
+	}
+	if n := l.Waiting(); n != 0 {
+		t.Errorf("Waiting() = %d after cancellation; want 0", n)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	l := New(1, 1)
+	l.Clock = clk
+	l.Allow()
+	if l.Allow() {
+		t.Fatal("Allow() = true with empty bucket")
+	}
+	clk.Advance(time.Second)
+	if !l.Allow() {
+		t.Fatal("Allow() = false a second later")
+	}
+
+	done := make(chan error)
+	go func() { done <- l.Wait(context.Background(), 0) }()
+	for l.Waiting() == 0 || clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned %v before the clock advanced", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clk.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
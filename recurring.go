@@ -0,0 +1,171 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Rule computes the occurrences of a recurring entry. Next returns the
+// first occurrence after t, and reports false if there are no more.
+type Rule interface {
+	Next(t time.Time) (time.Time, bool)
+}
+
+// A RuleFunc is a function that is a [Rule].
+type RuleFunc func(t time.Time) (time.Time, bool)
+
+// Next returns f(t).
+func (f RuleFunc) Next(t time.Time) (time.Time, bool) {
+	return f(t)
+}
+
+// A SkipRule is a Rule that can skip ahead: After returns the first
+// occurrence after t of the sequence that includes prev, as repeated calls
+// of Next from prev would, without computing the occurrences in between.
+// [RecurringScheduler.Fire] uses it to catch up after a long stall.
+type SkipRule interface {
+	Rule
+	After(prev, t time.Time) (time.Time, bool)
+}
+
+// Every returns a rule recurring every d. The rule is a [SkipRule].
+// It panics if d <= 0.
+func Every(d time.Duration) Rule {
+	if d <= 0 {
+		panic(fmt.Sprintf("sliceheap: Every interval %v <= 0", d))
+	}
+	return every(d)
+}
+
+type every time.Duration
+
+func (d every) Next(t time.Time) (time.Time, bool) {
+	return t.Add(time.Duration(d)), true
+}
+
+func (d every) After(prev, t time.Time) (time.Time, bool) {
+	if t.Before(prev) {
+		return d.Next(prev)
+	}
+	k := t.Sub(prev)/time.Duration(d) + 1
+	return prev.Add(k * time.Duration(d)), true
+}
+
+// Daily returns a rule recurring every day at the given time of day in loc,
+// following the wall clock across daylight saving changes. The rule is a
+// [SkipRule].
+func Daily(hour, min int, loc *time.Location) Rule {
+	return daily{hour, min, loc}
+}
+
+type daily struct {
+	hour, min int
+	loc       *time.Location
+}
+
+func (r daily) Next(t time.Time) (time.Time, bool) {
+	t = t.In(r.loc)
+	next := time.Date(t.Year(), t.Month(), t.Day(), r.hour, r.min, 0, 0, r.loc)
+	if !next.After(t) {
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, r.hour, r.min, 0, 0, r.loc)
+	}
+	return next, true
+}
+
+// After returns the first occurrence after t; every day's occurrence is in
+// the same sequence.
+func (r daily) After(prev, t time.Time) (time.Time, bool) {
+	if t.Before(prev) {
+		t = prev
+	}
+	return r.Next(t)
+}
+
+// A RecurringScheduler holds entries that fire repeatedly according to
+// their rules, like an in-process cron table. Only the next occurrence of
+// each entry is held; firing an entry replaces it at the root of the heap
+// with its following occurrence.
+//
+// The zero value is an empty scheduler ready to use.
+type RecurringScheduler[T any] struct {
+	h   []recurringEntry[T]
+	seq uint64
+}
+
+type recurringEntry[T any] struct {
+	at   time.Time
+	seq  uint64
+	rule Rule
+	item T
+}
+
+func recurringLess[T any](x, y recurringEntry[T]) bool {
+	if !x.at.Equal(y.at) {
+		return x.at.Before(y.at)
+	}
+	return x.seq < y.seq
+}
+
+// Len returns the number of entries with an occurrence to come.
+func (s *RecurringScheduler[T]) Len() int {
+	return len(s.h)
+}
+
+// Add adds item, to fire at each occurrence of rule after now. It does
+// nothing if rule has no occurrence after now.
+// The complexity is O(log n) where n = s.Len().
+func (s *RecurringScheduler[T]) Add(item T, rule Rule, now time.Time) {
+	at, ok := rule.Next(now)
+	if !ok {
+		return
+	}
+	PushFunc(&s.h, recurringEntry[T]{at, s.seq, rule, item}, recurringLess[T])
+	s.seq++
+}
+
+// Next returns the time of the earliest occurrence, and reports false if
+// the scheduler is empty.
+// The complexity is O(1).
+func (s *RecurringScheduler[T]) Next() (time.Time, bool) {
+	if len(s.h) == 0 {
+		return time.Time{}, false
+	}
+	return s.h[0].at, true
+}
+
+// Fire returns the items with an occurrence at or before now, earliest
+// first, and schedules their next occurrences after now. An item whose
+// occurrences were missed, because Fire was not called in time, is
+// returned once and not once for each; the missed occurrences are skipped
+// at once if its rule is a [SkipRule], and stepped through otherwise.
+// The complexity is O(k log n) where k is the number of items returned and
+// n = s.Len().
+func (s *RecurringScheduler[T]) Fire(now time.Time) []T {
+	var r []T
+	for len(s.h) > 0 && !s.h[0].at.After(now) {
+		e := s.h[0]
+		r = append(r, e.item)
+		var at time.Time
+		var ok bool
+		if sr, isSkip := e.rule.(SkipRule); isSkip {
+			at, ok = sr.After(e.at, now)
+		} else {
+			at, ok = e.rule.Next(e.at)
+			for ok && !at.After(now) {
+				at, ok = e.rule.Next(at)
+			}
+		}
+		if !ok {
+			PopFunc(&s.h, recurringLess[T])
+			continue
+		}
+		e.at, e.seq = at, s.seq
+		s.seq++
+		ReplaceFunc(s.h, e, recurringLess[T])
+	}
+	return r
+}
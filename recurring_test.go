@@ -0,0 +1,94 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecurringScheduler(t *testing.T) {
+	t0 := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	var s RecurringScheduler[string]
+	s.Add("poll", Every(time.Minute), t0)
+	s.Add("flush", Every(90*time.Second), t0)
+	var left int
+	s.Add("thrice", RuleFunc(func(t time.Time) (time.Time, bool) {
+		left++
+		return t.Add(time.Minute), left <= 3
+	}), t0)
+
+	var got []string
+	for now := t0; !now.After(t0.Add(5 * time.Minute)); now = now.Add(30 * time.Second) {
+		got = append(got, s.Fire(now)...)
+	}
+	want := []string{
+		"poll", "thrice", // 1m
+		"flush",          // 1m30s
+		"poll", "thrice", // 2m
+		"flush", "poll", "thrice", // 3m
+		"poll",  // 4m
+		"flush", // 4m30s
+		"poll",  // 5m
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("fired %v; want %v", got, want)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d; want 2", s.Len())
+	}
+
+	// Missed occurrences fire once.
+	if got := s.Fire(t0.Add(time.Hour)); len(got) != 2 {
+		t.Errorf("Fire after an hour = %v; want each entry once", got)
+	}
+	if next, _ := s.Next(); !next.After(t0.Add(time.Hour)) {
+		t.Errorf("Next() = %v; want after the hour", next)
+	}
+}
+
+func TestDaily(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	r := Daily(9, 30, ny)
+	// Clocks go forward on 2024-03-10; 9:30 stays 9:30 local.
+	at, _ := r.Next(time.Date(2024, 3, 9, 10, 0, 0, 0, ny))
+	if want := time.Date(2024, 3, 10, 9, 30, 0, 0, ny); !at.Equal(want) {
+		t.Errorf("Next = %v; want %v", at, want)
+	}
+	if d := at.Sub(time.Date(2024, 3, 9, 9, 30, 0, 0, ny)); d != 23*time.Hour {
+		t.Errorf("interval across DST = %v; want 23h", d)
+	}
+	at, _ = r.Next(time.Date(2024, 3, 9, 9, 0, 0, 0, ny))
+	if want := time.Date(2024, 3, 9, 9, 30, 0, 0, ny); !at.Equal(want) {
+		t.Errorf("Next before the time = %v; want %v", at, want)
+	}
+}
+
+func TestRecurringSkip(t *testing.T) {
+	// A day's stall of a millisecond rule is caught up in one step.
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var s RecurringScheduler[string]
+	s.Add("tick", Every(time.Millisecond), start)
+	now := start.Add(24*time.Hour + 500*time.Microsecond)
+	if got := s.Fire(now); len(got) != 1 {
+		t.Fatalf("Fire after a stall = %v; want one tick", got)
+	}
+	if next, _ := s.Next(); !next.Equal(start.Add(24*time.Hour + time.Millisecond)) {
+		t.Errorf("Next() = %v; want the first tick after %v", next, now)
+	}
+
+	// A rule without After is stepped through to the same result.
+	step := RuleFunc(func(t time.Time) (time.Time, bool) { return t.Add(time.Hour), true })
+	s.Add("step", step, start)
+	s.Fire(start.Add(90 * time.Minute))
+	s.Fire(start.Add(5*time.Hour + time.Minute))
+	if got := s.Fire(start.Add(6 * time.Hour)); !slices.Contains(got, "step") {
+		t.Errorf("Fire at 6h = %v; want step", got)
+	}
+}
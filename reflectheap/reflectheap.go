@@ -0,0 +1,243 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package reflectheap provides heap operations for slices whose element
+// type is known only at run time, such as values decoded from
+// heterogeneous configuration or held by an interpreter. The slices are
+// passed as any and accessed through package reflect.
+//
+// The functions without the Func suffix order elements with [Less], which
+// handles the kinds of Go's ordered types. Slices of int, int64, uint64,
+// float64 and string are recognized by every such function and handled by
+// package sliceheap directly, without reflection.
+package reflectheap
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+
+	"github.com/buth/sliceheap"
+)
+
+// Less reports whether x orders before y. Both must have the same type,
+// whose kind is an integer, floating-point or string kind; NaNs order
+// before other values, as by [cmp.Less].
+// Less panics if the values are of different types or of another kind.
+func Less(x, y reflect.Value) bool {
+	if x.Type() != y.Type() {
+		panic(fmt.Sprintf("reflectheap: cannot compare %v with %v", x.Type(), y.Type()))
+	}
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() < y.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() < y.Uint()
+	case reflect.Float32, reflect.Float64:
+		return cmp.Less(x.Float(), y.Float())
+	case reflect.String:
+		return x.String() < y.String()
+	}
+	panic(fmt.Sprintf("reflectheap: values of type %v are not ordered", x.Type()))
+}
+
+// Init establishes the heap invariants of the slice h.
+// It panics if h is not a slice.
+// The complexity is O(n) where n is the length of h.
+func Init(h any) {
+	switch h := h.(type) {
+	case []int:
+		sliceheap.Init(h)
+	case []int64:
+		sliceheap.Init(h)
+	case []uint64:
+		sliceheap.Init(h)
+	case []float64:
+		sliceheap.Init(h)
+	case []string:
+		sliceheap.Init(h)
+	default:
+		InitFunc(h, Less)
+	}
+}
+
+// InitFunc is like [Init] but uses a less function to compare elements.
+func InitFunc(h any, less func(x, y reflect.Value) bool) {
+	s := sliceOf("Init", h)
+	swapper(s, less).Init(s.Len())
+}
+
+// Push pushes x onto the heap *h, where h is a pointer to a slice and x is
+// assignable to its element type.
+// It panics if h or x is of the wrong type.
+// The complexity is O(log n) where n is the length of *h.
+func Push(h, x any) {
+	if !pushFast(h, x) {
+		PushFunc(h, x, Less)
+	}
+}
+
+// pushFast pushes x onto h with package sliceheap if both are of a type it
+// recognizes, and reports whether it did.
+func pushFast(h, x any) bool {
+	switch h := h.(type) {
+	case *[]int:
+		return pushIf(h, x)
+	case *[]int64:
+		return pushIf(h, x)
+	case *[]uint64:
+		return pushIf(h, x)
+	case *[]float64:
+		return pushIf(h, x)
+	case *[]string:
+		return pushIf(h, x)
+	}
+	return false
+}
+
+func pushIf[T cmp.Ordered](h *[]T, x any) bool {
+	v, ok := x.(T)
+	if ok && h != nil {
+		sliceheap.Push(h, v)
+	}
+	return ok && h != nil
+}
+
+// PushFunc is like [Push] but uses a less function to compare elements.
+func PushFunc(h, x any, less func(x, y reflect.Value) bool) {
+	p := pointerOf("Push", h)
+	v := reflect.ValueOf(x)
+	if t := p.Type().Elem(); !v.IsValid() || !v.Type().AssignableTo(t) {
+		panic(fmt.Sprintf("reflectheap: cannot push %T onto heap of %v", x, t))
+	}
+	p.Set(reflect.Append(p, v))
+	swapper(p, less).Push(p.Len())
+}
+
+// Pop removes and returns the minimum element of the heap *h, where h is a
+// pointer to a slice.
+// It panics if h is not a pointer to a slice or the heap is empty.
+// The complexity is O(log n) where n is the length of *h.
+func Pop(h any) any {
+	if x, ok := removeFast(h, 0, true); ok {
+		return x
+	}
+	return PopFunc(h, Less)
+}
+
+// PopFunc is like [Pop] but uses a less function to compare elements.
+func PopFunc(h any, less func(x, y reflect.Value) bool) any {
+	p := pointerOf("Pop", h)
+	n := p.Len()
+	swapper(p, less).Pop(n)
+	return truncate(p, n-1)
+}
+
+// Remove removes and returns the element at index i of the heap *h, where
+// h is a pointer to a slice.
+// It panics if h is not a pointer to a slice or i is out of range.
+// The complexity is O(log n) where n is the length of *h.
+func Remove(h any, i int) any {
+	if x, ok := removeFast(h, i, false); ok {
+		return x
+	}
+	return RemoveFunc(h, i, Less)
+}
+
+// removeFast removes the element at index i of h, or pops the minimum if
+// pop is set, with package sliceheap if h is of a type it recognizes, and
+// reports whether it did.
+func removeFast(h any, i int, pop bool) (any, bool) {
+	switch h := h.(type) {
+	case *[]int:
+		return removeIf(h, i, pop)
+	case *[]int64:
+		return removeIf(h, i, pop)
+	case *[]uint64:
+		return removeIf(h, i, pop)
+	case *[]float64:
+		return removeIf(h, i, pop)
+	case *[]string:
+		return removeIf(h, i, pop)
+	}
+	return nil, false
+}
+
+func removeIf[T cmp.Ordered](h *[]T, i int, pop bool) (any, bool) {
+	switch {
+	case h == nil:
+		return nil, false
+	case pop:
+		return sliceheap.Pop(h), true
+	}
+	return sliceheap.Remove(h, i), true
+}
+
+// RemoveFunc is like [Remove] but uses a less function to compare elements.
+func RemoveFunc(h any, i int, less func(x, y reflect.Value) bool) any {
+	p := pointerOf("Remove", h)
+	n := p.Len()
+	swapper(p, less).Remove(n, i)
+	return truncate(p, n-1)
+}
+
+// Fix re-establishes the heap ordering of the slice h after the element at
+// index i has changed.
+// It panics if h is not a slice or i is out of range.
+// The complexity is O(log n) where n is the length of h.
+func Fix(h any, i int) {
+	switch h := h.(type) {
+	case []int:
+		sliceheap.Fix(h, i)
+	case []int64:
+		sliceheap.Fix(h, i)
+	case []uint64:
+		sliceheap.Fix(h, i)
+	case []float64:
+		sliceheap.Fix(h, i)
+	case []string:
+		sliceheap.Fix(h, i)
+	default:
+		FixFunc(h, i, Less)
+	}
+}
+
+// FixFunc is like [Fix] but uses a less function to compare elements.
+func FixFunc(h any, i int, less func(x, y reflect.Value) bool) {
+	s := sliceOf("Fix", h)
+	swapper(s, less).Fix(s.Len(), i)
+}
+
+func sliceOf(op string, h any) reflect.Value {
+	s := reflect.ValueOf(h)
+	if s.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("reflectheap: %s of %T, not a slice", op, h))
+	}
+	return s
+}
+
+func pointerOf(op string, h any) reflect.Value {
+	p := reflect.ValueOf(h)
+	if p.Kind() != reflect.Pointer || p.IsNil() || p.Elem().Kind() != reflect.Slice {
+		panic(fmt.Sprintf("reflectheap: %s of %T, not a pointer to a slice", op, h))
+	}
+	return p.Elem()
+}
+
+func swapper(s reflect.Value, less func(x, y reflect.Value) bool) sliceheap.SwapperHeap {
+	return sliceheap.SwapperHeap{
+		Less: func(i, j int) bool { return less(s.Index(i), s.Index(j)) },
+		Swap: reflect.Swapper(s.Interface()),
+	}
+}
+
+// truncate shortens the slice p to length n, returning the element removed
+// from index n and zeroing its slot.
+func truncate(p reflect.Value, n int) any {
+	e := p.Index(n)
+	x := e.Interface()
+	e.SetZero()
+	p.SetLen(n)
+	return x
+}
@@ -0,0 +1,114 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reflectheap
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestReflect(t *testing.T) {
+	type celsius float32
+	h := []celsius{30, -5, 12.5, 7, 100}
+	Init(h)
+	Push(&h, celsius(-40))
+	Remove(&h, 2)
+	h[0] = 50
+	Fix(h, 0)
+
+	var got []celsius
+	for len(h) > 0 {
+		got = append(got, Pop(&h).(celsius))
+	}
+	if !slices.IsSorted(got) || len(got) != 5 {
+		t.Errorf("popped %v; want 5 values in order", got)
+	}
+}
+
+func TestFastPath(t *testing.T) {
+	h := rand.Perm(100)
+	Init(h)
+	for i := range 10 {
+		Push(&h, 100+i)
+	}
+	for i := range 110 {
+		if x := Pop(&h).(int); x != i {
+			t.Fatalf("%d.th pop got %d", i, x)
+		}
+	}
+}
+
+func TestFastPathRemoveFix(t *testing.T) {
+	h := []string{"d", "a", "c", "b", "e"}
+	Init(h)
+	if x := Remove(&h, len(h)-1).(string); len(h) != 4 {
+		t.Fatalf("Remove returned %q, leaving %v", x, h)
+	}
+	h[0] = "z"
+	Fix(h, 0)
+	if got := Pop(&h).(string); got == "z" || got == "a" {
+		t.Errorf("Pop after Fix = %q", got)
+	}
+
+	// Fix on a recognized slice type does not go through reflection,
+	// which would allocate a swapper.
+	ints := rand.Perm(100)
+	Init(ints)
+	var a any = ints
+	if n := testing.AllocsPerRun(10, func() { Fix(a, 50) }); n != 0 {
+		t.Errorf("Fix of []int allocated %v times; want 0", n)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	type job struct {
+		Name string
+		Pri  int
+	}
+	byPri := func(x, y reflect.Value) bool {
+		return x.FieldByName("Pri").Int() > y.FieldByName("Pri").Int()
+	}
+	var h any = &[]job{}
+	for i, n := range []string{"a", "b", "c"} {
+		PushFunc(h, job{n, i}, byPri)
+	}
+	if j := PopFunc(h, byPri).(job); j.Name != "c" {
+		t.Errorf("PopFunc = %v; want c", j)
+	}
+}
+
+func TestLess(t *testing.T) {
+	nan := reflect.ValueOf(math.NaN())
+	if !Less(nan, reflect.ValueOf(-math.MaxFloat64)) {
+		t.Error("NaN not ordered first")
+	}
+	if !Less(reflect.ValueOf(uint8(1)), reflect.ValueOf(uint8(200))) {
+		t.Error("Less(1, 200) = false")
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"NotSlice":      func() { Init(3) },
+		"NotPointer":    func() { Pop([]int8{1}) },
+		"Unordered":     func() { Init([]struct{}{{}, {}}) },
+		"WrongType":     func() { Push(&[]int8{}, "x") },
+		"WrongFast":     func() { Push(&[]int{}, int32(1)) },
+		"DifferentType": func() { Less(reflect.ValueOf(1), reflect.ValueOf("1")) },
+		"Empty":         func() { Pop(&[]int8{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
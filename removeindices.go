@@ -0,0 +1,52 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// RemoveIndices removes the elements at the given indices of the heap, all
+// referring to positions before any removal, and returns them in the order
+// of indices. The remaining elements are compacted and the heap rebuilt in
+// a single pass, which is faster than repeated calls to Remove when many
+// elements are removed and avoids tracking how indices shift.
+// RemoveIndices panics if an index is out of range or repeated.
+// The complexity is O(n) where n = len(*h).
+func RemoveIndices[T cmp.Ordered](h *[]T, indices []int) []T {
+	return RemoveIndicesFunc(h, indices, cmp.Less[T])
+}
+
+// RemoveIndicesFunc is like [RemoveIndices] but uses a less function to compare elements.
+func RemoveIndicesFunc[T any](h *[]T, indices []int, less func(x, y T) bool) []T {
+	if len(indices) == 0 {
+		return nil
+	}
+	s := *h
+	removed := make([]bool, len(s))
+	out := make([]T, len(indices))
+	for j, i := range indices {
+		if uint(i) >= uint(len(s)) {
+			panicRange("RemoveIndices", i, len(s))
+		}
+		if removed[i] {
+			panic(fmt.Sprintf("sliceheap: RemoveIndices index %d repeated", i))
+		}
+		removed[i] = true
+		out[j] = s[i]
+	}
+	n := 0
+	for i, x := range s {
+		if !removed[i] {
+			s[n] = x
+			n++
+		}
+	}
+	clear(s[n:])
+	*h = s[:n]
+	InitFunc(*h, less)
+	return out
+}
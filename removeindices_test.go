@@ -0,0 +1,60 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRemoveIndices(t *testing.T) {
+	var h []int
+	for i := range 50 {
+		Push(&h, i)
+	}
+	orig := slices.Clone(h)
+	indices := rand.Perm(50)[:20]
+
+	got := RemoveIndices(&h, indices)
+	verify(t, h)
+	if len(h) != 30 {
+		t.Fatalf("len = %d; want 30", len(h))
+	}
+	for j, i := range indices {
+		if got[j] != orig[i] {
+			t.Errorf("removed[%d] = %d; want %d", j, got[j], orig[i])
+		}
+	}
+	all := slices.Concat(h, got)
+	slices.Sort(all)
+	slices.Sort(orig)
+	if !slices.Equal(all, orig) {
+		t.Errorf("remaining and removed elements %v; want %v", all, orig)
+	}
+	if got := RemoveIndices(&h, nil); got != nil || len(h) != 30 {
+		t.Errorf("RemoveIndices(nil) = %v", got)
+	}
+}
+
+func TestRemoveIndicesPanics(t *testing.T) {
+	for _, tt := range []struct {
+		indices []int
+		want    string
+	}{
+		{[]int{1, 5}, "sliceheap: RemoveIndices index 5 out of range [0,3)"},
+		{[]int{1, 0, 1}, "sliceheap: RemoveIndices index 1 repeated"},
+	} {
+		func() {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("RemoveIndices(%v) panicked with %v; want %q", tt.indices, got, tt.want)
+				}
+			}()
+			h := []int{1, 2, 3}
+			RemoveIndices(&h, tt.indices)
+		}()
+	}
+}
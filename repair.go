@@ -0,0 +1,109 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Repair restores the heap invariants after some elements of h have been
+// changed in place, without knowing which. It finds every parent that
+// orders after one of its children and re-sifts only those parents and
+// their ancestors, bottom up, leaving intact subtrees alone. It returns the
+// number of swaps made, which is zero if h was already a heap.
+//
+// Repair makes one comparison per element to find the violations, fewer
+// than [Init], and moves only elements on the paths to them; when few
+// elements changed in a large heap, that is much less work than a rebuild.
+// The complexity is O(n + k log² n) where n = len(h) and k is the number of
+// violations.
+func Repair[T cmp.Ordered](h []T) int {
+	return RepairFunc(h, cmp.Less[T])
+}
+
+// RepairFunc is like [Repair] but uses a less function to compare elements.
+func RepairFunc[T any](h []T, less func(x, y T) bool) int {
+	var dirty []bool // internal nodes to re-sift, allocated on the first violation
+	for c := len(h) - 1; c > 0; c-- {
+		p := (c - 1) / 2
+		if !less(h[c], h[p]) {
+			continue
+		}
+		if dirty == nil {
+			dirty = make([]bool, len(h)/2)
+		}
+		markAncestors(dirty, p)
+	}
+	return resift(h, dirty, less)
+}
+
+// FixRange restores the heap invariants after the elements h[lo:hi] have
+// been changed in place, for example re-scored in one pass. Only the range
+// and its ancestors are re-sifted, bottom up; the rest of the heap is left
+// alone. FixRange(h, i, i+1) is equivalent to, though slower than,
+// [Fix](h, i).
+// The complexity is O((k + log n) log n) where n = len(h) and k = hi-lo.
+func FixRange[T cmp.Ordered](h []T, lo, hi int) {
+	FixRangeFunc(h, lo, hi, cmp.Less[T])
+}
+
+// FixRangeFunc is like [FixRange] but uses a less function to compare elements.
+func FixRangeFunc[T any](h []T, lo, hi int, less func(x, y T) bool) {
+	n := len(h)
+	if lo < 0 || hi < lo || hi > n {
+		panic(fmt.Sprintf("sliceheap: FixRange range [%d:%d] out of range with length %d", lo, hi, n))
+	}
+	// The ancestors of a contiguous range at one level are contiguous at
+	// the level above, so the nodes to re-sift can be visited in
+	// decreasing order one range at a time, without marking them.
+	for lo < hi {
+		for i := min(hi, n/2) - 1; i >= lo; i-- {
+			down(h, i, n, less)
+		}
+		if lo == 0 {
+			break
+		}
+		lo, hi = (lo-1)/2, min((hi-2)/2+1, lo)
+	}
+	if debug {
+		check("FixRange", h, less)
+	}
+}
+
+// markAncestors marks node i and its ancestors as dirty.
+func markAncestors(dirty []bool, i int) {
+	for !dirty[i] {
+		dirty[i] = true
+		if i == 0 {
+			break
+		}
+		i = (i - 1) / 2
+	}
+}
+
+// resift re-sifts the dirty nodes of h bottom up, returning the number of
+// swaps made. As in Init, the subtrees of each node are heaps by the time
+// it is sifted: clean subtrees were never broken, and dirty ones have been
+// sifted already. The dirty set must be closed under taking ancestors.
+func resift[T any](h []T, dirty []bool, less func(x, y T) bool) int {
+	swaps := 0
+	s := SwapperHeap{
+		Less: func(i, j int) bool { return less(h[i], h[j]) },
+		Swap: func(i, j int) {
+			h[i], h[j] = h[j], h[i]
+			swaps++
+		},
+	}
+	for i := len(dirty) - 1; i >= 0; i-- {
+		if dirty[i] {
+			s.down(i, len(h))
+		}
+	}
+	if debug {
+		check("Repair", h, less)
+	}
+	return swaps
+}
@@ -0,0 +1,89 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 10, 100, 1000} {
+		for _, k := range []int{0, 1, 3, n / 4, n} {
+			h := rand.Perm(n)
+			Init(h)
+			for range min(k, n) {
+				h[rand.Intn(n)] = rand.Intn(2*n) - n/2
+			}
+			want := slices.Sorted(slices.Values(h))
+			swaps := Repair(h)
+			verify(t, h)
+			if k == 0 && swaps != 0 {
+				t.Errorf("n=%d: Repair of a valid heap made %d swaps", n, swaps)
+			}
+			if got := slices.Sorted(slices.Values(h)); !slices.Equal(got, want) {
+				t.Fatalf("n=%d, k=%d: Repair changed the elements", n, k)
+			}
+		}
+	}
+}
+
+func TestFixRange(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100, 1000} {
+		for lo := 0; lo <= n; lo += 1 + n/7 {
+			for hi := lo; hi <= n; hi += 1 + n/5 {
+				h := rand.Perm(n)
+				Init(h)
+				for i := lo; i < hi; i++ {
+					h[i] = rand.Intn(2 * n)
+				}
+				want := slices.Sorted(slices.Values(h))
+				FixRange(h, lo, hi)
+				verify(t, h)
+				if got := slices.Sorted(slices.Values(h)); !slices.Equal(got, want) {
+					t.Fatalf("n=%d: FixRange(%d, %d) changed the elements", n, lo, hi)
+				}
+			}
+		}
+	}
+}
+
+func TestFixRangePanics(t *testing.T) {
+	for _, r := range [][2]int{{-1, 0}, {2, 1}, {0, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FixRange(%d, %d) did not panic", r[0], r[1])
+				}
+			}()
+			FixRange([]int{1, 2, 3}, r[0], r[1])
+		}()
+	}
+}
+
+func BenchmarkRepair(b *testing.B) {
+	h := rand.Perm(1 << 16)
+	Init(h)
+	for range b.N {
+		for range 4 {
+			i := rand.Intn(len(h))
+			h[i] = rand.Intn(len(h))
+		}
+		Repair(h)
+	}
+}
+
+func BenchmarkRepairInit(b *testing.B) {
+	h := rand.Perm(1 << 16)
+	Init(h)
+	for range b.N {
+		for range 4 {
+			i := rand.Intn(len(h))
+			h[i] = rand.Intn(len(h))
+		}
+		Init(h)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package retry provides a queue of items to be retried with exponential
+// backoff.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/clock"
+)
+
+// Defaults used for the corresponding zero Queue fields.
+const (
+	DefaultBaseDelay = 100 * time.Millisecond
+	DefaultMaxDelay  = time.Minute
+)
+
+// maxBackoff caps every delay, including when MaxDelay is zero, so that
+// doubling it cannot overflow.
+const maxBackoff = time.Duration(math.MaxInt64 / 2)
+
+// An Entry is an item taken from a Queue for an attempt.
+type Entry[T any] struct {
+	Value   T
+	Attempt int // number of earlier attempts; zero for the first
+}
+
+// A Queue holds items awaiting an attempt. Items added with Add are ready at
+// once; items whose attempt failed are passed to Retry, which holds them back
+// for a jittered, exponentially growing delay. Pop and Wait return only items
+// whose next attempt is due, earliest first.
+//
+// The exported fields configure the queue and must not be changed after it
+// is first used. The zero value is a queue with default settings.
+// A Queue is safe for concurrent use by multiple goroutines.
+type Queue[T any] struct {
+	// BaseDelay is the delay before the first retry. Each later retry
+	// doubles the delay, up to MaxDelay, or up to about 146 years if
+	// MaxDelay is zero. If BaseDelay is zero, DefaultBaseDelay and DefaultMaxDelay are
+	// used.
+	BaseDelay, MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, by which each delay may be
+	// randomly shortened, so that items failing together do not retry in
+	// lockstep.
+	Jitter float64
+
+	// MaxAttempts is the number of attempts after which Retry gives up on
+	// an item instead of requeuing it. If zero, items are retried forever.
+	MaxAttempts int
+
+	// OnDeadLetter, if not nil, is called with each item Retry gives up on
+	// and the error from its last attempt.
+	OnDeadLetter func(e Entry[T], err error)
+
+	// Clock is the source of time. If nil, clock.Real is used.
+	Clock clock.Clock
+
+	mu   sync.Mutex
+	h    []item[T]
+	seq  uint64
+	wake chan struct{} // closed when an item is added, if anyone is waiting
+}
+
+type item[T any] struct {
+	at  time.Time
+	seq uint64 // tie-breaker: insertion order
+	e   Entry[T]
+}
+
+func less[T any](x, y item[T]) bool {
+	if !x.at.Equal(y.at) {
+		return x.at.Before(y.at)
+	}
+	return x.seq < y.seq
+}
+
+// Len returns the number of items in the queue, due or not.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.h)
+}
+
+// Add adds x to the queue, ready for its first attempt.
+func (q *Queue[T]) Add(x T) {
+	q.push(q.clock().Now(), Entry[T]{Value: x})
+}
+
+// Retry requeues e, whose attempt failed with err, to be retried after a
+// backoff delay. If e has reached MaxAttempts, it is passed to OnDeadLetter
+// instead and Retry reports false.
+func (q *Queue[T]) Retry(e Entry[T], err error) bool {
+	e.Attempt++
+	if q.MaxAttempts > 0 && e.Attempt >= q.MaxAttempts {
+		if q.OnDeadLetter != nil {
+			q.OnDeadLetter(e, err)
+		}
+		return false
+	}
+	q.push(q.clock().Now().Add(q.delay(e.Attempt)), e)
+	return true
+}
+
+func (q *Queue[T]) clock() clock.Clock {
+	if q.Clock == nil {
+		return clock.Real
+	}
+	return q.Clock
+}
+
+// delay returns the backoff delay before the given retry.
+func (q *Queue[T]) delay(retry int) time.Duration {
+	base, max := q.BaseDelay, q.MaxDelay
+	if base <= 0 {
+		base, max = DefaultBaseDelay, DefaultMaxDelay
+	}
+	if max <= 0 || max > maxBackoff {
+		max = maxBackoff
+	}
+	d := base
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	d = min(d, max)
+	if q.Jitter > 0 {
+		d -= time.Duration(q.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+func (q *Queue[T]) push(at time.Time, e Entry[T]) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	sliceheap.PushFunc(&q.h, item[T]{at: at, seq: q.seq, e: e}, less)
+	q.seq++
+	if q.wake != nil {
+		close(q.wake)
+		q.wake = nil
+	}
+}
+
+// Pop removes and returns the earliest item that is due at now. It reports
+// false if no item is due.
+func (q *Queue[T]) Pop(now time.Time) (Entry[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.h) == 0 || q.h[0].at.After(now) {
+		return Entry[T]{}, false
+	}
+	return sliceheap.PopFunc(&q.h, less).e, true
+}
+
+// Next returns the time at which the earliest item is due. It reports false
+// if the queue is empty.
+func (q *Queue[T]) Next() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.h) == 0 {
+		return time.Time{}, false
+	}
+	return q.h[0].at, true
+}
+
+// Wait waits until an item is due, then removes and returns it. It returns
+// ctx.Err() if ctx is done first.
+func (q *Queue[T]) Wait(ctx context.Context) (Entry[T], error) {
+	var t clock.Timer
+	defer func() {
+		if t != nil {
+			t.Stop()
+		}
+	}()
+	for {
+		q.mu.Lock()
+		now := q.clock().Now()
+		if len(q.h) > 0 && !q.h[0].at.After(now) {
+			e := sliceheap.PopFunc(&q.h, less).e
+			q.mu.Unlock()
+			return e, nil
+		}
+		if q.wake == nil {
+			q.wake = make(chan struct{})
+		}
+		wake := q.wake
+		var timer <-chan time.Time
+		if len(q.h) > 0 {
+			if t == nil {
+				t = q.clock().NewTimer(q.h[0].at.Sub(now))
+			} else {
+				t.Reset(q.h[0].at.Sub(now))
+			}
+			timer = t.C()
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-timer:
+		case <-ctx.Done():
+			return Entry[T]{}, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+)
+
+func TestDelay(t *testing.T) {
+	q := &Queue[int]{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		retry := i + 1
+		if d := q.delay(retry); d != want {
+			t.Errorf("delay(%d) = %v; want %v", retry, d, want)
+		}
+	}
+
+	q.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := q.delay(3); d < 2*time.Second || d > 4*time.Second {
+			t.Fatalf("jittered delay(3) = %v; want in [2s, 4s]", d)
+		}
+	}
+
+	// Without MaxDelay, the delay grows until it is capped, never
+	// overflowing to a negative duration.
+	q = &Queue[int]{BaseDelay: time.Second}
+	prev := time.Duration(0)
+	for retry := 1; retry <= 200; retry++ {
+		d := q.delay(retry)
+		if d < prev {
+			t.Fatalf("delay(%d) = %v; want at least %v", retry, d, prev)
+		}
+		prev = d
+	}
+}
+
+func TestQueue(t *testing.T) {
+	var dead []Entry[string]
+	q := &Queue[string]{
+		BaseDelay:    20 * time.Millisecond,
+		MaxAttempts:  2,
+		OnDeadLetter: func(e Entry[string], err error) { dead = append(dead, e) },
+	}
+	q.Add("a")
+	q.Add("b")
+
+	now := time.Now()
+	e, ok := q.Pop(now)
+	if !ok || e.Value != "a" || e.Attempt != 0 {
+		t.Fatalf("Pop() = %+v, %v; want a, attempt 0", e, ok)
+	}
+	if !q.Retry(e, errors.New("failed")) {
+		t.Fatal("Retry of first attempt gave up")
+	}
+	if e, ok := q.Pop(now); !ok || e.Value != "b" {
+		t.Fatalf("Pop() = %+v, %v; want b", e, ok)
+	}
+	if e, ok := q.Pop(now); ok {
+		t.Fatalf("Pop() = %+v before retry was due", e)
+	}
+	if next, ok := q.Next(); !ok || next.Before(now.Add(20*time.Millisecond)) {
+		t.Errorf("Next() = %v, %v; want at least 20ms from now", next, ok)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	e, err := q.Wait(ctx)
+	if err != nil || e.Value != "a" || e.Attempt != 1 {
+		t.Fatalf("Wait() = %+v, %v; want a, attempt 1", e, err)
+	}
+	if q.Retry(e, errors.New("failed again")) {
+		t.Error("Retry past MaxAttempts requeued")
+	}
+	if len(dead) != 1 || dead[0].Value != "a" || dead[0].Attempt != 2 {
+		t.Errorf("dead letters = %+v; want a after 2 attempts", dead)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %d; want 0", q.Len())
+	}
+}
+
+func TestWaitWake(t *testing.T) {
+	var q Queue[int]
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Add(7)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if e, err := q.Wait(ctx); err != nil || e.Value != 7 {
+		t.Errorf("Wait() = %+v, %v; want 7", e, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() on empty queue = %v; want DeadlineExceeded", err)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	q := &Queue[string]{BaseDelay: time.Minute, Clock: c}
+	q.Add("a")
+	e, _ := q.Pop(c.Now())
+	q.Retry(e, errors.New("failed"))
+
+	got := make(chan Entry[string])
+	go func() {
+		e, _ := q.Wait(context.Background())
+		got <- e
+	}()
+	// Wait for the waiter's timer before advancing past it.
+	for c.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Advance(time.Minute)
+	select {
+	case e := <-got:
+		if e.Value != "a" || e.Attempt != 1 {
+			t.Errorf("Wait() = %+v; want a, attempt 1", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after the clock advanced")
+	}
+}
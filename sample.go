@@ -0,0 +1,111 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// A WeightedReservoir draws a weighted random sample without replacement of
+// up to k elements from a stream of unknown length, using the
+// exponential-jumps algorithm (A-ExpJ) of Efraimidis and Spirakis. Each
+// element is kept with probability proportional to its weight, as if
+// elements were drawn one at a time without replacement.
+//
+// The reservoir is a min-heap of the k largest random keys seen. Instead of
+// drawing a key for every element, A-ExpJ draws how much weight to skip
+// before the next element enters the reservoir, so only O(k log(n/k))
+// random numbers are needed for n elements.
+type WeightedReservoir[T any] struct {
+	k    int
+	r    *rand.Rand
+	h    []keyed[T]
+	jump float64 // weight remaining to skip before the next insertion
+}
+
+type keyed[T any] struct {
+	key float64 // log of the key u^(1/w), to avoid underflow
+	v   T
+}
+
+func keyedLess[T any](x, y keyed[T]) bool {
+	return x.key < y.key
+}
+
+// NewWeightedReservoir returns a reservoir for a sample of size k drawing
+// random numbers from r, or from the global source if r is nil.
+// It panics if k < 0.
+func NewWeightedReservoir[T any](k int, r *rand.Rand) *WeightedReservoir[T] {
+	if k < 0 {
+		panic(fmt.Sprintf("sliceheap: sample size %d < 0", k))
+	}
+	return &WeightedReservoir[T]{k: k, r: r, h: make([]keyed[T], 0, k)}
+}
+
+func (s *WeightedReservoir[T]) float64() float64 {
+	if s.r != nil {
+		return s.r.Float64()
+	}
+	return rand.Float64()
+}
+
+// uniform returns a random number in (0, 1].
+func (s *WeightedReservoir[T]) uniform() float64 {
+	return 1 - s.float64()
+}
+
+// Add offers x with weight w to the reservoir. Elements with non-positive
+// weight are never sampled.
+// The complexity is O(log k) when x enters the reservoir and O(1) otherwise.
+func (s *WeightedReservoir[T]) Add(x T, w float64) {
+	if !(w > 0) || s.k == 0 {
+		return
+	}
+	if len(s.h) < s.k {
+		PushFunc(&s.h, keyed[T]{math.Log(s.uniform()) / w, x}, keyedLess)
+		if len(s.h) == s.k {
+			s.newJump()
+		}
+		return
+	}
+	if s.jump -= w; s.jump > 0 {
+		return
+	}
+	// x enters the reservoir with a key drawn uniformly from those larger
+	// than the current minimum.
+	t := math.Exp(s.h[0].key * w)
+	u := t + (1-t)*s.uniform()
+	s.h[0] = keyed[T]{math.Log(u) / w, x}
+	FixFunc(s.h, 0, keyedLess)
+	s.newJump()
+}
+
+func (s *WeightedReservoir[T]) newJump() {
+	s.jump = math.Log(s.uniform()) / s.h[0].key
+}
+
+// Sample returns the elements in the reservoir, in no particular order.
+func (s *WeightedReservoir[T]) Sample() []T {
+	out := make([]T, len(s.h))
+	for i, e := range s.h {
+		out[i] = e.v
+	}
+	return out
+}
+
+// WeightedSample returns a weighted random sample without replacement of up
+// to k of items, in no particular order, drawing random numbers from r or,
+// if r is nil, from the global source.
+// The complexity is O(n + k log k log(n/k)) where n = len(items): every item
+// is visited, but only an expected O(k log(n/k)) of them enter the sample.
+func WeightedSample[T any](r *rand.Rand, k int, items []T, weight func(T) float64) []T {
+	s := NewWeightedReservoir[T](k, r)
+	for _, x := range items {
+		s.Add(x, weight(x))
+	}
+	return s.Sample()
+}
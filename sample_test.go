@@ -0,0 +1,77 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestWeightedSample(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+	weight := func(x int) float64 { return float64(x) } // item 0 is never chosen
+	r := rand.New(rand.NewPCG(1, 2))
+
+	const trials = 20000
+	counts := make([]int, len(items))
+	for i := 0; i < trials; i++ {
+		s := WeightedSample(r, 1, items, weight)
+		if len(s) != 1 {
+			t.Fatalf("sample of size %d; want 1", len(s))
+		}
+		counts[s[0]]++
+	}
+	if counts[0] != 0 {
+		t.Errorf("zero-weight item sampled %d times", counts[0])
+	}
+	// With k = 1, each item is chosen with probability w / sum(w).
+	for x := 1; x < len(items); x++ {
+		want := float64(x) / 10 * trials
+		if math.Abs(float64(counts[x])-want) > 0.05*trials {
+			t.Errorf("item %d chosen %d times; want about %.0f", x, counts[x], want)
+		}
+	}
+}
+
+func TestWeightedSampleDistinct(t *testing.T) {
+	var items []int
+	for i := 0; i < 1000; i++ {
+		items = append(items, i)
+	}
+	r := rand.New(rand.NewPCG(3, 4))
+	s := WeightedSample(r, 50, items, func(x int) float64 { return 1 + float64(x%7) })
+	if len(s) != 50 {
+		t.Fatalf("sample of size %d; want 50", len(s))
+	}
+	slices.Sort(s)
+	if len(slices.Compact(s)) != 50 {
+		t.Error("sample contains duplicates")
+	}
+
+	if s := WeightedSample(r, 10, items[:3], func(int) float64 { return 1 }); len(s) != 3 {
+		t.Errorf("sample of 3 items has size %d; want 3", len(s))
+	}
+}
+
+func TestWeightedReservoirHeavy(t *testing.T) {
+	// A single very heavy item late in the stream is almost always kept.
+	r := rand.New(rand.NewPCG(5, 6))
+	kept := 0
+	for trial := 0; trial < 200; trial++ {
+		s := NewWeightedReservoir[int](5, r)
+		for i := 0; i < 1000; i++ {
+			s.Add(i, 1)
+		}
+		s.Add(-1, 1e6)
+		if slices.Contains(s.Sample(), -1) {
+			kept++
+		}
+	}
+	if kept < 195 {
+		t.Errorf("heavy item kept in %d of 200 trials", kept)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scheduler runs jobs on a fixed pool of worker goroutines in
+// priority order.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/clock"
+)
+
+// ErrStopped is returned by Submit after Stop or Drain has been called, and
+// is passed to Hooks.OnDone for queued jobs discarded by Stop.
+var ErrStopped = errors.New("scheduler: stopped")
+
+// A PanicError is the error reported for a job that panicked.
+type PanicError struct {
+	Value any    // the value passed to panic
+	Stack []byte // the stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("scheduler: job panicked: %v", e.Value)
+}
+
+// JobInfo describes a job to the hooks.
+type JobInfo struct {
+	Priority int
+	Enqueued time.Time
+}
+
+// Hooks are optional callbacks for observing the scheduler, for example to
+// collect metrics. They are called synchronously from the goroutine
+// submitting or running the job, and must be safe for concurrent use.
+type Hooks struct {
+	// OnEnqueue is called when a job is queued.
+	OnEnqueue func(info JobInfo)
+
+	// OnStart is called when a worker takes a job from the queue.
+	OnStart func(info JobInfo)
+
+	// OnDone is called when a job has finished, with the error it returned
+	// or a *PanicError if it panicked. It is also called, with the
+	// context's error, for a job whose context was done before it started,
+	// and with ErrStopped for a job discarded by Stop.
+	OnDone func(info JobInfo, err error, elapsed time.Duration)
+}
+
+// A Scheduler dispatches submitted jobs to its workers strictly in priority
+// order: whenever a worker becomes free it runs the queued job with the
+// highest priority, and jobs of equal priority run in the order they were
+// submitted.
+type Scheduler struct {
+	// Clock is the source of time for JobInfo.Enqueued and the elapsed
+	// times passed to Hooks.OnDone. If nil, clock.Real is used. It must
+	// not be changed after the first call to Submit.
+	Clock clock.Clock
+
+	hooks  Hooks
+	ctx    context.Context // canceled by Stop
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	cond    sync.Cond
+	q       sliceheap.PriorityQueue[Job]
+	seq     uint64
+	closing bool
+}
+
+// A Job is a submitted job waiting in a scheduler's queue.
+type Job struct {
+	info JobInfo
+	seq  uint64
+	ctx  context.Context
+	f    func(context.Context) error
+}
+
+// Info returns the description of the job passed to the hooks.
+func (j Job) Info() JobInfo {
+	return j.info
+}
+
+// Less reports whether job x runs before job y: x has the higher
+// priority, or the same priority and was submitted first. It is the
+// ordering a queue passed to [NewWithQueue] must follow.
+func Less(x, y Job) bool {
+	if x.info.Priority != y.info.Priority {
+		return x.info.Priority > y.info.Priority
+	}
+	return x.seq < y.seq
+}
+
+// heapQueue is the queue used by New: the sliceheap functions on a slice.
+type heapQueue []Job
+
+func (q *heapQueue) Len() int   { return len(*q) }
+func (q *heapQueue) Push(j Job) { sliceheap.PushFunc((*[]Job)(q), j, Less) }
+func (q *heapQueue) Pop() Job   { return sliceheap.PopFunc((*[]Job)(q), Less) }
+func (q *heapQueue) Peek() Job  { return (*q)[0] }
+
+// New returns a scheduler running the given number of workers.
+// It panics if workers < 1.
+func New(workers int, hooks Hooks) *Scheduler {
+	return NewWithQueue(workers, hooks, new(heapQueue))
+}
+
+// NewWithQueue is like [New] but queues jobs in q, which must be empty and
+// must pop jobs in the order given by [Less]. The scheduler serializes its
+// calls to q, and q must not be used by other goroutines. A q whose Push
+// can panic, such as a full fixed-capacity heap, makes Submit panic.
+// It panics if workers < 1.
+func NewWithQueue(workers int, hooks Hooks, q sliceheap.PriorityQueue[Job]) *Scheduler {
+	if workers < 1 {
+		panic(fmt.Sprintf("scheduler: %d workers < 1", workers))
+	}
+	s := &Scheduler{hooks: hooks, q: q}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.cond.L = &s.mu
+	s.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// Submit queues f to be run with the given priority. Higher priorities run
+// first. The job is passed a context derived from ctx that is also canceled
+// if the scheduler is stopped while the job runs. If ctx is done before the
+// job starts, the job is skipped.
+//
+// Hooks.OnEnqueue is called before the job is queued, so it precedes the
+// job's OnStart. If the scheduler is stopped in between, the job is
+// reported to OnDone with ErrStopped and Submit returns ErrStopped.
+func (s *Scheduler) Submit(ctx context.Context, priority int, f func(context.Context) error) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return ErrStopped
+	}
+	j := Job{info: JobInfo{Priority: priority, Enqueued: s.clock().Now()}, seq: s.seq, ctx: ctx, f: f}
+	s.seq++
+	s.mu.Unlock()
+
+	if s.hooks.OnEnqueue != nil {
+		s.hooks.OnEnqueue(j.info)
+	}
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		if s.hooks.OnDone != nil {
+			s.hooks.OnDone(j.info, ErrStopped, 0)
+		}
+		return ErrStopped
+	}
+	s.q.Push(j)
+	s.mu.Unlock()
+	s.cond.Signal()
+	return nil
+}
+
+// Len returns the number of queued jobs that have not yet started.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.q.Len()
+}
+
+func (s *Scheduler) worker() {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		for s.q.Len() == 0 && !s.closing {
+			s.cond.Wait()
+		}
+		if s.q.Len() == 0 {
+			s.mu.Unlock()
+			return
+		}
+		j := s.q.Pop()
+		s.mu.Unlock()
+		s.run(j)
+	}
+}
+
+func (s *Scheduler) clock() clock.Clock {
+	if s.Clock == nil {
+		return clock.Real
+	}
+	return s.Clock
+}
+
+func (s *Scheduler) run(j Job) {
+	if s.hooks.OnStart != nil {
+		s.hooks.OnStart(j.info)
+	}
+	clk := s.clock()
+	start := clk.Now()
+	err := j.ctx.Err()
+	if err == nil {
+		err = s.call(j)
+	}
+	if s.hooks.OnDone != nil {
+		s.hooks.OnDone(j.info, err, clk.Now().Sub(start))
+	}
+}
+
+func (s *Scheduler) call(j Job) (err error) {
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return j.f(ctx)
+}
+
+// Drain stops the scheduler from accepting new jobs, waits for all queued
+// jobs to run, and then stops the workers.
+func (s *Scheduler) Drain() {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	s.cond.Broadcast()
+	s.wg.Wait()
+	s.cancel()
+}
+
+// Stop stops the scheduler from accepting new jobs, discards queued jobs,
+// cancels the contexts of running jobs, and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.closing = true
+	dropped := slices.Collect(sliceheap.Drain(s.q))
+	s.mu.Unlock()
+	s.cond.Broadcast()
+	s.cancel()
+
+	if s.hooks.OnDone != nil {
+		for _, j := range dropped {
+			s.hooks.OnDone(j.info, ErrStopped, 0)
+		}
+	}
+	s.wg.Wait()
+}
@@ -0,0 +1,209 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/clock"
+)
+
+// block submits a job that occupies a worker until the returned function is
+// called.
+func block(t *testing.T, s *Scheduler) func() {
+	t.Helper()
+	started, release := make(chan struct{}), make(chan struct{})
+	err := s.Submit(context.Background(), 1<<30, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	return func() { close(release) }
+}
+
+func TestPriorityOrder(t *testing.T) {
+	s := New(1, Hooks{})
+	release := block(t, s)
+
+	var mu sync.Mutex
+	var order []int
+	for _, p := range []int{1, 5, 3, 5, 0, 9} {
+		s.Submit(context.Background(), p, func(context.Context) error {
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+			return nil
+		})
+	}
+	if s.Len() != 6 {
+		t.Errorf("Len() = %d; want 6", s.Len())
+	}
+	release()
+	s.Drain()
+
+	if want := []int{9, 5, 5, 3, 1, 0}; !slices.Equal(order, want) {
+		t.Errorf("ran in order %v; want %v", order, want)
+	}
+	if err := s.Submit(context.Background(), 0, func(context.Context) error { return nil }); err != ErrStopped {
+		t.Errorf("Submit after Drain = %v; want ErrStopped", err)
+	}
+}
+
+func TestNewWithQueue(t *testing.T) {
+	var buf []Job
+	q := sliceheap.NewCounterFunc(&buf, Less)
+	s := NewWithQueue(1, Hooks{}, q)
+	release := block(t, s)
+
+	var order []int
+	for _, p := range []int{2, 7, 4} {
+		s.Submit(context.Background(), p, func(context.Context) error {
+			order = append(order, p)
+			return nil
+		})
+	}
+	if q.Len() != 3 || q.Peek().Info().Priority != 7 {
+		t.Errorf("queue holds %d jobs; want 3 headed by priority 7", q.Len())
+	}
+	release()
+	s.Drain()
+
+	if want := []int{7, 4, 2}; !slices.Equal(order, want) {
+		t.Errorf("ran in order %v; want %v", order, want)
+	}
+	if q.Total.Comparisons == 0 {
+		t.Error("scheduler did not use the queue")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var mu sync.Mutex
+	var enqueued, started int
+	var errs []error
+	s := New(2, Hooks{
+		OnEnqueue: func(JobInfo) { mu.Lock(); enqueued++; mu.Unlock() },
+		OnStart:   func(JobInfo) { mu.Lock(); started++; mu.Unlock() },
+		OnDone: func(_ JobInfo, err error, _ time.Duration) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+	})
+
+	boom := errors.New("boom")
+	s.Submit(context.Background(), 0, func(context.Context) error { return boom })
+	s.Submit(context.Background(), 0, func(context.Context) error { panic("oops") })
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Submit(canceled, 0, func(context.Context) error {
+		t.Error("job with canceled context ran")
+		return nil
+	})
+	s.Drain()
+
+	if enqueued != 3 || started != 3 || len(errs) != 3 {
+		t.Fatalf("enqueued, started, done = %d, %d, %d; want 3 each", enqueued, started, len(errs))
+	}
+	var sawBoom, sawPanic, sawCanceled bool
+	for _, err := range errs {
+		var pe *PanicError
+		switch {
+		case err == boom:
+			sawBoom = true
+		case errors.As(err, &pe):
+			sawPanic = pe.Value == "oops" && len(pe.Stack) > 0
+		case err == context.Canceled:
+			sawCanceled = true
+		}
+	}
+	if !sawBoom || !sawPanic || !sawCanceled {
+		t.Errorf("OnDone errors = %v; want boom, a PanicError, and context.Canceled", errs)
+	}
+}
+
+func TestHookOrder(t *testing.T) {
+	// Each job's OnEnqueue precedes its OnStart, however quickly a worker
+	// takes it.
+	var mu sync.Mutex
+	enqueued := map[int]bool{}
+	s := New(4, Hooks{
+		OnEnqueue: func(info JobInfo) { mu.Lock(); enqueued[info.Priority] = true; mu.Unlock() },
+		OnStart: func(info JobInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !enqueued[info.Priority] {
+				t.Errorf("job %d started before OnEnqueue", info.Priority)
+			}
+		},
+	})
+	for i := range 1000 {
+		s.Submit(context.Background(), i, func(context.Context) error { return nil })
+	}
+	s.Drain()
+}
+
+func TestStop(t *testing.T) {
+	var mu sync.Mutex
+	var dropped int
+	s := New(1, Hooks{
+		OnDone: func(_ JobInfo, err error, _ time.Duration) {
+			if err == ErrStopped {
+				mu.Lock()
+				dropped++
+				mu.Unlock()
+			}
+		},
+	})
+
+	running := make(chan struct{})
+	var jobErr error
+	s.Submit(context.Background(), 1, func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		jobErr = ctx.Err()
+		return jobErr
+	})
+	<-running
+	for i := 0; i < 3; i++ {
+		s.Submit(context.Background(), 0, func(context.Context) error {
+			t.Error("queued job ran after Stop")
+			return nil
+		})
+	}
+	s.Stop()
+
+	if jobErr != context.Canceled {
+		t.Errorf("running job saw %v; want context.Canceled", jobErr)
+	}
+	if dropped != 3 {
+		t.Errorf("%d jobs dropped; want 3", dropped)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(100, 0))
+	var got JobInfo
+	var elapsed time.Duration
+	s := New(1, Hooks{OnDone: func(info JobInfo, _ error, d time.Duration) { got, elapsed = info, d }})
+	s.Clock = clk
+	s.Submit(context.Background(), 0, func(context.Context) error {
+		clk.Advance(time.Minute)
+		return nil
+	})
+	s.Drain()
+	if !got.Enqueued.Equal(time.Unix(100, 0)) || elapsed != time.Minute {
+		t.Errorf("Enqueued, elapsed = %v, %v; want the fake clock's %v, %v", got.Enqueued, elapsed, time.Unix(100, 0), time.Minute)
+	}
+}
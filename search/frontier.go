@@ -0,0 +1,151 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Frontier holds the states discovered but not yet expanded by a search,
+// each with its score. Lower scores are better.
+type Frontier[T any, S cmp.Ordered] interface {
+	// Push adds a state.
+	Push(x T, score S)
+
+	// Pop removes and returns the state with the best score. It reports
+	// false if the frontier is empty.
+	Pop() (x T, score S, ok bool)
+
+	// Len returns the number of states in the frontier.
+	Len() int
+}
+
+type scored[T any, S cmp.Ordered] struct {
+	x     T
+	score S
+	seq   uint64 // tie-breaker: insertion order
+}
+
+func better[T any, S cmp.Ordered](a, b scored[T, S]) bool {
+	if a.score != b.score {
+		return a.score < b.score
+	}
+	return a.seq < b.seq
+}
+
+// A Heap is an unbounded frontier. States of equal score are popped in the
+// order they were pushed. The zero value is an empty frontier.
+type Heap[T any, S cmp.Ordered] struct {
+	h   []scored[T, S]
+	seq uint64
+}
+
+// Push adds a state.
+// The complexity is O(log n) where n = f.Len().
+func (f *Heap[T, S]) Push(x T, score S) {
+	sliceheap.PushFunc(&f.h, scored[T, S]{x, score, f.seq}, better)
+	f.seq++
+}
+
+// Pop removes and returns the state with the best score.
+// The complexity is O(log n) where n = f.Len().
+func (f *Heap[T, S]) Pop() (x T, score S, ok bool) {
+	if len(f.h) == 0 {
+		return x, score, false
+	}
+	e := sliceheap.PopFunc(&f.h, better)
+	return e.x, e.score, true
+}
+
+// Len returns the number of states in the frontier.
+func (f *Heap[T, S]) Len() int {
+	return len(f.h)
+}
+
+// A Bounded frontier holds at most a fixed number of states. Pushing a state
+// onto a full frontier evicts the worst state, which may be the one pushed.
+// It keeps its states in both a min-heap and a max-heap, so that both the
+// best and the worst can be removed in logarithmic time.
+type Bounded[T any, S cmp.Ordered] struct {
+	capacity int
+	best     []*boundedEntry[T, S]
+	worst    []*boundedEntry[T, S]
+	seq      uint64
+	evicted  int
+}
+
+type boundedEntry[T any, S cmp.Ordered] struct {
+	scored[T, S]
+	bestIndex, worstIndex int
+}
+
+func bestLess[T any, S cmp.Ordered](a, b *boundedEntry[T, S]) bool {
+	return better(a.scored, b.scored)
+}
+
+func worstLess[T any, S cmp.Ordered](a, b *boundedEntry[T, S]) bool {
+	return better(b.scored, a.scored)
+}
+
+func setBestIndex[T any, S cmp.Ordered](e *boundedEntry[T, S], i int) {
+	e.bestIndex = i
+}
+
+func setWorstIndex[T any, S cmp.Ordered](e *boundedEntry[T, S], i int) {
+	e.worstIndex = i
+}
+
+// NewBounded returns a frontier holding at most capacity states.
+// It panics if capacity < 1.
+func NewBounded[T any, S cmp.Ordered](capacity int) *Bounded[T, S] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("search: frontier capacity %d < 1", capacity))
+	}
+	return &Bounded[T, S]{capacity: capacity}
+}
+
+// Push adds a state, evicting the worst state if the frontier is full.
+// The complexity is O(log n) where n = f.Len().
+func (f *Bounded[T, S]) Push(x T, score S) {
+	e := &boundedEntry[T, S]{scored: scored[T, S]{x, score, f.seq}}
+	f.seq++
+	if len(f.best) == f.capacity {
+		if !better(e.scored, f.worst[0].scored) {
+			f.evicted++
+			return
+		}
+		w := indexheap.Pop(&f.worst, worstLess, setWorstIndex)
+		indexheap.Remove(&f.best, w.bestIndex, bestLess, setBestIndex)
+		f.evicted++
+	}
+	indexheap.Push(&f.best, e, bestLess, setBestIndex)
+	indexheap.Push(&f.worst, e, worstLess, setWorstIndex)
+}
+
+// Pop removes and returns the state with the best score.
+// The complexity is O(log n) where n = f.Len().
+func (f *Bounded[T, S]) Pop() (x T, score S, ok bool) {
+	if len(f.best) == 0 {
+		return x, score, false
+	}
+	e := indexheap.Pop(&f.best, bestLess, setBestIndex)
+	indexheap.Remove(&f.worst, e.worstIndex, worstLess, setWorstIndex)
+	return e.x, e.score, true
+}
+
+// Len returns the number of states in the frontier.
+func (f *Bounded[T, S]) Len() int {
+	return len(f.best)
+}
+
+// Evicted returns the number of states discarded because the frontier was
+// full.
+func (f *Bounded[T, S]) Evicted() int {
+	return f.evicted
+}
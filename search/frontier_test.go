@@ -0,0 +1,66 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func drain(f Frontier[int, int]) []int {
+	var out []int
+	for {
+		x, _, ok := f.Pop()
+		if !ok {
+			return out
+		}
+		out = append(out, x)
+	}
+}
+
+func TestHeap(t *testing.T) {
+	var f Heap[int, int]
+	for _, x := range []int{5, 1, 4, 1, 3} {
+		f.Push(x, x)
+	}
+	if f.Len() != 5 {
+		t.Errorf("Len() = %d; want 5", f.Len())
+	}
+	if got, want := drain(&f), []int{1, 1, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+}
+
+func TestBounded(t *testing.T) {
+	f := NewBounded[int, int](10)
+	var model []int // the best states, sorted
+	evicted := 0
+	for i := 0; i < 100; i++ {
+		x := rand.Intn(1000)
+		f.Push(x, x)
+		model = append(model, x)
+		slices.Sort(model)
+		if len(model) > 10 {
+			model = model[:10]
+			evicted++
+		}
+		if f.Len() != len(model) {
+			t.Fatalf("Len() = %d; want %d", f.Len(), len(model))
+		}
+		if i%7 == 0 {
+			if x, _, _ := f.Pop(); x != model[0] {
+				t.Fatalf("Pop() = %d; want %d", x, model[0])
+			}
+			model = model[1:]
+		}
+	}
+	if got := drain(f); !slices.Equal(got, model) {
+		t.Errorf("popped %v; want %v", got, model)
+	}
+	if f.Evicted() != evicted {
+		t.Errorf("Evicted() = %d; want %d", f.Evicted(), evicted)
+	}
+}
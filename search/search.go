@@ -0,0 +1,139 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package search provides heap-driven best-first search over implicit state
+// spaces.
+package search
+
+import (
+	"cmp"
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned when the frontier is exhausted without
+	// reaching a goal.
+	ErrNotFound = errors.New("search: no goal found")
+
+	// ErrBudget is returned when a search exceeds its step budget.
+	ErrBudget = errors.New("search: step budget exhausted")
+)
+
+// A Search describes a search problem over states of type T. States are
+// identified for duplicate detection by keys of type K, and scored by values
+// of type S, lower being better. Expand, Score, and Goal must be set; the
+// other fields are optional.
+type Search[T any, K comparable, S cmp.Ordered] struct {
+	// Expand returns the successors of a state.
+	Expand func(T) []T
+
+	// Score scores a state. Best-first search always expands the state
+	// with the lowest score.
+	Score func(T) S
+
+	// Goal reports whether a state is a goal.
+	Goal func(T) bool
+
+	// Key, if not nil, identifies states. A state whose key matches that
+	// of a state already expanded is not expanded again.
+	Key func(T) K
+
+	// NewFrontier, if not nil, returns the frontier to use for each
+	// search. By default an unbounded Heap is used.
+	NewFrontier func() Frontier[T, S]
+
+	// MaxSteps, if positive, limits the number of states expanded.
+	MaxSteps int
+}
+
+// BestFirst searches from start, repeatedly expanding the best state in the
+// frontier, until it reaches a goal, which it returns. States are tested
+// against Goal as they are taken from the frontier. BestFirst also returns
+// the number of states expanded.
+func (s *Search[T, K, S]) BestFirst(start T) (goal T, steps int, err error) {
+	var f Frontier[T, S]
+	if s.NewFrontier != nil {
+		f = s.NewFrontier()
+	} else {
+		f = new(Heap[T, S])
+	}
+	var seen map[K]bool
+	if s.Key != nil {
+		seen = make(map[K]bool)
+	}
+
+	f.Push(start, s.Score(start))
+	for {
+		x, _, ok := f.Pop()
+		if !ok {
+			return goal, steps, ErrNotFound
+		}
+		if s.Goal(x) {
+			return x, steps, nil
+		}
+		if seen != nil {
+			k := s.Key(x)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		if s.MaxSteps > 0 && steps >= s.MaxSteps {
+			return goal, steps, ErrBudget
+		}
+		steps++
+		for _, y := range s.Expand(x) {
+			if seen != nil && seen[s.Key(y)] {
+				continue
+			}
+			f.Push(y, s.Score(y))
+		}
+	}
+}
+
+// Beam searches from start one depth level at a time, keeping only the width
+// best states of each level, until a state in the beam is a goal, which it
+// returns. The successors of every state in a level are gathered in a
+// Bounded frontier of capacity width, so the worst are evicted as better
+// ones are found. Beam ignores NewFrontier. It also returns the number of
+// states expanded.
+// It panics if width < 1.
+func (s *Search[T, K, S]) Beam(start T, width int) (goal T, steps int, err error) {
+	var seen map[K]bool
+	if s.Key != nil {
+		seen = map[K]bool{s.Key(start): true}
+	}
+
+	level := []T{start}
+	next := NewBounded[T, S](width)
+	for len(level) > 0 {
+		for _, x := range level {
+			if s.Goal(x) {
+				return x, steps, nil
+			}
+		}
+		for _, x := range level {
+			if s.MaxSteps > 0 && steps >= s.MaxSteps {
+				return goal, steps, ErrBudget
+			}
+			steps++
+			for _, y := range s.Expand(x) {
+				if seen != nil {
+					k := s.Key(y)
+					if seen[k] {
+						continue
+					}
+					seen[k] = true
+				}
+				next.Push(y, s.Score(y))
+			}
+		}
+		level = level[:0]
+		for next.Len() > 0 {
+			x, _, _ := next.Pop()
+			level = append(level, x)
+		}
+	}
+	return goal, steps, ErrNotFound
+}
@@ -0,0 +1,116 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"testing"
+)
+
+// reach searches for a way to reach a target number from 1 using the
+// operations +1 and *2.
+func reach(target int) *Search[int, int, int] {
+	return &Search[int, int, int]{
+		Expand: func(x int) []int { return []int{x + 1, x * 2} },
+		Score: func(x int) int {
+			if x > target {
+				return 2 * (x - target)
+			}
+			return target - x
+		},
+		Goal: func(x int) bool { return x == target },
+		Key:  func(x int) int { return x },
+	}
+}
+
+func TestBestFirst(t *testing.T) {
+	s := reach(100)
+	goal, steps, err := s.BestFirst(1)
+	if err != nil || goal != 100 {
+		t.Fatalf("BestFirst(1) = %d, %v; want 100", goal, err)
+	}
+	if steps > 100 {
+		t.Errorf("BestFirst took %d steps", steps)
+	}
+
+	s.NewFrontier = func() Frontier[int, int] { return NewBounded[int, int](4) }
+	if goal, _, err := s.BestFirst(1); err != nil || goal != 100 {
+		t.Errorf("BestFirst(1) with bounded frontier = %d, %v; want 100", goal, err)
+	}
+}
+
+func TestBestFirstBudget(t *testing.T) {
+	s := reach(1000)
+	s.MaxSteps = 5
+	if _, steps, err := s.BestFirst(1); err != ErrBudget || steps != 5 {
+		t.Errorf("BestFirst = %d steps, %v; want 5, ErrBudget", steps, err)
+	}
+}
+
+func TestBestFirstNotFound(t *testing.T) {
+	// A finite state space without the goal.
+	s := &Search[int, int, int]{
+		Expand: func(x int) []int {
+			if x >= 10 {
+				return nil
+			}
+			return []int{x + 1, x + 2}
+		},
+		Score: func(x int) int { return -x },
+		Goal:  func(x int) bool { return x == 50 },
+		Key:   func(x int) int { return x },
+	}
+	if _, steps, err := s.BestFirst(0); err != ErrNotFound || steps != 12 {
+		t.Errorf("BestFirst = %d steps, %v; want 12, ErrNotFound", steps, err)
+	}
+}
+
+func TestBeam(t *testing.T) {
+	s := reach(100)
+	goal, _, err := s.Beam(1, 3)
+	if err != nil || goal != 100 {
+		t.Fatalf("Beam(1, 3) = %d, %v; want 100", goal, err)
+	}
+
+	s.MaxSteps = 4
+	if _, steps, err := s.Beam(1, 3); err != ErrBudget || steps != 4 {
+		t.Errorf("Beam with budget = %d steps, %v; want 4, ErrBudget", steps, err)
+	}
+}
+
+func TestBeamWidth(t *testing.T) {
+	// Each state branches into ten; the beam must hold exactly the best
+	// width states of each level.
+	var levels [][]int
+	depth := map[int]int{0: 0}
+	s := &Search[int, int, int]{
+		Expand: func(x int) []int {
+			d := depth[x] + 1
+			if d > 3 {
+				return nil
+			}
+			if len(levels) < d {
+				levels = append(levels, nil)
+			}
+			levels[d-1] = append(levels[d-1], x)
+			var out []int
+			for i := 0; i < 10; i++ {
+				y := x*10 + i + 1
+				depth[y] = d
+				out = append(out, y)
+			}
+			return out
+		},
+		Score: func(x int) int { return -x },
+		Goal:  func(int) bool { return false },
+	}
+	if _, steps, err := s.Beam(0, 2); err != ErrNotFound || steps != 7 {
+		t.Fatalf("Beam = %d steps, %v; want 7, ErrNotFound", steps, err)
+	}
+	for d, l := range levels[1:] {
+		if len(l) != 2 {
+			t.Errorf("level %d expanded %v; want 2 states", d+1, l)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Select returns the k-th smallest element of the heap, counting from zero,
+// so that Select(h, 0) is the minimum. It walks the heap in ascending order
+// using a second heap of at most k+1 candidate indices, and does not modify
+// h. Elements beyond the k+1 smallest, and their subtrees, are never
+// visited, so the cost is independent of len(h).
+// Select panics if k is out of range.
+// The complexity is O(k log k).
+func Select[T cmp.Ordered](h []T, k int) T {
+	return SelectFunc(h, k, cmp.Less[T])
+}
+
+// SelectFunc is like [Select] but uses a less function to compare elements.
+func SelectFunc[T any](h []T, k int, less func(x, y T) bool) T {
+	if uint(k) >= uint(len(h)) {
+		panic(fmt.Sprintf("sliceheap: Select rank %d out of range [0,%d)", k, len(h)))
+	}
+	i := 0
+	for x := range ascend(h, less) {
+		if i == k {
+			return x
+		}
+		i++
+	}
+	panic("unreachable")
+}
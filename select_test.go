@@ -0,0 +1,42 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	var h []int
+	for range 300 {
+		Push(&h, rand.Intn(100))
+	}
+	orig := slices.Clone(h)
+	sorted := slices.Sorted(slices.Values(h))
+	for k := range sorted {
+		if got := Select(h, k); got != sorted[k] {
+			t.Fatalf("Select(h, %d) = %d; want %d", k, got, sorted[k])
+		}
+	}
+	if !slices.Equal(h, orig) {
+		t.Error("Select modified the heap")
+	}
+
+	greater := func(x, y int) bool { return x > y }
+	m := []int{1, 9, 4, 7}
+	InitFunc(m, greater)
+	if got := SelectFunc(m, 1, greater); got != 7 {
+		t.Errorf("SelectFunc(max-heap, 1) = %d; want 7", got)
+	}
+
+	defer func() {
+		if got, want := recover(), "sliceheap: Select rank 4 out of range [0,4)"; got != want {
+			t.Errorf("Select out of range panicked with %v; want %q", got, want)
+		}
+	}()
+	SelectFunc(m, 4, greater)
+}
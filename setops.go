@@ -0,0 +1,108 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Diff returns a new heap holding the elements of heap a that are not in
+// heap b, with multiset semantics: an element occurring m times in a and k
+// times in b occurs max(m-k, 0) times in the result. Elements are the same
+// if neither is less than the other. Neither a nor b is modified; they need
+// not satisfy the heap invariants. The result is sorted, which is a valid
+// heap.
+// The complexity is O(n log n + m log m) where n = len(a) and m = len(b).
+func Diff[T cmp.Ordered](a, b []T) []T {
+	return DiffFunc(a, b, cmp.Less[T])
+}
+
+// DiffFunc is like [Diff] but uses a less function to compare elements.
+func DiffFunc[T any](a, b []T, less func(x, y T) bool) []T {
+	return multiset(a, b, less, true, false, false)
+}
+
+// Union returns a new heap holding the elements of heaps a and b, with
+// multiset semantics: an element occurring m times in a and k times in b
+// occurs max(m, k) times in the result, taken from a before b. To keep
+// every element of both, use [MergeSorted] or append one heap to the other
+// and call [Init]. Neither a nor b is modified, and the result is sorted.
+// The complexity is O(n log n + m log m) where n = len(a) and m = len(b).
+func Union[T cmp.Ordered](a, b []T) []T {
+	return UnionFunc(a, b, cmp.Less[T])
+}
+
+// UnionFunc is like [Union] but uses a less function to compare elements.
+func UnionFunc[T any](a, b []T, less func(x, y T) bool) []T {
+	return multiset(a, b, less, true, true, true)
+}
+
+// Intersect returns a new heap holding the elements common to heaps a and
+// b, with multiset semantics: an element occurring m times in a and k times
+// in b occurs min(m, k) times in the result, taken from a. Neither a nor b
+// is modified, and the result is sorted.
+// The complexity is O(n log n + m log m) where n = len(a) and m = len(b).
+func Intersect[T cmp.Ordered](a, b []T) []T {
+	return IntersectFunc(a, b, cmp.Less[T])
+}
+
+// IntersectFunc is like [Intersect] but uses a less function to compare elements.
+func IntersectFunc[T any](a, b []T, less func(x, y T) bool) []T {
+	return multiset(a, b, less, false, true, false)
+}
+
+// multiset walks the sorted elements of a and b in step, pairing equal
+// elements, and returns in ascending order the elements of a that are
+// unpaired if onlyA is set, those of a that are paired if both is set, and
+// those of b that are unpaired if onlyB is set.
+func multiset[T any](a, b []T, less func(x, y T) bool, onlyA, both, onlyB bool) []T {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.SortFunc(a, compareFunc(less))
+	slices.SortFunc(b, compareFunc(less))
+	var r []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case less(a[i], b[j]):
+			if onlyA {
+				r = append(r, a[i])
+			}
+			i++
+		case less(b[j], a[i]):
+			if onlyB {
+				r = append(r, b[j])
+			}
+			j++
+		default:
+			if both {
+				r = append(r, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if onlyA {
+		r = append(r, a[i:]...)
+	}
+	if onlyB {
+		r = append(r, b[j:]...)
+	}
+	return r
+}
+
+// compareFunc returns a comparison function, as used by [slices.SortFunc],
+// for the ordering defined by less.
+func compareFunc[T any](less func(x, y T) bool) func(x, y T) int {
+	return func(x, y T) int {
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		}
+		return 0
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := []int{1, 2, 2, 2, 3, 5, 8}
+	b := []int{2, 8, 4, 2, 9}
+	Init(a)
+	Init(b)
+	ca, cb := slices.Clone(a), slices.Clone(b)
+
+	got := Diff(a, b)
+	if want := []int{1, 2, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("Diff = %v; want %v", got, want)
+	}
+	verify(t, got)
+	if !slices.Equal(a, ca) || !slices.Equal(b, cb) {
+		t.Error("Diff modified its arguments")
+	}
+	if got := Diff(b, a); !slices.Equal(got, []int{4, 9}) {
+		t.Errorf("Diff(b, a) = %v; want [4 9]", got)
+	}
+	if got := Diff(a, nil); !slices.Equal(got, []int{1, 2, 2, 2, 3, 5, 8}) {
+		t.Errorf("Diff(a, nil) = %v", got)
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	// Jobs are the same if their IDs are equal.
+	type job struct {
+		id    int
+		state string
+	}
+	byID := func(x, y job) bool { return x.id < y.id }
+	desired := []job{{1, "want"}, {2, "want"}, {3, "want"}}
+	inFlight := []job{{2, "running"}}
+	got := DiffFunc(desired, inFlight, byID)
+	if want := []job{{1, "want"}, {3, "want"}}; !slices.Equal(got, want) {
+		t.Errorf("DiffFunc = %v; want %v", got, want)
+	}
+}
+
+func TestUnionIntersect(t *testing.T) {
+	a := []int{5, 1, 2, 2, 3}
+	b := []int{2, 4, 2, 2, 1}
+	Init(a)
+	Init(b)
+
+	u := Union(a, b)
+	if want := []int{1, 2, 2, 2, 3, 4, 5}; !slices.Equal(u, want) {
+		t.Errorf("Union = %v; want %v", u, want)
+	}
+	verify(t, u)
+	in := Intersect(a, b)
+	if want := []int{1, 2, 2}; !slices.Equal(in, want) {
+		t.Errorf("Intersect = %v; want %v", in, want)
+	}
+	verify(t, in)
+
+	// |A ∪ B| + |A ∩ B| = |A| + |B| for multisets.
+	if len(u)+len(in) != len(a)+len(b) {
+		t.Errorf("len(Union) + len(Intersect) = %d; want %d", len(u)+len(in), len(a)+len(b))
+	}
+	if got := Intersect(a, nil); len(got) != 0 {
+		t.Errorf("Intersect(a, nil) = %v", got)
+	}
+
+	type tagged struct {
+		k   int
+		src string
+	}
+	byK := func(x, y tagged) bool { return x.k < y.k }
+	got := UnionFunc([]tagged{{1, "a"}}, []tagged{{1, "b"}, {2, "b"}}, byK)
+	if want := []tagged{{1, "a"}, {2, "b"}}; !slices.Equal(got, want) {
+		t.Errorf("UnionFunc = %v; want %v", got, want)
+	}
+	got = IntersectFunc([]tagged{{1, "a"}}, []tagged{{1, "b"}, {2, "b"}}, byK)
+	if want := []tagged{{1, "a"}}; !slices.Equal(got, want) {
+		t.Errorf("IntersectFunc = %v; want %v", got, want)
+	}
+}
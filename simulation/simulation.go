@@ -0,0 +1,135 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package simulation provides a scheduler for discrete-event simulations.
+//
+// A Scheduler holds events keyed by virtual time, measured as a
+// time.Duration since the start of the simulation. Running the scheduler
+// executes events in time order, advancing the virtual clock to each event's
+// time as it runs. Events scheduled for the same time run in the order in
+// which they were scheduled, so a simulation that schedules its events
+// deterministically runs deterministically.
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/buth/sliceheap"
+)
+
+// A Scheduler is a queue of events ordered by virtual time.
+// The zero value is an empty scheduler at time zero.
+// A Scheduler is not safe for concurrent use; events run on the goroutine
+// that runs the scheduler and may themselves schedule further events.
+type Scheduler struct {
+	now time.Duration
+	seq uint64
+	q   []event
+}
+
+type event struct {
+	at  time.Duration
+	seq uint64 // tie-breaker: scheduling order
+	f   func()
+}
+
+func less(x, y event) bool {
+	if x.at != y.at {
+		return x.at < y.at
+	}
+	return x.seq < y.seq
+}
+
+// Now returns the current virtual time.
+func (s *Scheduler) Now() time.Duration {
+	return s.now
+}
+
+// Len returns the number of pending events.
+func (s *Scheduler) Len() int {
+	return len(s.q)
+}
+
+// Schedule schedules f to run at virtual time at.
+// It panics if at is earlier than the current time.
+func (s *Scheduler) Schedule(at time.Duration, f func()) {
+	if at < s.now {
+		panic(fmt.Sprintf("simulation: event scheduled at %v, before current time %v", at, s.now))
+	}
+	sliceheap.PushFunc(&s.q, event{at: at, seq: s.seq, f: f}, less)
+	s.seq++
+}
+
+// After schedules f to run once d has elapsed in virtual time.
+// It panics if d is negative.
+func (s *Scheduler) After(d time.Duration, f func()) {
+	s.Schedule(s.now+d, f)
+}
+
+// Run runs, in order, every event scheduled at or before the virtual time
+// until, including events scheduled by the events it runs, and then advances
+// the current time to until. It returns the number of events run. If until
+// is earlier than the current time, Run does nothing.
+func (s *Scheduler) Run(until time.Duration) int {
+	n := 0
+	for len(s.q) > 0 && s.q[0].at <= until {
+		e := sliceheap.PopFunc(&s.q, less)
+		s.now = e.at
+		e.f()
+		n++
+	}
+	if until > s.now {
+		s.now = until
+	}
+	return n
+}
+
+// RunFor is like Run but runs events for a virtual duration d from the
+// current time.
+func (s *Scheduler) RunFor(d time.Duration) int {
+	return s.Run(s.now + d)
+}
+
+// Next returns the virtual time of the next event, and reports false if no
+// event is pending.
+func (s *Scheduler) Next() (time.Duration, bool) {
+	if len(s.q) == 0 {
+		return 0, false
+	}
+	return s.q[0].at, true
+}
+
+// Step runs the next event, advancing the current time to its time, and
+// reports whether there was one.
+func (s *Scheduler) Step() bool {
+	if len(s.q) == 0 {
+		return false
+	}
+	e := sliceheap.PopFunc(&s.q, less)
+	s.now = e.at
+	e.f()
+	return true
+}
+
+// StepN runs up to n events, as by Step, and returns the number run.
+func (s *Scheduler) StepN(n int) int {
+	i := 0
+	for i < n && s.Step() {
+		i++
+	}
+	return i
+}
+
+// Jump advances the current time to that of the next event and runs every
+// event scheduled for that time, including those scheduled by the events it
+// runs. It returns the number of events run, which is zero if no event is
+// pending.
+func (s *Scheduler) Jump() int {
+	at, ok := s.Next()
+	if !ok {
+		return 0
+	}
+	return s.Run(at)
+}
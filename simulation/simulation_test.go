@@ -0,0 +1,97 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simulation
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	var s Scheduler
+	var log []string
+	record := func(name string) func() {
+		return func() { log = append(log, name+"@"+s.Now().String()) }
+	}
+
+	s.Schedule(3*time.Second, record("c"))
+	s.Schedule(1*time.Second, record("a"))
+	s.Schedule(3*time.Second, record("d")) // same time as c, scheduled later
+	s.Schedule(2*time.Second, func() {
+		record("b")()
+		s.After(time.Second, record("e")) // also at 3s, after c and d
+		s.After(5*time.Second, record("f"))
+	})
+
+	if n := s.Run(4 * time.Second); n != 5 {
+		t.Errorf("Run(4s) ran %d events; want 5", n)
+	}
+	if s.Now() != 4*time.Second {
+		t.Errorf("Now() = %v; want 4s", s.Now())
+	}
+	want := []string{"a@1s", "b@2s", "c@3s", "d@3s", "e@3s"}
+	if !slices.Equal(log, want) {
+		t.Errorf("log = %v; want %v", log, want)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", s.Len())
+	}
+
+	s.Run(10 * time.Second)
+	if got := log[len(log)-1]; got != "f@7s" {
+		t.Errorf("last event = %s; want f@7s", got)
+	}
+}
+
+func TestSchedulePast(t *testing.T) {
+	var s Scheduler
+	s.Run(time.Second)
+	defer func() {
+		if recover() == nil {
+			t.Error("Schedule in the past did not panic")
+		}
+	}()
+	s.Schedule(0, func() {})
+}
+
+func TestStepAndJump(t *testing.T) {
+	var s Scheduler
+	var log []string
+	record := func(name string) func() {
+		return func() { log = append(log, name+"@"+s.Now().String()) }
+	}
+	s.Schedule(2*time.Second, record("b"))
+	s.Schedule(1*time.Second, record("a"))
+	s.Schedule(2*time.Second, func() {
+		record("c")()
+		s.After(0, record("d")) // same time, so Jump runs it too
+	})
+	s.Schedule(5*time.Second, record("e"))
+	s.Schedule(9*time.Second, record("f"))
+
+	if next, ok := s.Next(); !ok || next != time.Second {
+		t.Errorf("Next() = %v, %v; want 1s, true", next, ok)
+	}
+	if !s.Step() || s.Now() != time.Second {
+		t.Errorf("Step() ran nothing or Now() = %v; want 1s", s.Now())
+	}
+	if n := s.Jump(); n != 3 || s.Now() != 2*time.Second {
+		t.Errorf("Jump() = %d at %v; want 3 events at 2s", n, s.Now())
+	}
+	if n := s.RunFor(time.Second); n != 0 || s.Now() != 3*time.Second {
+		t.Errorf("RunFor(1s) = %d at %v; want 0 events at 3s", n, s.Now())
+	}
+	if n := s.StepN(5); n != 2 || s.Now() != 9*time.Second {
+		t.Errorf("StepN(5) = %d at %v; want 2 events at 9s", n, s.Now())
+	}
+	if s.Step() || s.Jump() != 0 {
+		t.Error("Step or Jump ran an event with none pending")
+	}
+	want := []string{"a@1s", "b@2s", "c@2s", "d@2s", "e@5s", "f@9s"}
+	if !slices.Equal(log, want) {
+		t.Errorf("log = %v; want %v", log, want)
+	}
+}
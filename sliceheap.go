@@ -13,9 +13,21 @@
 // ordering for the Less method, so Push adds items while Pop removes the
 // highest-priority item from the queue. The Examples include such an
 // implementation; the file example_pq_test.go has the complete source.
+//
+// Pop, Remove, Fix and Init never allocate, and Push allocates only when
+// the slice must grow, which [Grow] can do in advance. The package's tests
+// enforce this, and sliceheaptest.AssertNoAllocs lets wrappers check that
+// they preserve it.
+//
+// Building with the sliceheap_debug tag makes every operation verify the heap
+// invariant and panic with a description of any violation.
 package sliceheap
 
-import "cmp"
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
 
 // Init establishes the heap invariants required by the other routines in this package.
 // Init is idempotent with respect to the heap invariants
@@ -32,6 +44,9 @@ func InitFunc[T any](h []T, less func(x, y T) bool) {
 	for i := n/2 - 1; i >= 0; i-- {
 		down(h, i, n, less)
 	}
+	if debug {
+		check("Init", h, less)
+	}
 }
 
 // Push pushes the element x onto the heap.
@@ -44,11 +59,17 @@ func Push[T cmp.Ordered](h *[]T, x T) {
 func PushFunc[T any](h *[]T, x T, less func(x, y T) bool) {
 	*h = append(*h, x)
 	up(*h, len(*h)-1, less)
+	if debug {
+		check("Push", *h, less)
+	}
 }
 
 // Pop removes and returns the minimum element (according to Less) from the heap.
 // The complexity is O(log n) where n = len(h).
 // Pop is equivalent to Remove(h, 0).
+// The vacated slot past the new length is zeroed so that the backing array
+// does not keep the element reachable.
+// Pop panics if the heap is empty.
 func Pop[T cmp.Ordered](h *[]T) T {
 	return PopFunc(h, cmp.Less)
 }
@@ -56,15 +77,79 @@ func Pop[T cmp.Ordered](h *[]T) T {
 // PopFunc is like [Pop] but uses a less function to compare elements.
 func PopFunc[T any](h *[]T, less func(x, y T) bool) T {
 	n := len(*h) - 1
+	if n < 0 {
+		panic("sliceheap: Pop on empty heap")
+	}
 	x := (*h)[0]
 	(*h)[0] = (*h)[n]
+	var zero T
+	(*h)[n] = zero
 	*h = (*h)[:n]
 	down(*h, 0, n, less)
+	if debug {
+		check("Pop", *h, less)
+	}
 	return x
 }
 
+// PopOr is like [Pop] but returns def if the heap is empty.
+func PopOr[T cmp.Ordered](h *[]T, def T) T {
+	return PopOrFunc(h, def, cmp.Less)
+}
+
+// PopOrFunc is like [PopOr] but uses a less function to compare elements.
+func PopOrFunc[T any](h *[]T, def T, less func(x, y T) bool) T {
+	if len(*h) == 0 {
+		return def
+	}
+	return PopFunc(h, less)
+}
+
+// Replace replaces the minimum element of the heap with x and returns the
+// element replaced. It is equivalent to, but less expensive than, a Pop
+// followed by a Push of x.
+// The complexity is O(log n) where n = len(h).
+// Replace panics if the heap is empty.
+func Replace[T cmp.Ordered](h []T, x T) T {
+	return ReplaceFunc(h, x, cmp.Less)
+}
+
+// ReplaceFunc is like [Replace] but uses a less function to compare elements.
+func ReplaceFunc[T any](h []T, x T, less func(x, y T) bool) T {
+	if len(h) == 0 {
+		panic("sliceheap: Replace on empty heap")
+	}
+	y := h[0]
+	h[0] = x
+	down(h, 0, len(h), less)
+	if debug {
+		check("Replace", h, less)
+	}
+	return y
+}
+
+// PeekOr returns the minimum element of the heap without removing it, or
+// def if the heap is empty.
+// The complexity is O(1).
+func PeekOr[T any](h []T, def T) T {
+	if len(h) == 0 {
+		return def
+	}
+	return h[0]
+}
+
+// Grow increases the capacity of the heap, if necessary, to guarantee space
+// for another n elements, so that n pushes can be made without
+// reallocating. The elements, and so the heap invariants, are unchanged.
+// Grow panics if n is negative.
+func Grow[T any](h *[]T, n int) {
+	*h = slices.Grow(*h, n)
+}
+
 // Remove removes and returns the element at index i from the heap.
+// As with [Pop], the vacated slot past the new length is zeroed.
 // The complexity is O(log n) where n = len(h).
+// Remove panics if i is out of range.
 func Remove[T cmp.Ordered](h *[]T, i int) T {
 	return RemoveFunc(h, i, cmp.Less)
 }
@@ -72,6 +157,9 @@ func Remove[T cmp.Ordered](h *[]T, i int) T {
 // RemoveFunc is like [Remove] but uses a less function to compare elements.
 func RemoveFunc[T any](h *[]T, i int, less func(x, y T) bool) T {
 	n := len(*h) - 1
+	if uint(i) > uint(n) {
+		panicRange("Remove", i, n+1)
+	}
 	x := (*h)[i]
 	if n != i {
 		(*h)[i] = (*h)[n]
@@ -79,7 +167,12 @@ func RemoveFunc[T any](h *[]T, i int, less func(x, y T) bool) T {
 			up(*h, i, less)
 		}
 	}
+	var zero T
+	(*h)[n] = zero
 	*h = (*h)[:n]
+	if debug {
+		check("Remove", *h, less)
+	}
 	return x
 }
 
@@ -87,15 +180,27 @@ func RemoveFunc[T any](h *[]T, i int, less func(x, y T) bool) T {
 // Changing the value of the element at index i and then calling Fix is equivalent to,
 // but less expensive than, calling Remove(h, i) followed by a Push of the new value.
 // The complexity is O(log n) where n = len(h).
+// Fix panics if i is out of range.
 func Fix[T cmp.Ordered](h []T, i int) {
 	FixFunc(h, i, cmp.Less)
 }
 
 // FixFunc is like [Fix] but uses a less function to compare elements.
 func FixFunc[T any](h []T, i int, less func(x, y T) bool) {
+	if uint(i) >= uint(len(h)) {
+		panicRange("Fix", i, len(h))
+	}
 	if !down(h, i, len(h), less) {
 		up(h, i, less)
 	}
+	if debug {
+		check("Fix", h, less)
+	}
+}
+
+// panicRange panics for an index i out of range in a heap of length n.
+func panicRange(op string, i, n int) {
+	panic(fmt.Sprintf("sliceheap: %s index %d out of range [0,%d)", op, i, n))
 }
 
 func up[T any](h []T, j int, less func(x, y T) bool) {
@@ -10,24 +10,10 @@ import (
 	"testing"
 )
 
-func verify[T cmp.Ordered](h []T, t *testing.T, i int) {
+func verify[T cmp.Ordered](t *testing.T, h []T) {
 	t.Helper()
-	n := len(h)
-	j1 := 2*i + 1
-	j2 := 2*i + 2
-	if j1 < n {
-		if cmp.Less(h[j1], h[i]) {
-			t.Errorf("heap invariant invalidated [%d] = %v > [%d] = %v", i, h[i], j1, h[j1])
-			return
-		}
-		verify(h, t, j1)
-	}
-	if j2 < n {
-		if cmp.Less(h[j2], h[i]) {
-			t.Errorf("heap invariant invalidated [%d] = %v > [%d] = %v", i, h[i], j1, h[j2])
-			return
-		}
-		verify(h, t, j2)
+	if err := Verify(h); err != nil {
+		t.Error(err)
 	}
 }
 
@@ -37,11 +23,11 @@ func TestInit0(t *testing.T) {
 		h = append(h, 0) // all elements are the same
 	}
 	Init(h)
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 1; len(h) > 0; i++ {
 		x := Pop(&h)
-		verify(h, t, 0)
+		verify(t, h)
 		if x != 0 {
 			t.Errorf("%d.th pop got %d; want %d", i, x, 0)
 		}
@@ -54,11 +40,11 @@ func TestInit1(t *testing.T) {
 		h = append(h, i) // all elements are different
 	}
 	Init(h)
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 1; len(h) > 0; i++ {
 		x := Pop(&h)
-		verify(h, t, 0)
+		verify(t, h)
 		if x != i {
 			t.Errorf("%d.th pop got %d; want %d", i, x, i)
 		}
@@ -67,17 +53,17 @@ func TestInit1(t *testing.T) {
 
 func Test(t *testing.T) {
 	h := []int{}
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 20; i > 10; i-- {
 		h = append(h, i)
 	}
 	Init(h)
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 10; i > 0; i-- {
 		Push(&h, i)
-		verify(h, t, 0)
+		verify(t, h)
 	}
 
 	for i := 1; len(h) > 0; i++ {
@@ -85,7 +71,7 @@ func Test(t *testing.T) {
 		if i < 20 {
 			Push(&h, 20+i)
 		}
-		verify(h, t, 0)
+		verify(t, h)
 		if x != i {
 			t.Errorf("%d.th pop got %d; want %d", i, x, i)
 		}
@@ -97,7 +83,7 @@ func TestRemove0(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		h = append(h, i)
 	}
-	verify(h, t, 0)
+	verify(t, h)
 
 	for len(h) > 0 {
 		i := len(h) - 1
@@ -105,7 +91,7 @@ func TestRemove0(t *testing.T) {
 		if x != i {
 			t.Errorf("Remove(%d) got %d; want %d", i, x, i)
 		}
-		verify(h, t, 0)
+		verify(t, h)
 	}
 }
 
@@ -114,14 +100,14 @@ func TestRemove1(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		h = append(h, i)
 	}
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 0; len(h) > 0; i++ {
 		x := Remove(&h, 0)
 		if x != i {
 			t.Errorf("Remove(0) got %d; want %d", x, i)
 		}
-		verify(h, t, 0)
+		verify(t, h)
 	}
 }
 
@@ -132,12 +118,12 @@ func TestRemove2(t *testing.T) {
 	for i := 0; i < N; i++ {
 		h = append(h, i)
 	}
-	verify(h, t, 0)
+	verify(t, h)
 
 	m := make(map[int]bool)
 	for len(h) > 0 {
 		m[Remove(&h, (len(h)-1)/2)] = true
-		verify(h, t, 0)
+		verify(t, h)
 	}
 
 	if len(m) != N {
@@ -165,19 +151,19 @@ func BenchmarkDup(b *testing.B) {
 
 func TestFix(t *testing.T) {
 	h := []int{}
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 200; i > 0; i -= 10 {
 		Push(&h, i)
 	}
-	verify(h, t, 0)
+	verify(t, h)
 
 	if h[0] != 10 {
 		t.Fatalf("Expected head to be 10, was %d", h[0])
 	}
 	h[0] = 210
 	Fix(h, 0)
-	verify(h, t, 0)
+	verify(t, h)
 
 	for i := 100; i > 0; i-- {
 		elem := rand.Intn(len(h))
@@ -187,6 +173,109 @@ func TestFix(t *testing.T) {
 			h[elem] /= 2
 		}
 		Fix(h, elem)
-		verify(h, t, 0)
+		verify(t, h)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"Pop", func() { Pop(&[]int{}) }, "sliceheap: Pop on empty heap"},
+		{"Remove", func() { Remove(&[]int{1, 2, 3, 4, 5}, 12) }, "sliceheap: Remove index 12 out of range [0,5)"},
+		{"RemoveNegative", func() { Remove(&[]int{1}, -1) }, "sliceheap: Remove index -1 out of range [0,1)"},
+		{"Fix", func() { Fix([]int{}, 0) }, "sliceheap: Fix index 0 out of range [0,0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("panic = %v; want %q", got, tt.want)
+				}
+			}()
+			tt.f()
+		})
+	}
+}
+
+func TestPopOr(t *testing.T) {
+	var h []int
+	if got := PeekOr(h, -1); got != -1 {
+		t.Errorf("PeekOr(empty, -1) = %d", got)
+	}
+	if got := PopOr(&h, -1); got != -1 {
+		t.Errorf("PopOr(empty, -1) = %d", got)
+	}
+	Push(&h, 2)
+	Push(&h, 1)
+	if got := PeekOr(h, -1); got != 1 {
+		t.Errorf("PeekOr = %d; want 1", got)
+	}
+	greater := func(x, y int) bool { return x > y }
+	InitFunc(h, greater)
+	if got := PopOrFunc(&h, -1, greater); got != 2 || len(h) != 1 {
+		t.Errorf("PopOrFunc = %d, len %d; want 2, 1", got, len(h))
+	}
+}
+
+func TestPopZeroesSlot(t *testing.T) {
+	h := []int{1, 2, 3, 4}
+	Init(h)
+	Pop(&h)
+	if got := h[:4][3]; got != 0 {
+		t.Errorf("Pop left %d in the vacated slot", got)
+	}
+	Remove(&h, 1)
+	if got := h[:3][2]; got != 0 {
+		t.Errorf("Remove left %d in the vacated slot", got)
 	}
 }
+
+func TestGrow(t *testing.T) {
+	h := []int{5, 3, 8, 1}
+	Init(h)
+	before := append([]int(nil), h...)
+	Grow(&h, 100)
+	if cap(h)-len(h) < 100 {
+		t.Fatalf("cap = %d, len = %d after Grow(100)", cap(h), len(h))
+	}
+	for i := range before {
+		if h[i] != before[i] {
+			t.Fatalf("Grow changed elements: %v; want %v", h, before)
+		}
+	}
+	p := &h[0]
+	for i := range 100 {
+		Push(&h, i)
+	}
+	if &h[0] != p {
+		t.Error("Push reallocated after Grow")
+	}
+	verify(t, h)
+
+	var o OrderedHeap[int]
+	o.Grow(10)
+	if cap(o) < 10 {
+		t.Errorf("OrderedHeap cap = %d after Grow(10)", cap(o))
+	}
+}
+
+func TestReplace(t *testing.T) {
+	h := []int{5, 3, 8, 1, 9}
+	Init(h)
+	if x := Replace(h, 7); x != 1 {
+		t.Errorf("Replace = %d; want 1", x)
+	}
+	verify(t, h)
+	if h[0] != 3 {
+		t.Errorf("root = %d after Replace; want 3", h[0])
+	}
+	defer func() {
+		if got := recover(); got != "sliceheap: Replace on empty heap" {
+			t.Errorf("panic = %v", got)
+		}
+	}()
+	Replace([]int{}, 1)
+}
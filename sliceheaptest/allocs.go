@@ -0,0 +1,28 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import "testing"
+
+// AllocRuns is the number of runs over which AssertNoAllocs averages.
+const AllocRuns = 100
+
+// AssertNoAllocs reports an error if f allocates, averaged over AllocRuns
+// runs as by [testing.AllocsPerRun]. Wrappers around the sliceheap
+// functions can use it to check that they keep the package's guarantee
+// that Pop, Remove and Fix never allocate, and that Push allocates only
+// when the slice must grow.
+//
+// The checks made when built with the sliceheap_debug tag allocate, so
+// AssertNoAllocs skips the test in that case.
+func AssertNoAllocs(t testing.TB, f func()) {
+	t.Helper()
+	if debug {
+		t.Skip("skipping allocation test with the sliceheap_debug tag")
+	}
+	if n := testing.AllocsPerRun(AllocRuns, f); n != 0 {
+		t.Errorf("got %v allocations per run; want 0", n)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build sliceheap_debug
+
+package sliceheaptest
+
+// debug mirrors the sliceheap_debug tag of package sliceheap.
+const debug = true
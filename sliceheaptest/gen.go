@@ -0,0 +1,67 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/buth/sliceheap"
+)
+
+// The generators return reproducible inputs of characterized shape for
+// tests and benchmarks. The same arguments always give the same result.
+
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, 0x5eed))
+}
+
+// RandomHeap returns n ints drawn uniformly from [0, n), arranged as a
+// min-heap by sliceheap.Init.
+// It panics if n < 0.
+func RandomHeap(n int, seed uint64) []int {
+	h := ManyDuplicates(n, max(n, 1), seed)
+	sliceheap.Init(h)
+	return h
+}
+
+// NearlySorted returns the ints 0 to n-1 in ascending order, which is a
+// valid min-heap, disturbed by swapping disorder*n pairs of elements at
+// random positions, so that disorder 0 is sorted and disorder around 1 is
+// close to a random permutation.
+// It panics if n < 0 or disorder < 0.
+func NearlySorted(n int, disorder float64, seed uint64) []int {
+	if n < 0 || disorder < 0 {
+		panic(fmt.Sprintf("sliceheaptest: NearlySorted(%d, %v)", n, disorder))
+	}
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	if n < 2 {
+		return s
+	}
+	r := newRand(seed)
+	for range int(disorder * float64(n)) {
+		i, j := r.IntN(n), r.IntN(n)
+		s[i], s[j] = s[j], s[i]
+	}
+	return s
+}
+
+// ManyDuplicates returns n ints drawn uniformly from [0, distinct), so
+// that each value occurs about n/distinct times.
+// It panics if n < 0 or distinct < 1.
+func ManyDuplicates(n, distinct int, seed uint64) []int {
+	if n < 0 || distinct < 1 {
+		panic(fmt.Sprintf("sliceheaptest: ManyDuplicates(%d, %d)", n, distinct))
+	}
+	r := newRand(seed)
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.IntN(distinct)
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/buth/sliceheap"
+)
+
+func TestGenerators(t *testing.T) {
+	h := RandomHeap(1000, 1)
+	if err := sliceheap.Verify(h); err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(h, RandomHeap(1000, 1)) || slices.Equal(h, RandomHeap(1000, 2)) {
+		t.Error("RandomHeap not determined by its seed")
+	}
+	if len(RandomHeap(0, 1)) != 0 {
+		t.Error("RandomHeap(0) not empty")
+	}
+
+	if s := NearlySorted(100, 0, 1); !slices.IsSorted(s) {
+		t.Errorf("NearlySorted with no disorder = %v", s)
+	}
+	s := NearlySorted(1000, 0.01, 1)
+	out := 0
+	for i, x := range s {
+		if x != i {
+			out++
+		}
+	}
+	if out == 0 || out > 20 {
+		t.Errorf("NearlySorted(1000, 0.01) has %d elements out of place; want 1 to 20", out)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(s)), NearlySorted(1000, 0, 1)) {
+		t.Error("NearlySorted is not a permutation")
+	}
+
+	d := ManyDuplicates(1000, 4, 1)
+	counts := make(map[int]int)
+	for _, x := range d {
+		counts[x]++
+	}
+	if len(counts) != 4 {
+		t.Errorf("ManyDuplicates(1000, 4) has %d distinct values", len(counts))
+	}
+	for v, c := range counts {
+		if v < 0 || v >= 4 || c < 150 {
+			t.Errorf("value %d occurs %d times", v, c)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sliceheaptest provides a differential test harness for heaps. It
+// runs random sequences of operations against a heap and against a simple
+// sorted-slice model, and reports the first point at which they disagree
+// together with the seed that reproduces it. It can check a custom
+// comparator with the sliceheap functions, or a wrapper type built on them.
+// CheckBytes and Fuzz take the operations from a fuzzer's input instead.
+package sliceheaptest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap"
+)
+
+// DefaultOps is the number of operations run when Config.Ops is zero.
+const DefaultOps = 1000
+
+// A Config describes a test run. Less and Gen must be set; the other fields
+// are optional.
+type Config[T any] struct {
+	// Less is the ordering the heap is expected to follow.
+	Less func(x, y T) bool
+
+	// Gen returns a random element to push.
+	Gen func(r *rand.Rand) T
+
+	// New returns an empty heap to test. If nil, a slice managed by
+	// sliceheap.PushFunc and sliceheap.PopFunc with Less is tested, and
+	// the heap invariant is verified after every operation.
+	New func() sliceheap.PriorityQueue[T]
+
+	// Seed seeds the random operations. If zero, a seed is chosen from
+	// the current time; either way it is reported with any failure.
+	Seed uint64
+
+	// Ops is the number of operations to run. If zero, DefaultOps is used.
+	Ops int
+}
+
+// A Divergence reports where the heap and the model disagreed.
+type Divergence struct {
+	Seed uint64 // the seed that reproduces the failure, from Check
+	Data []byte // the input that reproduces the failure, from CheckBytes
+	Op   int    // the index of the failing operation
+	Msg  string
+}
+
+func (d *Divergence) Error() string {
+	if d.Data != nil {
+		return fmt.Sprintf("sliceheaptest: input %x: operation %d: %s", d.Data, d.Op, d.Msg)
+	}
+	return fmt.Sprintf("sliceheaptest: seed %d: operation %d: %s", d.Seed, d.Op, d.Msg)
+}
+
+// Check runs the operations described by c and returns a *Divergence for
+// the first disagreement with the model, or nil if there was none. A panic
+// in the heap is reported as a divergence.
+func Check[T any](c Config[T]) error {
+	seed := c.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	ops := c.Ops
+	if ops <= 0 {
+		ops = DefaultOps
+	}
+	r := rand.New(rand.NewPCG(seed, seed))
+	op := 0
+	next := func(empty bool) (push bool, x T, ok bool) {
+		if op == ops {
+			return false, x, false
+		}
+		op++
+		// Push somewhat more often than Pop, so the heap grows.
+		if empty || r.IntN(5) < 3 {
+			return true, c.Gen(r), true
+		}
+		return false, x, true
+	}
+	if d := check(c, next); d != nil {
+		d.Seed = seed
+		return d
+	}
+	return nil
+}
+
+// CheckBytes is like [Check] but takes the operations from data rather
+// than from a random source, so that it can be driven by a fuzzer; see
+// [Fuzz]. Each byte is one operation: a Pop if its low two bits are set and
+// the heap is not empty, and otherwise a Push of the element Gen returns
+// when given a source seeded with the byte. c.Seed and c.Ops are ignored.
+func CheckBytes[T any](c Config[T], data []byte) error {
+	op := 0
+	next := func(empty bool) (push bool, x T, ok bool) {
+		if op == len(data) {
+			return false, x, false
+		}
+		b := data[op]
+		op++
+		if !empty && b&3 == 3 {
+			return false, x, true
+		}
+		return true, c.Gen(rand.New(rand.NewPCG(uint64(b), 0))), true
+	}
+	if d := check(c, next); d != nil {
+		d.Data = data
+		return d
+	}
+	return nil
+}
+
+// check runs the operations returned by next, which is told whether the
+// heap is empty and reports false when there are no more, against the heap
+// and the model.
+func check[T any](c Config[T], next func(empty bool) (push bool, x T, ok bool)) (d *Divergence) {
+	h := newHeap(c)
+	fail := func(op int, format string, args ...any) *Divergence {
+		return &Divergence{Op: op, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	var model []T // sorted
+	op := 0
+	defer func() {
+		if v := recover(); v != nil {
+			d = fail(op, "panic: %v", v)
+		}
+	}()
+	for ; ; op++ {
+		push, x, ok := next(len(model) == 0)
+		if !ok {
+			return nil
+		}
+		if push {
+			h.Push(x)
+			i, _ := slices.BinarySearchFunc(model, x, func(e, x T) int {
+				if c.Less(x, e) {
+					return 1
+				}
+				return -1
+			})
+			model = slices.Insert(model, i, x)
+		} else {
+			want := model[0]
+			if got := h.Peek(); c.Less(got, want) || c.Less(want, got) {
+				return fail(op, "Peek() = %v; want %v", got, want)
+			}
+			got := h.Pop()
+			model = model[1:]
+			if c.Less(got, want) || c.Less(want, got) {
+				return fail(op, "Pop() = %v; want %v", got, want)
+			}
+		}
+		if n := h.Len(); n != len(model) {
+			return fail(op, "Len() = %d; want %d", n, len(model))
+		}
+		if v, ok := h.(verifier); ok {
+			if err := v.verify(); err != nil {
+				return fail(op, "%v", err)
+			}
+		}
+	}
+}
+
+// Run is like [Check] but reports a failure through t.
+func Run[T any](t testing.TB, c Config[T]) {
+	t.Helper()
+	if err := Check(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fuzz is like [CheckBytes] but reports a failure through t. It is meant to
+// be called from a fuzz target:
+//
+//	func FuzzMyHeap(f *testing.F) {
+//		f.Add([]byte{0, 1, 3, 2, 3})
+//		f.Fuzz(func(t *testing.T, data []byte) {
+//			sliceheaptest.Fuzz(t, config, data)
+//		})
+//	}
+func Fuzz[T any](t testing.TB, c Config[T], data []byte) {
+	t.Helper()
+	if err := CheckBytes(c, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type verifier interface {
+	verify() error
+}
+
+type sliceHeap[T any] struct {
+	h    []T
+	less func(x, y T) bool
+}
+
+func newHeap[T any](c Config[T]) sliceheap.PriorityQueue[T] {
+	if c.New != nil {
+		return c.New()
+	}
+	return &sliceHeap[T]{less: c.Less}
+}
+
+func (s *sliceHeap[T]) Push(x T)      { sliceheap.PushFunc(&s.h, x, s.less) }
+func (s *sliceHeap[T]) Pop() T        { return sliceheap.PopFunc(&s.h, s.less) }
+func (s *sliceHeap[T]) Peek() T       { return s.h[0] }
+func (s *sliceHeap[T]) Len() int      { return len(s.h) }
+func (s *sliceHeap[T]) verify() error { return sliceheap.VerifyFunc(s.h, s.less) }
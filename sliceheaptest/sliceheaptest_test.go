@@ -0,0 +1,103 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/buth/sliceheap"
+)
+
+func genInt(r *rand.Rand) int { return r.IntN(100) }
+
+type pair struct{ k, v int }
+
+func TestRun(t *testing.T) {
+	Run(t, Config[int]{Less: cmp.Less[int], Gen: genInt})
+
+	// Elements that compare equal may pop in any order.
+	Run(t, Config[pair]{
+		Less: func(x, y pair) bool { return x.k < y.k },
+		Gen:  func(r *rand.Rand) pair { return pair{r.IntN(5), r.Int()} },
+	})
+}
+
+// stack is a broken heap that pops the most recent element.
+type stack []int
+
+func (s *stack) Push(x int) { *s = append(*s, x) }
+func (s *stack) Len() int   { return len(*s) }
+func (s *stack) Peek() int  { return (*s)[len(*s)-1] }
+func (s *stack) Pop() int {
+	x := (*s)[len(*s)-1]
+	*s = (*s)[:len(*s)-1]
+	return x
+}
+
+func TestDivergence(t *testing.T) {
+	c := Config[int]{
+		Less: cmp.Less[int],
+		Gen:  genInt,
+		New:  func() sliceheap.PriorityQueue[int] { return new(stack) },
+		Seed: 42,
+	}
+	err := Check(c)
+	var d *Divergence
+	if !errors.As(err, &d) || d.Seed != 42 {
+		t.Fatalf("Check of broken heap = %v; want a divergence with seed 42", err)
+	}
+
+	// The same seed reproduces the same failure.
+	if err2 := Check(c); err2 == nil || err2.Error() != err.Error() {
+		t.Errorf("rerun with seed 42 = %v; want %v", err2, err)
+	}
+}
+
+func TestBadComparator(t *testing.T) {
+	// A comparator that is not a strict weak order is caught by the
+	// invariant check or by the model.
+	bad := func(x, y int) bool { return x%10 < y%10 || x < y }
+	c := Config[int]{Less: bad, Gen: genInt, Seed: 1}
+	if err := Check(c); err == nil {
+		t.Error("Check with inconsistent comparator succeeded")
+	}
+}
+
+func TestAssertNoAllocs(t *testing.T) {
+	h := make([]int, 0, 16)
+	AssertNoAllocs(t, func() {
+		sliceheap.Push(&h, 3)
+		sliceheap.Push(&h, 1)
+		sliceheap.Pop(&h)
+		sliceheap.Pop(&h)
+	})
+}
+
+func TestCheckBytes(t *testing.T) {
+	c := Config[int]{Less: cmp.Less[int], Gen: genInt}
+	data := []byte{10, 200, 3, 7, 3, 3, 3, 3, 42}
+	if err := CheckBytes(c, data); err != nil {
+		t.Fatal(err)
+	}
+
+	c.New = func() sliceheap.PriorityQueue[int] { return new(stack) }
+	err := CheckBytes(c, []byte{1, 2, 3})
+	var d *Divergence
+	if !errors.As(err, &d) || string(d.Data) != "\x01\x02\x03" || d.Op != 2 {
+		t.Errorf("CheckBytes of broken heap = %v; want a divergence at operation 2", err)
+	}
+}
+
+func FuzzSliceHeap(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 3, 3})
+	f.Add([]byte{255, 254, 253, 3, 7, 11})
+	c := Config[int]{Less: cmp.Less[int], Gen: genInt}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		Fuzz(t, c, data)
+	})
+}
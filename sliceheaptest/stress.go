@@ -0,0 +1,244 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A ConcurrentHeap is a concurrent priority queue of int keys under stress
+// test, popping the least key first. Adapt a queue of another element type
+// by deriving its priority from the key and recovering the key on Pop.
+type ConcurrentHeap interface {
+	Push(x int)
+	TryPop() (int, bool)
+}
+
+// A StressConfig describes a stress test.
+type StressConfig struct {
+	// New returns an empty queue to test.
+	New func() ConcurrentHeap
+
+	// Producers and Consumers are the numbers of goroutines pushing and
+	// popping. If zero, GOMAXPROCS is used for each.
+	Producers, Consumers int
+
+	// Ops is the number of keys each producer pushes. If zero,
+	// DefaultOps is used.
+	Ops int
+
+	// Seed seeds the keys pushed and the points at which goroutines
+	// yield. If zero, a seed is chosen from the current time; either way
+	// it is reported with any failure. The interleaving of goroutines is
+	// up to the scheduler, so a seed makes a failure likely, not certain,
+	// to recur.
+	Seed uint64
+}
+
+// A StressError reports a violation found by Stress.
+type StressError struct {
+	Seed uint64
+	Msg  string
+}
+
+func (e *StressError) Error() string {
+	return fmt.Sprintf("sliceheaptest: stress seed %d: %s", e.Seed, e.Msg)
+}
+
+type stressPush struct {
+	key int
+	end int64 // stamp after Push returned
+}
+
+type stressPop struct {
+	key        int
+	start, end int64 // stamps before TryPop was called and after it returned
+}
+
+// Stress runs producers pushing distinct random keys concurrently with
+// consumers popping them, until every key has been popped or the queue
+// stays empty after the last push, and checks the history. It returns a
+// *StressError if a key was lost, duplicated or invented, if a key was
+// left in the queue, or if a Pop returned a key while a lesser key had
+// been pushed before that Pop began and was not popped until after it
+// ended, which no linearizable priority queue can do.
+func Stress(c StressConfig) error {
+	seed := c.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	producers, consumers, ops := c.Producers, c.Consumers, c.Ops
+	if producers <= 0 {
+		producers = runtime.GOMAXPROCS(0)
+	}
+	if consumers <= 0 {
+		consumers = runtime.GOMAXPROCS(0)
+	}
+	if ops <= 0 {
+		ops = DefaultOps
+	}
+	total := producers * ops
+	fail := func(format string, args ...any) error {
+		return &StressError{Seed: seed, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	h := c.New()
+	var clock atomic.Int64
+	pushes := make([][]stressPush, producers)
+	pops := make([][]stressPop, consumers)
+	var popped atomic.Int64
+	var pushing, popping sync.WaitGroup
+	var pushed atomic.Bool // set once every producer has returned
+	for p := range producers {
+		pushing.Add(1)
+		go func() {
+			defer pushing.Done()
+			r := rand.New(rand.NewPCG(seed, uint64(p)))
+			for i := range ops {
+				// Keys are unique: a random priority, then the push's
+				// position to break ties.
+				key := r.IntN(1000)*total + p*ops + i
+				h.Push(key)
+				pushes[p] = append(pushes[p], stressPush{key, clock.Add(1)})
+				if r.IntN(8) == 0 {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	for q := range consumers {
+		popping.Add(1)
+		go func() {
+			defer popping.Done()
+			r := rand.New(rand.NewPCG(seed, uint64(producers+q)))
+			var idle time.Time // when the queue was first found empty after the pushes
+			for popped.Load() < int64(total) {
+				start := clock.Add(1)
+				key, ok := h.TryPop()
+				end := clock.Add(1)
+				if ok {
+					popped.Add(1)
+					pops[q] = append(pops[q], stressPop{key, start, end})
+					idle = time.Time{}
+				} else if pushed.Load() {
+					// A queue that stays empty after every push has
+					// returned has lost keys; give up on them.
+					if idle.IsZero() {
+						idle = time.Now()
+					} else if time.Since(idle) > stressIdle {
+						return
+					}
+				}
+				if !ok || r.IntN(8) == 0 {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	pushing.Wait()
+	pushed.Store(true)
+	popping.Wait()
+	if key, ok := h.TryPop(); ok {
+		return fail("key %d left in the queue after it appeared empty", key)
+	}
+
+	pushEnd := make(map[int]int64, total) // key to push end stamp
+	for _, ps := range pushes {
+		for _, p := range ps {
+			pushEnd[p.key] = p.end
+		}
+	}
+	var all []stressPop
+	seen := make(map[int]bool, total)
+	for _, ps := range pops {
+		for _, p := range ps {
+			if _, ok := pushEnd[p.key]; !ok {
+				return fail("popped key %d that was never pushed", p.key)
+			}
+			if seen[p.key] {
+				return fail("key %d popped twice", p.key)
+			}
+			seen[p.key] = true
+			all = append(all, p)
+		}
+	}
+	if len(all) < total {
+		var lost []int
+		for key := range pushEnd {
+			if !seen[key] {
+				lost = append(lost, key)
+			}
+		}
+		sort.Ints(lost)
+		return fail("%d keys pushed and never popped, the least %d", len(lost), lost[0])
+	}
+
+	// For each Pop, no lesser key may have been in the queue throughout
+	// it: pushed before the Pop began and popped after it ended. Scanning
+	// Pops in order of key, record each in a tree indexed by its push
+	// stamp that gives the latest Pop start among keys pushed before a
+	// given stamp.
+	stamps := make([]int64, 0, total)
+	for _, end := range pushEnd {
+		stamps = append(stamps, end)
+	}
+	slices.Sort(stamps)
+	latest := make(maxTree, len(stamps)+1)
+	sort.Slice(all, func(i, j int) bool { return all[i].key < all[j].key })
+	for _, p := range all {
+		before, _ := slices.BinarySearch(stamps, p.start)
+		if q := latest.max(before); q.start > p.end {
+			return fail("Pop returned %d while %d, pushed earlier, was queued", p.key, q.key)
+		}
+		i, _ := slices.BinarySearch(stamps, pushEnd[p.key])
+		latest.add(i, p)
+	}
+	return nil
+}
+
+// stressIdle is how long consumers wait on an empty queue, after every
+// push has returned, before Stress concludes that keys were lost.
+const stressIdle = 100 * time.Millisecond
+
+// A maxTree is a Fenwick tree of Pops, giving the Pop with the latest start
+// among those added at indices below a bound.
+type maxTree []stressPop
+
+// add records p at index i.
+func (t maxTree) add(i int, p stressPop) {
+	for i++; i < len(t); i += i & -i {
+		if p.start > t[i].start {
+			t[i] = p
+		}
+	}
+}
+
+// max returns the Pop with the latest start added at an index below n, or
+// the zero Pop if there is none.
+func (t maxTree) max(n int) stressPop {
+	var m stressPop
+	for ; n > 0; n -= n & -n {
+		if t[n].start > m.start {
+			m = t[n]
+		}
+	}
+	return m
+}
+
+// RunStress is like [Stress] but reports a failure through t.
+func RunStress(t testing.TB, c StressConfig) {
+	t.Helper()
+	if err := Stress(c); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheaptest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/buth/sliceheap"
+)
+
+// lockedHeap is a correct concurrent heap.
+type lockedHeap struct {
+	mu sync.Mutex
+	h  []int
+}
+
+func (l *lockedHeap) Push(x int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sliceheap.Push(&l.h, x)
+}
+
+func (l *lockedHeap) TryPop() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.h) == 0 {
+		return 0, false
+	}
+	return sliceheap.Pop(&l.h), true
+}
+
+// lossyHeap drops every hundredth key pushed.
+type lossyHeap struct {
+	lockedHeap
+	n int
+}
+
+func (l *lossyHeap) Push(x int) {
+	l.mu.Lock()
+	l.n++
+	drop := l.n%100 == 0
+	l.mu.Unlock()
+	if !drop {
+		l.lockedHeap.Push(x)
+	}
+}
+
+// lockedStack pops the most recent key.
+type lockedStack struct {
+	mu sync.Mutex
+	s  []int
+}
+
+func (l *lockedStack) Push(x int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s = append(l.s, x)
+}
+
+func (l *lockedStack) TryPop() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.s) == 0 {
+		return 0, false
+	}
+	x := l.s[len(l.s)-1]
+	l.s = l.s[:len(l.s)-1]
+	return x, true
+}
+
+func TestStress(t *testing.T) {
+	RunStress(t, StressConfig{
+		New:       func() ConcurrentHeap { return new(lockedHeap) },
+		Producers: 4,
+		Consumers: 3,
+		Ops:       300,
+	})
+
+	err := Stress(StressConfig{
+		New:       func() ConcurrentHeap { return new(lockedStack) },
+		Producers: 2,
+		Consumers: 2,
+		Ops:       300,
+		Seed:      7,
+	})
+	var e *StressError
+	if !errors.As(err, &e) || e.Seed != 7 {
+		t.Errorf("Stress of a stack = %v; want a violation", err)
+	}
+
+	// A queue that loses keys fails rather than hanging.
+	err = Stress(StressConfig{
+		New:       func() ConcurrentHeap { return new(lossyHeap) },
+		Producers: 2,
+		Consumers: 2,
+		Ops:       300,
+	})
+	if !errors.As(err, &e) || !strings.Contains(e.Msg, "never popped") {
+		t.Errorf("Stress of a lossy queue = %v; want lost keys", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// HeapStats describes the shape and contents of a heap.
+type HeapStats struct {
+	Len    int
+	Depth  int // number of levels in the tree
+	Leaves int // number of elements without children
+
+	// LastLevelFill is the fraction of the deepest level that is occupied,
+	// in (0, 1], or 0 for an empty heap.
+	LastLevelFill float64
+
+	// Duplicates is the fraction of elements equal to another element
+	// earlier in sorted order, where elements are equal if neither is less
+	// than the other. [DuplicatesFunc] computes it with an equality
+	// function instead.
+	Duplicates float64
+
+	// Sortedness is the fraction of adjacent pairs of elements in the
+	// slice that are in order. A sorted slice, which is also a valid
+	// heap, has Sortedness 1.
+	Sortedness float64
+}
+
+// Stats returns statistics describing h, which need not satisfy the heap
+// invariants. They can help in sizing heaps, and in deciding whether a
+// heap whose elements have changed is better rebuilt with Init or repaired
+// with Fix.
+// The complexity is O(n log n) where n = len(h), and Stats allocates a copy
+// of h.
+func Stats[T cmp.Ordered](h []T) HeapStats {
+	return StatsFunc(h, cmp.Less[T])
+}
+
+// StatsFunc is like [Stats] but uses a less function to compare elements.
+func StatsFunc[T any](h []T, less func(x, y T) bool) HeapStats {
+	n := len(h)
+	s := HeapStats{Len: n}
+	if n == 0 {
+		return s
+	}
+	s.Depth = bits.Len(uint(n))
+	s.Leaves = n - n/2
+	width := 1 << (s.Depth - 1)
+	s.LastLevelFill = float64(n-(width-1)) / float64(width)
+
+	if n == 1 {
+		s.Sortedness = 1
+		return s
+	}
+	inOrder := 0
+	for i := 1; i < n; i++ {
+		if !less(h[i], h[i-1]) {
+			inOrder++
+		}
+	}
+	s.Sortedness = float64(inOrder) / float64(n-1)
+
+	sorted := sortedDesc(h, less)
+	dups := 0
+	for i := 1; i < n; i++ {
+		if !less(sorted[i], sorted[i-1]) {
+			dups++
+		}
+	}
+	s.Duplicates = float64(dups) / float64(n)
+	return s
+}
+
+// DuplicatesFunc returns the fraction of elements of h equal, according to
+// eq, to another element earlier in sorted order. It is like the
+// Duplicates field of [HeapStats] for elements that are equivalent under
+// less but not equal, such as records with the same priority but
+// different payloads. Elements equal under eq must be equivalent under
+// less, so that sorting brings them together.
+// The complexity is O(n log n + r²) where n = len(h) and r is the length
+// of the longest run of equivalent elements, and DuplicatesFunc allocates
+// a copy of h.
+func DuplicatesFunc[T any](h []T, less func(x, y T) bool, eq func(x, y T) bool) float64 {
+	n := len(h)
+	if n == 0 {
+		return 0
+	}
+	sorted := sortedDesc(h, less)
+	dups := 0
+	for lo := 0; lo < n; {
+		hi := lo + 1
+		for hi < n && !less(sorted[hi], sorted[hi-1]) {
+			hi++
+		}
+		for j := lo + 1; j < hi; j++ {
+			for k := lo; k < j; k++ {
+				if eq(sorted[k], sorted[j]) {
+					dups++
+					break
+				}
+			}
+		}
+		lo = hi
+	}
+	return float64(dups) / float64(n)
+}
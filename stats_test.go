@@ -0,0 +1,42 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		h    []int
+		want HeapStats
+	}{
+		{nil, HeapStats{}},
+		{[]int{1}, HeapStats{Len: 1, Depth: 1, Leaves: 1, LastLevelFill: 1, Sortedness: 1}},
+		{[]int{1, 2, 3, 4, 5, 6, 7}, HeapStats{Len: 7, Depth: 3, Leaves: 4, LastLevelFill: 1, Sortedness: 1}},
+		{[]int{1, 3, 2, 3, 5, 3, 4, 9}, HeapStats{Len: 8, Depth: 4, Leaves: 4, LastLevelFill: 0.125, Duplicates: 0.25, Sortedness: 5.0 / 7}},
+		{[]int{2, 2, 2, 2}, HeapStats{Len: 4, Depth: 3, Leaves: 2, LastLevelFill: 0.25, Duplicates: 0.75, Sortedness: 1}},
+	}
+	for _, tt := range tests {
+		if got := Stats(tt.h); got != tt.want {
+			t.Errorf("Stats(%v) = %+v; want %+v", tt.h, got, tt.want)
+		}
+	}
+}
+
+func TestDuplicatesFunc(t *testing.T) {
+	type job struct{ priority, id int }
+	less := func(x, y job) bool { return x.priority < y.priority }
+	eq := func(x, y job) bool { return x == y }
+	h := []job{{1, 1}, {2, 1}, {1, 2}, {1, 1}, {2, 1}, {3, 1}, {1, 2}, {1, 3}}
+	// {1,1}, {2,1} and {1,2} each occur a second time.
+	if got, want := DuplicatesFunc(h, less, eq), 3.0/8; got != want {
+		t.Errorf("DuplicatesFunc = %v; want %v", got, want)
+	}
+	if got, want := StatsFunc(h, less).Duplicates, 5.0/8; got != want {
+		t.Errorf("StatsFunc(h).Duplicates = %v; want %v", got, want)
+	}
+	if got := DuplicatesFunc(nil, less, eq); got != 0 {
+		t.Errorf("DuplicatesFunc(nil) = %v; want 0", got)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+// A SwapperHeap keeps elements addressed by index in heap order through
+// caller-supplied Less and Swap functions, as sort.Slice does for sorting
+// and container/heap does through heap.Interface. It suits data that is not
+// a single slice: several parallel slices kept in step, or other indexed
+// storage. The caller owns the storage and its length; the methods take
+// the current length n and never add or remove elements themselves.
+//
+// For example, to keep parallel slices of keys and values in heap order:
+//
+//	s := sliceheap.SwapperHeap{
+//		Less: func(i, j int) bool { return keys[i] < keys[j] },
+//		Swap: func(i, j int) {
+//			keys[i], keys[j] = keys[j], keys[i]
+//			vals[i], vals[j] = vals[j], vals[i]
+//		},
+//	}
+//	s.Init(len(keys))
+type SwapperHeap struct {
+	// Less reports whether the element at index i orders before the
+	// element at index j.
+	Less func(i, j int) bool
+
+	// Swap swaps the elements at indices i and j.
+	Swap func(i, j int)
+}
+
+// Init establishes the heap invariants over the elements at indices [0,n).
+// The complexity is O(n).
+func (s SwapperHeap) Init(n int) {
+	for i := n/2 - 1; i >= 0; i-- {
+		s.down(i, n)
+	}
+}
+
+// Push restores the heap invariants after an element has been added at
+// index n-1 of a heap now of length n.
+// The complexity is O(log n).
+func (s SwapperHeap) Push(n int) {
+	s.up(n - 1)
+}
+
+// Pop moves the minimum element of a heap of length n to index n-1 and
+// restores the heap invariants over [0,n-1). The caller then takes the
+// element from index n-1 and shortens its storage.
+// Pop panics if n < 1.
+// The complexity is O(log n).
+func (s SwapperHeap) Pop(n int) {
+	if n < 1 {
+		panic("sliceheap: Pop on empty heap")
+	}
+	s.Swap(0, n-1)
+	s.down(0, n-1)
+}
+
+// Remove moves the element at index i of a heap of length n to index n-1
+// and restores the heap invariants over [0,n-1). The caller then takes the
+// element from index n-1 and shortens its storage.
+// Remove panics if i is out of range.
+// The complexity is O(log n).
+func (s SwapperHeap) Remove(n, i int) {
+	if uint(i) >= uint(n) {
+		panicRange("Remove", i, n)
+	}
+	if last := n - 1; last != i {
+		s.Swap(i, last)
+		if !s.down(i, last) {
+			s.up(i)
+		}
+	}
+}
+
+// Fix restores the heap invariants of a heap of length n after the
+// element at index i has changed.
+// Fix panics if i is out of range.
+// The complexity is O(log n).
+func (s SwapperHeap) Fix(n, i int) {
+	if uint(i) >= uint(n) {
+		panicRange("Fix", i, n)
+	}
+	if !s.down(i, n) {
+		s.up(i)
+	}
+}
+
+func (s SwapperHeap) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !s.Less(j, i) {
+			break
+		}
+		s.Swap(i, j)
+		j = i
+	}
+}
+
+func (s SwapperHeap) down(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && s.Less(j2, j1) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !s.Less(j, i) {
+			break
+		}
+		s.Swap(i, j)
+		i = j
+	}
+	return i > i0
+}
@@ -0,0 +1,62 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSwapperHeap(t *testing.T) {
+	var keys []int
+	var vals []string
+	s := SwapperHeap{
+		Less: func(i, j int) bool { return keys[i] < keys[j] },
+		Swap: func(i, j int) {
+			keys[i], keys[j] = keys[j], keys[i]
+			vals[i], vals[j] = vals[j], vals[i]
+		},
+	}
+	push := func(k int) {
+		keys = append(keys, k)
+		vals = append(vals, strconv.Itoa(k))
+		s.Push(len(keys))
+	}
+	pop := func() int {
+		s.Pop(len(keys))
+		n := len(keys) - 1
+		k, v := keys[n], vals[n]
+		keys, vals = keys[:n], vals[:n]
+		if v != strconv.Itoa(k) {
+			t.Fatalf("key %d popped with value %s", k, v)
+		}
+		return k
+	}
+
+	for _, k := range rand.Perm(50) {
+		keys = append(keys, k)
+		vals = append(vals, strconv.Itoa(k))
+	}
+	s.Init(len(keys))
+	for i := 50; i < 60; i++ {
+		push(i)
+	}
+	s.Remove(len(keys), 10)
+	keys, vals = keys[:59], vals[:59]
+	keys[3] = -1
+	vals[3] = "-1"
+	s.Fix(len(keys), 3)
+	verify(t, keys)
+
+	var got []int
+	for len(keys) > 0 {
+		got = append(got, pop())
+	}
+	if len(got) != 59 || got[0] != -1 || !slices.IsSorted(got) {
+		t.Errorf("popped %v", got)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+// LessForType builds a less function for the struct type T from its "heap"
+// struct tags. Each tagged field takes part in the ordering; fields without a
+// tag, or tagged "-", are ignored. A tag consists of an optional name or
+// integer rank followed by options: a direction, "asc" (the default) or
+// "desc", and "rank=N" giving the rank explicitly. The name only documents
+// the key; it must be an identifier and must not resemble an option, so
+// that a misspelled direction such as "dsc" is reported rather than taken
+// as a name:
+//
+//	type Job struct {
+//		Priority int       `heap:"priority,desc"`
+//		Created  time.Time // ignored
+//		Name     string    `heap:"name,asc"`
+//		Seq      uint64    `heap:"2"`
+//	}
+//
+// Fields are compared in increasing rank, with unranked fields following the
+// ranked ones in declaration order; above, Seq is compared first, then
+// Priority, then Name. Tagged fields must be exported and of an integer,
+// floating-point, or string kind.
+//
+// The tags are validated once, when LessForType is called, and an error is
+// returned describing the first problem found.
+func LessForType[T any]() (func(x, y T) bool, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sliceheap: LessForType of non-struct type %v", t)
+	}
+
+	var keys []tagKey
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("heap")
+		if !ok || tag == "-" {
+			continue
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("sliceheap: tagged field %s.%s is not exported", t, f.Name)
+		}
+		k := tagKey{offset: f.Offset, kind: f.Type.Kind()}
+		switch k.kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64,
+			reflect.String:
+		default:
+			return nil, fmt.Errorf("sliceheap: tagged field %s.%s has unordered type %v", t, f.Name, f.Type)
+		}
+		for n, opt := range strings.Split(tag, ",") {
+			switch opt = strings.TrimSpace(opt); {
+			case opt == "":
+			case opt == "asc":
+				k.desc = false
+			case opt == "desc":
+				k.desc = true
+			case strings.HasPrefix(opt, "rank="):
+				r, err := strconv.Atoi(opt[len("rank="):])
+				if err != nil {
+					return nil, fmt.Errorf("sliceheap: field %s.%s has invalid rank %q", t, f.Name, opt)
+				}
+				k.rank, k.ranked = r, true
+			case n == 0:
+				// A leading integer is a rank; an identifier names the key.
+				if r, err := strconv.Atoi(opt); err == nil {
+					k.rank, k.ranked = r, true
+				} else if err := checkKeyName(opt); err != nil {
+					return nil, fmt.Errorf("sliceheap: field %s.%s has %v", t, f.Name, err)
+				}
+			default:
+				return nil, fmt.Errorf("sliceheap: field %s.%s has unknown tag option %q", t, f.Name, opt)
+			}
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sliceheap: LessForType of type %v with no heap tags", t)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].ranked != keys[j].ranked {
+			return keys[i].ranked
+		}
+		return keys[i].rank < keys[j].rank
+	})
+
+	// The fields are read at their offsets rather than through
+	// reflect.Value, which would make x and y escape and allocate on
+	// every comparison.
+	return func(x, y T) bool {
+		px, py := unsafe.Pointer(&x), unsafe.Pointer(&y)
+		for _, k := range keys {
+			if r := k.compare(px, py); r != 0 {
+				return r < 0
+			}
+		}
+		return false
+	}, nil
+}
+
+// A tagKey is a field of a struct type taking part in the ordering built
+// by [LessForType].
+type tagKey struct {
+	offset uintptr
+	kind   reflect.Kind
+	rank   int
+	ranked bool
+	desc   bool
+}
+
+// compare compares the field of the structs at px and py.
+func (k tagKey) compare(px, py unsafe.Pointer) int {
+	x, y := unsafe.Add(px, k.offset), unsafe.Add(py, k.offset)
+	if k.desc {
+		x, y = y, x
+	}
+	switch k.kind {
+	case reflect.Int:
+		return cmp.Compare(*(*int)(x), *(*int)(y))
+	case reflect.Int8:
+		return cmp.Compare(*(*int8)(x), *(*int8)(y))
+	case reflect.Int16:
+		return cmp.Compare(*(*int16)(x), *(*int16)(y))
+	case reflect.Int32:
+		return cmp.Compare(*(*int32)(x), *(*int32)(y))
+	case reflect.Int64:
+		return cmp.Compare(*(*int64)(x), *(*int64)(y))
+	case reflect.Uint:
+		return cmp.Compare(*(*uint)(x), *(*uint)(y))
+	case reflect.Uint8:
+		return cmp.Compare(*(*uint8)(x), *(*uint8)(y))
+	case reflect.Uint16:
+		return cmp.Compare(*(*uint16)(x), *(*uint16)(y))
+	case reflect.Uint32:
+		return cmp.Compare(*(*uint32)(x), *(*uint32)(y))
+	case reflect.Uint64:
+		return cmp.Compare(*(*uint64)(x), *(*uint64)(y))
+	case reflect.Uintptr:
+		return cmp.Compare(*(*uintptr)(x), *(*uintptr)(y))
+	case reflect.Float32:
+		return cmp.Compare(*(*float32)(x), *(*float32)(y))
+	case reflect.Float64:
+		return cmp.Compare(*(*float64)(x), *(*float64)(y))
+	default: // reflect.String
+		return cmp.Compare(*(*string)(x), *(*string)(y))
+	}
+}
+
+// tagOptions are the options a tag may contain, which a key name must not
+// resemble.
+var tagOptions = []string{"asc", "desc", "rank"}
+
+// checkKeyName reports an error if name, the leading token of a tag, is
+// not an identifier or looks like a misspelled option, such as
+// "descending" or "dsc", which would otherwise be taken silently as a
+// name.
+func checkKeyName(name string) error {
+	for i, c := range name {
+		if c != '_' && !unicode.IsLetter(c) && (i == 0 || !unicode.IsDigit(c)) {
+			return fmt.Errorf("invalid key name %q", name)
+		}
+	}
+	lower := strings.ToLower(name)
+	for _, opt := range tagOptions {
+		if strings.HasPrefix(lower, opt) || editDistance(lower, opt) <= 1 {
+			return fmt.Errorf("key name %q that looks like the option %q", name, opt)
+		}
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	d := make([]int, len(b)+1)
+	for j := range d {
+		d[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := d[0]
+		d[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			prev, d[j] = d[j], min(d[j]+1, d[j-1]+1, prev+cost)
+		}
+	}
+	return d[len(b)]
+}
+
+// MustLessForType is like [LessForType] but panics if the tags are invalid.
+// It simplifies the initialization of global variables holding less
+// functions.
+func MustLessForType[T any]() func(x, y T) bool {
+	less, err := LessForType[T]()
+	if err != nil {
+		panic(err)
+	}
+	return less
+}
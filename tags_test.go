@@ -0,0 +1,143 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"strings"
+	"testing"
+)
+
+type taggedJob struct {
+	Name     string `heap:"2"`
+	Priority int    `heap:"1,desc"`
+	Weight   float64
+	Seq      uint `heap:"asc"`
+}
+
+func TestLessForType(t *testing.T) {
+	less, err := LessForType[taggedJob]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := []taggedJob{
+		{"b", 1, 0, 2},
+		{"a", 1, 0, 3},
+		{"c", 5, 0, 0},
+		{"a", 1, 0, 1},
+		{"z", 9, 0, 7},
+	}
+	InitFunc(h, less)
+
+	want := []taggedJob{
+		{"z", 9, 0, 7},
+		{"c", 5, 0, 0},
+		{"a", 1, 0, 1},
+		{"a", 1, 0, 3},
+		{"b", 1, 0, 2},
+	}
+	for i, w := range want {
+		if x := PopFunc(&h, less); x != w {
+			t.Errorf("%d.th pop got %v; want %v", i, x, w)
+		}
+	}
+}
+
+func TestLessForTypeNamed(t *testing.T) {
+	// The syntax from the request: a key name and a direction.
+	type task struct {
+		Priority int    `heap:"priority,asc"`
+		Owner    string `heap:"owner,desc"`
+		Seq      int    `heap:"seq,rank=1"`
+	}
+	less, err := LessForType[task]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := []task{{2, "a", 0}, {1, "a", 0}, {1, "b", 0}, {9, "z", -1}}
+	InitFunc(h, less)
+	want := []task{{9, "z", -1}, {1, "b", 0}, {1, "a", 0}, {2, "a", 0}}
+	for i, w := range want {
+		if x := PopFunc(&h, less); x != w {
+			t.Errorf("%d.th pop got %v; want %v", i, x, w)
+		}
+	}
+}
+
+func TestLessForTypeErrors(t *testing.T) {
+	type unexported struct {
+		a int `heap:"1"`
+	}
+	type unordered struct {
+		A []int `heap:"1"`
+	}
+	type badRank struct {
+		A int `heap:"a,rank=first"`
+	}
+	type badOption struct {
+		A int `heap:"1,up"`
+	}
+	type untagged struct {
+		A int
+	}
+	type typoDescending struct {
+		A int `heap:"descending"`
+	}
+	type typoDsc struct {
+		A int `heap:"dsc"`
+	}
+	type typoAscending struct {
+		A int `heap:"Ascending,rank=1"`
+	}
+	type badName struct {
+		A int `heap:"my-key,desc"`
+	}
+
+	for name, f := range map[string]func() error{
+		"int":        func() error { _, err := LessForType[int](); return err },
+		"unexported": func() error { _, err := LessForType[unexported](); return err },
+		"unordered":  func() error { _, err := LessForType[unordered](); return err },
+		"badRank":    func() error { _, err := LessForType[badRank](); return err },
+		"badOption":  func() error { _, err := LessForType[badOption](); return err },
+		"untagged":   func() error { _, err := LessForType[untagged](); return err },
+		"descending": func() error { _, err := LessForType[typoDescending](); return err },
+		"dsc":        func() error { _, err := LessForType[typoDsc](); return err },
+		"Ascending":  func() error { _, err := LessForType[typoAscending](); return err },
+		"badName":    func() error { _, err := LessForType[badName](); return err },
+	} {
+		if err := f(); err == nil {
+			t.Errorf("LessForType[%s] succeeded; want error", name)
+		}
+	}
+}
+
+func TestLessForTypeNoAllocs(t *testing.T) {
+	if debug {
+		t.Skip("debug checks allocate")
+	}
+	less := MustLessForType[taggedJob]()
+	x, y := taggedJob{"a", 1, 0, 2}, taggedJob{"a", 1, 0, 3}
+	if n := testing.AllocsPerRun(100, func() { less(x, y) }); n != 0 {
+		t.Errorf("less allocates %v times per call; want 0", n)
+	}
+}
+
+func TestMustLessForType(t *testing.T) {
+	type job struct {
+		Priority int `heap:"desc"`
+	}
+	less := MustLessForType[job]()
+	if !less(job{2}, job{1}) {
+		t.Error("less(job{2}, job{1}) = false; want true")
+	}
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !strings.HasPrefix(err.Error(), "sliceheap: ") {
+			t.Errorf("MustLessForType[int] panicked with %v; want a sliceheap error", err)
+		}
+	}()
+	MustLessForType[int]()
+	t.Error("MustLessForType[int] did not panic")
+}
@@ -0,0 +1,248 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package taskgraph runs a graph of dependent tasks, such as the steps of a
+// build or an ETL pipeline, dispatching the tasks that are ready in priority
+// order.
+package taskgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/buth/sliceheap"
+)
+
+// A Status describes the progress of a task.
+type Status int
+
+const (
+	Unknown Status = iota // the task has not been added
+	Waiting               // some dependency has not finished
+	Ready                 // queued to run
+	Running
+	Done
+	Failed
+)
+
+var statusNames = [...]string{"unknown", "waiting", "ready", "running", "done", "failed"}
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+	return statusNames[s]
+}
+
+// A DependencyError is the error of a task that was not run because one of
+// its dependencies failed.
+type DependencyError[K comparable] struct {
+	Dep K     // the failed dependency
+	Err error // the error of the dependency
+}
+
+func (e *DependencyError[K]) Error() string {
+	return fmt.Sprintf("taskgraph: dependency %v failed: %v", e.Dep, e.Err)
+}
+
+func (e *DependencyError[K]) Unwrap() error {
+	return e.Err
+}
+
+// A Graph schedules tasks identified by keys of type K. A task becomes
+// ready once all of its dependencies are done, and ready tasks are run with
+// the highest priority first, in the order they became ready among equal
+// priorities. When a task fails, every task depending on it, directly or
+// indirectly, fails with a *DependencyError without being run.
+//
+// Tasks may be added at any time, including by running tasks. A Graph must
+// be created with New.
+type Graph[K comparable] struct {
+	workers int
+
+	mu      sync.Mutex
+	cond    sync.Cond
+	tasks   map[K]*task[K]
+	ready   []*task[K]
+	seq     uint64
+	running int
+	failed  []*task[K] // tasks that failed by their own error, in order
+}
+
+type task[K comparable] struct {
+	id         K
+	priority   int
+	seq        uint64
+	f          func(context.Context) error
+	status     Status
+	err        error
+	waiting    int // unfinished dependencies
+	dependents []*task[K]
+}
+
+func less[K comparable](x, y *task[K]) bool {
+	if x.priority != y.priority {
+		return x.priority > y.priority
+	}
+	return x.seq < y.seq
+}
+
+// New returns an empty graph that runs up to workers tasks at once.
+// It panics if workers < 1.
+func New[K comparable](workers int) *Graph[K] {
+	if workers < 1 {
+		panic(fmt.Sprintf("taskgraph: %d workers < 1", workers))
+	}
+	g := &Graph[K]{workers: workers, tasks: make(map[K]*task[K])}
+	g.cond.L = &g.mu
+	return g
+}
+
+// Add adds a task that runs f once every task in deps is done. The
+// dependencies must already have been added, which keeps the graph acyclic.
+// If one of them has failed, the task fails immediately. Higher priorities
+// run first.
+func (g *Graph[K]) Add(id K, priority int, deps []K, f func(context.Context) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.tasks[id]; ok {
+		return fmt.Errorf("taskgraph: duplicate task %v", id)
+	}
+	t := &task[K]{id: id, priority: priority, f: f, status: Waiting}
+	for _, d := range deps {
+		dt, ok := g.tasks[d]
+		if !ok {
+			return fmt.Errorf("taskgraph: task %v depends on unknown task %v", id, d)
+		}
+		switch dt.status {
+		case Done:
+		case Failed:
+			if t.err == nil {
+				t.err = &DependencyError[K]{Dep: d, Err: dt.err}
+			}
+		default:
+			t.waiting++
+		}
+	}
+	g.tasks[id] = t
+	if t.err != nil {
+		t.status = Failed
+		return nil
+	}
+	for _, d := range deps {
+		if dt := g.tasks[d]; dt.status != Done {
+			dt.dependents = append(dt.dependents, t)
+		}
+	}
+	if t.waiting == 0 {
+		g.push(t)
+	}
+	return nil
+}
+
+func (g *Graph[K]) push(t *task[K]) {
+	t.status = Ready
+	t.seq = g.seq
+	g.seq++
+	sliceheap.PushFunc(&g.ready, t, less)
+	g.cond.Broadcast()
+}
+
+// Status returns the status of the task id and, if it failed, its error.
+func (g *Graph[K]) Status(id K) (Status, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.tasks[id]
+	if !ok {
+		return Unknown, nil
+	}
+	return t.status, t.err
+}
+
+// Run runs tasks until none is ready or running, and returns the errors of
+// the tasks that failed, joined in the order they failed. Failures caused
+// by a failed dependency are not included.
+//
+// If ctx is done, Run stops starting tasks, waits for the running ones to
+// return, and fails the remaining tasks with the context's error. Tasks are
+// passed ctx.
+func (g *Graph[K]) Run(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		g.mu.Lock()
+		g.cond.Broadcast()
+		g.mu.Unlock()
+	})
+	defer stop()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failed = nil
+	for {
+		for ctx.Err() == nil && g.running < g.workers && len(g.ready) > 0 {
+			t := sliceheap.PopFunc(&g.ready, less[K])
+			t.status = Running
+			g.running++
+			go g.run(ctx, t)
+		}
+		if g.running == 0 && (len(g.ready) == 0 || ctx.Err() != nil) {
+			break
+		}
+		g.cond.Wait()
+	}
+
+	if err := ctx.Err(); err != nil {
+		for _, t := range g.tasks {
+			if t.status == Waiting || t.status == Ready {
+				t.status, t.err = Failed, err
+			}
+		}
+		clear(g.ready)
+		g.ready = g.ready[:0]
+	}
+	errs := make([]error, len(g.failed))
+	for i, t := range g.failed {
+		errs[i] = t.err
+	}
+	return errors.Join(errs...)
+}
+
+func (g *Graph[K]) run(ctx context.Context, t *task[K]) {
+	err := t.f(ctx)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running--
+	g.cond.Broadcast()
+	if err != nil {
+		t.status, t.err = Failed, err
+		g.failed = append(g.failed, t)
+		g.propagate(t)
+		return
+	}
+	t.status = Done
+	for _, d := range t.dependents {
+		if d.status != Waiting {
+			continue
+		}
+		if d.waiting--; d.waiting == 0 {
+			g.push(d)
+		}
+	}
+	t.dependents = nil
+}
+
+// propagate fails the dependents of the failed task t.
+func (g *Graph[K]) propagate(t *task[K]) {
+	for _, d := range t.dependents {
+		if d.status != Waiting {
+			continue
+		}
+		d.status = Failed
+		d.err = &DependencyError[K]{Dep: t.id, Err: t.err}
+		g.propagate(d)
+	}
+	t.dependents = nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package taskgraph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *recorder) task(name string, err error) func(context.Context) error {
+	return func(context.Context) error {
+		r.mu.Lock()
+		r.order = append(r.order, name)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+func TestOrder(t *testing.T) {
+	var r recorder
+	g := New[string](1)
+	add := func(id string, priority int, deps ...string) {
+		if err := g.Add(id, priority, deps, r.task(id, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("fetch", 0)
+	add("lint", 1)
+	add("compile", 5, "fetch")
+	add("test", 3, "compile")
+	add("docs", 4, "fetch")
+
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"lint", "fetch", "compile", "docs", "test"}
+	if !slices.Equal(r.order, want) {
+		t.Errorf("ran %v; want %v", r.order, want)
+	}
+	if s, err := g.Status("test"); s != Done || err != nil {
+		t.Errorf("Status(test) = %v, %v; want done, nil", s, err)
+	}
+}
+
+func TestFailure(t *testing.T) {
+	var r recorder
+	boom := errors.New("boom")
+	g := New[string](2)
+	g.Add("a", 0, nil, r.task("a", boom))
+	g.Add("b", 0, []string{"a"}, r.task("b", nil))
+	g.Add("c", 0, []string{"b"}, r.task("c", nil))
+	g.Add("d", 0, nil, r.task("d", nil))
+
+	err := g.Run(context.Background())
+	if !errors.Is(err, boom) {
+		t.Errorf("Run() = %v; want %v", err, boom)
+	}
+	slices.Sort(r.order)
+	if want := []string{"a", "d"}; !slices.Equal(r.order, want) {
+		t.Errorf("ran %v; want %v", r.order, want)
+	}
+	s, err := g.Status("c")
+	var de *DependencyError[string]
+	if s != Failed || !errors.As(err, &de) || de.Dep != "b" || !errors.Is(err, boom) {
+		t.Errorf("Status(c) = %v, %v; want failed on b caused by boom", s, err)
+	}
+
+	// A task added after its dependency failed fails at once.
+	g.Add("e", 0, []string{"a"}, r.task("e", nil))
+	if s, _ := g.Status("e"); s != Failed {
+		t.Errorf("Status(e) = %v; want failed", s)
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	var r recorder
+	g := New[int](3)
+	var spawn func(n int) func(context.Context) error
+	spawn = func(n int) func(context.Context) error {
+		return func(ctx context.Context) error {
+			r.task("", nil)(ctx)
+			if n < 20 {
+				if err := g.Add(n+1, 0, []int{n}, spawn(n+1)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	g.Add(0, 0, nil, spawn(0))
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.order) != 21 {
+		t.Errorf("ran %d tasks; want 21", len(r.order))
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	g := New[string](1)
+	g.Add("a", 0, nil, nil)
+	if err := g.Add("a", 0, nil, nil); err == nil {
+		t.Error("Add of duplicate task succeeded")
+	}
+	if err := g.Add("b", 0, []string{"x"}, nil); err == nil {
+		t.Error("Add with unknown dependency succeeded")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := New[string](1)
+	g.Add("a", 1, nil, func(context.Context) error {
+		cancel()
+		return nil
+	})
+	g.Add("b", 0, nil, func(context.Context) error {
+		t.Error("task b ran after cancellation")
+		return nil
+	})
+	g.Run(ctx)
+	if s, err := g.Status("b"); s != Failed || err != context.Canceled {
+		t.Errorf("Status(b) = %v, %v; want failed, %v", s, err, context.Canceled)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A TopK collects the k greatest elements of a stream. It keeps them in a
+// min-heap of size k, so each element added costs O(log k) and memory use is
+// independent of the length of the stream.
+type TopK[T any] struct {
+	// Hooks observe elements entering and being evicted from the
+	// collection.
+	Hooks Hooks[T]
+
+	k    int
+	less func(x, y T) bool
+	h    []T
+}
+
+// NewTopK returns a collector for the k greatest elements.
+// It panics if k < 0.
+func NewTopK[T cmp.Ordered](k int) *TopK[T] {
+	return NewTopKFunc(k, cmp.Less[T])
+}
+
+// NewTopKFunc is like [NewTopK] but uses a less function to compare elements.
+func NewTopKFunc[T any](k int, less func(x, y T) bool) *TopK[T] {
+	if k < 0 {
+		panic(fmt.Sprintf("sliceheap: TopK size %d < 0", k))
+	}
+	return &TopK[T]{k: k, less: less, h: make([]T, 0, k)}
+}
+
+// Len returns the number of elements collected, which is at most k.
+func (t *TopK[T]) Len() int {
+	return len(t.h)
+}
+
+// Add offers the element x to the collector.
+// The complexity is O(log k).
+func (t *TopK[T]) Add(x T) {
+	if len(t.h) < t.k {
+		PushFunc(&t.h, x, t.less)
+		t.Hooks.push(x)
+	} else if t.k > 0 && t.less(t.h[0], x) {
+		y := t.h[0]
+		t.h[0] = x
+		FixFunc(t.h, 0, t.less)
+		t.Hooks.leave(y, Evicted)
+		t.Hooks.push(x)
+	}
+}
+
+// AddAll offers every element of seq to the collector.
+func (t *TopK[T]) AddAll(seq iter.Seq[T]) {
+	for x := range seq {
+		t.Add(x)
+	}
+}
+
+// Result returns the collected elements, greatest first. Ties are in no
+// particular order. The collector is not modified.
+// The complexity is O(k log k).
+func (t *TopK[T]) Result() []T {
+	return sortedDesc(t.h, t.less)
+}
+
+func sortedDesc[T any](h []T, less func(x, y T) bool) []T {
+	s := slices.Clone(h)
+	slices.SortFunc(s, func(x, y T) int {
+		switch {
+		case less(y, x):
+			return -1
+		case less(x, y):
+			return 1
+		}
+		return 0
+	})
+	return s
+}
+
+// A KeyedTopK collects the k greatest elements of a stream, counting only the
+// greatest element for each key. For example, keyed by endpoint and ordered
+// by latency, it reports the slowest request to each of the k slowest
+// endpoints.
+type KeyedTopK[K comparable, T any] struct {
+	// Hooks observe elements entering, being replaced in, and being
+	// evicted from the collection.
+	Hooks Hooks[T]
+
+	k    int
+	key  func(T) K
+	less func(x, y T) bool
+	h    []T
+	pos  map[K]int // position in h of the element with each key
+}
+
+// NewKeyedTopK returns a collector for the k greatest elements with
+// distinct keys.
+// It panics if k < 0.
+func NewKeyedTopK[K comparable, T cmp.Ordered](k int, key func(T) K) *KeyedTopK[K, T] {
+	return NewKeyedTopKFunc(k, key, cmp.Less[T])
+}
+
+// NewKeyedTopKFunc is like [NewKeyedTopK] but uses a less function to compare elements.
+func NewKeyedTopKFunc[K comparable, T any](k int, key func(T) K, less func(x, y T) bool) *KeyedTopK[K, T] {
+	if k < 0 {
+		panic(fmt.Sprintf("sliceheap: KeyedTopK size %d < 0", k))
+	}
+	return &KeyedTopK[K, T]{k: k, key: key, less: less, h: make([]T, 0, k), pos: make(map[K]int, k)}
+}
+
+// Len returns the number of elements collected, which is at most k.
+func (t *KeyedTopK[K, T]) Len() int {
+	return len(t.h)
+}
+
+// Add offers the element x to the collector. If an element with the same key
+// has already been collected, x replaces it only if it is greater.
+// The complexity is O(log k).
+func (t *KeyedTopK[K, T]) Add(x T) {
+	kx := t.key(x)
+	if i, ok := t.pos[kx]; ok {
+		if y := t.h[i]; t.less(y, x) {
+			t.h[i] = x
+			indexheap.Fix(t.h, i, t.less, t.setIndex)
+			t.Hooks.leave(y, Replaced)
+			t.Hooks.push(x)
+		}
+		return
+	}
+	if len(t.h) < t.k {
+		indexheap.Push(&t.h, x, t.less, t.setIndex)
+		t.Hooks.push(x)
+	} else if t.k > 0 && t.less(t.h[0], x) {
+		y := t.h[0]
+		delete(t.pos, t.key(y))
+		t.h[0] = x
+		t.setIndex(x, 0)
+		indexheap.Fix(t.h, 0, t.less, t.setIndex)
+		t.Hooks.leave(y, Evicted)
+		t.Hooks.push(x)
+	}
+}
+
+func (t *KeyedTopK[K, T]) setIndex(x T, i int) {
+	if i < 0 {
+		delete(t.pos, t.key(x))
+	} else {
+		t.pos[t.key(x)] = i
+	}
+}
+
+// AddAll offers every element of seq to the collector.
+func (t *KeyedTopK[K, T]) AddAll(seq iter.Seq[T]) {
+	for x := range seq {
+		t.Add(x)
+	}
+}
+
+// Result returns the collected elements, greatest first. Ties are in no
+// particular order. The collector is not modified.
+// The complexity is O(k log k).
+func (t *KeyedTopK[K, T]) Result() []T {
+	return sortedDesc(t.h, t.less)
+}
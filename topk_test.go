@@ -0,0 +1,82 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	for _, k := range []int{0, 1, 5, 100} {
+		var in []int
+		for i := 0; i < 50; i++ {
+			in = append(in, rand.Intn(30))
+		}
+
+		tk := NewTopK[int](k)
+		tk.AddAll(slices.Values(in))
+
+		want := slices.Clone(in)
+		slices.Sort(want)
+		slices.Reverse(want)
+		want = want[:min(k, len(want))]
+		if got := tk.Result(); !slices.Equal(got, want) {
+			t.Errorf("k=%d: Result() = %v; want %v", k, got, want)
+		}
+		if tk.Len() != len(want) {
+			t.Errorf("k=%d: Len() = %d; want %d", k, tk.Len(), len(want))
+		}
+	}
+}
+
+func TestKeyedTopK(t *testing.T) {
+	type request struct {
+		endpoint string
+		latency  int
+	}
+	tk := NewKeyedTopKFunc(2, func(r request) string { return r.endpoint },
+		func(x, y request) bool { return x.latency < y.latency })
+
+	for _, r := range []request{
+		{"/a", 10},
+		{"/b", 5},
+		{"/a", 30},
+		{"/c", 20},
+		{"/a", 25},
+		{"/b", 40},
+		{"/c", 1},
+		{"/d", 15},
+	} {
+		tk.Add(r)
+	}
+
+	want := []request{{"/b", 40}, {"/a", 30}}
+	if got := tk.Result(); !slices.Equal(got, want) {
+		t.Errorf("Result() = %v; want %v", got, want)
+	}
+}
+
+func TestKeyedTopKRandom(t *testing.T) {
+	// Keys are x%50, so each key's best is among the larger elements.
+	key := func(x int) int { return x % 50 }
+	tk := NewKeyedTopK(10, key)
+	best := map[int]int{}
+	for range 2000 {
+		x := rand.Intn(10000)
+		tk.Add(x)
+		best[key(x)] = max(best[key(x)], x)
+	}
+	var want []int
+	for _, x := range best {
+		want = append(want, x)
+	}
+	slices.Sort(want)
+	slices.Reverse(want)
+	if got := tk.Result(); !slices.Equal(got, want[:10]) {
+		t.Errorf("Result() = %v; want %v", got, want[:10])
+	}
+}
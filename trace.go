@@ -0,0 +1,292 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// An OpKind is the kind of a traced heap operation.
+type OpKind uint8
+
+const (
+	OpPush   OpKind = iota // Push of Value
+	OpPop                  // Pop
+	OpRemove               // Remove at Index
+	OpFix                  // set the element at Index to Value, then Fix
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpPush:
+		return "Push"
+	case OpPop:
+		return "Pop"
+	case OpRemove:
+		return "Remove"
+	case OpFix:
+		return "Fix"
+	}
+	return fmt.Sprintf("OpKind(%d)", uint8(k))
+}
+
+// A TraceOp is one operation in a trace.
+type TraceOp[T any] struct {
+	Kind  OpKind
+	Index int
+	Value T
+}
+
+// A Trace is a record of heap operations from a starting layout, enough to
+// reproduce every state the heap went through.
+type Trace[T any] struct {
+	Start []T // the heap before the first operation
+	Ops   []TraceOp[T]
+}
+
+// A Recorder performs heap operations on a slice and records them in a
+// Trace. When a long run breaks the heap, for example through a less
+// function that is not a strict weak order, the trace can be saved with
+// [AppendTrace] and passed to [Replay] to find the operation at which it
+// broke. [OrderedHeap.Record] returns a Recorder for an OrderedHeap.
+//
+// The heap must not be changed by other means while it is recorded, since
+// such changes are not in the trace.
+type Recorder[T any] struct {
+	h     *[]T
+	less  func(x, y T) bool
+	trace Trace[T]
+}
+
+// NewRecorder returns a recorder for operations on the heap *h, starting
+// from its current contents.
+func NewRecorder[T cmp.Ordered](h *[]T) *Recorder[T] {
+	return NewRecorderFunc(h, cmp.Less[T])
+}
+
+// NewRecorderFunc is like [NewRecorder] but uses a less function to compare elements.
+func NewRecorderFunc[T any](h *[]T, less func(x, y T) bool) *Recorder[T] {
+	return &Recorder[T]{h: h, less: less, trace: Trace[T]{Start: slices.Clone(*h)}}
+}
+
+// Trace returns the operations recorded so far. The trace shares memory
+// with the recorder and grows as more operations are recorded.
+func (r *Recorder[T]) Trace() Trace[T] {
+	return r.trace
+}
+
+// Len returns the number of elements in the recorded heap.
+func (r *Recorder[T]) Len() int {
+	return len(*r.h)
+}
+
+// Push is like [PushFunc] on the recorded heap.
+func (r *Recorder[T]) Push(x T) {
+	r.trace.Ops = append(r.trace.Ops, TraceOp[T]{Kind: OpPush, Value: x})
+	PushFunc(r.h, x, r.less)
+}
+
+// Pop is like [PopFunc] on the recorded heap.
+func (r *Recorder[T]) Pop() T {
+	r.trace.Ops = append(r.trace.Ops, TraceOp[T]{Kind: OpPop})
+	return PopFunc(r.h, r.less)
+}
+
+// Peek returns the least element of the recorded heap without removing it.
+// Peeking is not recorded. Peek panics if the heap is empty.
+func (r *Recorder[T]) Peek() T {
+	if len(*r.h) == 0 {
+		panic("sliceheap: Peek on empty heap")
+	}
+	return (*r.h)[0]
+}
+
+// Remove is like [RemoveFunc] on the recorded heap.
+func (r *Recorder[T]) Remove(i int) T {
+	r.trace.Ops = append(r.trace.Ops, TraceOp[T]{Kind: OpRemove, Index: i})
+	return RemoveFunc(r.h, i, r.less)
+}
+
+// Update sets the element at index i to x and restores the heap order, as
+// by [FixFunc].
+func (r *Recorder[T]) Update(i int, x T) {
+	r.trace.Ops = append(r.trace.Ops, TraceOp[T]{Kind: OpFix, Index: i, Value: x})
+	(*r.h)[i] = x
+	FixFunc(*r.h, i, r.less)
+}
+
+// A ReplayError reports the first operation of a trace after which the
+// heap invariant did not hold, or which panicked.
+type ReplayError struct {
+	Op  int   // index of the operation in the trace
+	Err error // an *InvariantError, or an error describing the panic
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("sliceheap: replay operation %d: %v", e.Op, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Replay applies the operations of t to a copy of t.Start, verifying the
+// heap after each, and returns the resulting heap. If the invariant fails
+// to hold, it stops and returns the heap as of that operation and a
+// *ReplayError identifying it.
+// The complexity is O(k n) for k operations on a heap of at most n elements.
+func Replay[T cmp.Ordered](t Trace[T]) ([]T, error) {
+	return ReplayFunc(t, cmp.Less[T])
+}
+
+// ReplayFunc is like [Replay] but uses a less function to compare elements.
+func ReplayFunc[T any](t Trace[T], less func(x, y T) bool) (h []T, err error) {
+	h = slices.Clone(t.Start)
+	if err := VerifyFunc(h, less); err != nil {
+		return h, &ReplayError{Op: -1, Err: err}
+	}
+	k := 0
+	defer func() {
+		if v := recover(); v != nil {
+			err = &ReplayError{Op: k, Err: fmt.Errorf("panic: %v", v)}
+		}
+	}()
+	for ; k < len(t.Ops); k++ {
+		op := t.Ops[k]
+		switch op.Kind {
+		case OpPush:
+			PushFunc(&h, op.Value, less)
+		case OpPop:
+			PopFunc(&h, less)
+		case OpRemove:
+			RemoveFunc(&h, op.Index, less)
+		case OpFix:
+			if uint(op.Index) >= uint(len(h)) {
+				panicRange("Fix", op.Index, len(h))
+			}
+			h[op.Index] = op.Value
+			FixFunc(h, op.Index, less)
+		default:
+			panic(fmt.Sprintf("sliceheap: unknown operation %v", op.Kind))
+		}
+		if err := VerifyFunc(h, less); err != nil {
+			return h, &ReplayError{Op: k, Err: err}
+		}
+	}
+	return h, nil
+}
+
+// AppendTrace appends a binary encoding of t to b and returns the extended
+// buffer, so that a trace can be saved to a file and replayed later. Each
+// element is encoded by appendValue. Indices and lengths are varints and
+// an operation's kind is one byte, so a Pop takes one byte and a Push one
+// byte plus its element.
+func AppendTrace[T any](b []byte, t Trace[T], appendValue func(b []byte, x T) []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(t.Start)))
+	for _, x := range t.Start {
+		b = appendValue(b, x)
+	}
+	for _, op := range t.Ops {
+		b = append(b, byte(op.Kind))
+		switch op.Kind {
+		case OpPush:
+			b = appendValue(b, op.Value)
+		case OpRemove:
+			b = binary.AppendUvarint(b, uint64(op.Index))
+		case OpFix:
+			b = binary.AppendUvarint(b, uint64(op.Index))
+			b = appendValue(b, op.Value)
+		}
+	}
+	return b
+}
+
+// DecodeTrace decodes a trace encoded by [AppendTrace]. Each element is
+// decoded by decodeValue, which returns the element at the start of b and
+// the number of bytes it occupied.
+func DecodeTrace[T any](b []byte, decodeValue func(b []byte) (x T, n int, err error)) (Trace[T], error) {
+	var t Trace[T]
+	d := traceDecoder[T]{b: b, value: decodeValue}
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(b)) {
+		d.fail("start length %d exceeds input", n)
+	}
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		t.Start = append(t.Start, d.decodeValue())
+	}
+	for len(d.b) > 0 && d.err == nil {
+		op := TraceOp[T]{Kind: OpKind(d.b[0])}
+		d.b = d.b[1:]
+		switch op.Kind {
+		case OpPush:
+			op.Value = d.decodeValue()
+		case OpPop:
+		case OpRemove:
+			op.Index = d.index()
+		case OpFix:
+			op.Index = d.index()
+			op.Value = d.decodeValue()
+		default:
+			d.fail("unknown operation %v", op.Kind)
+		}
+		t.Ops = append(t.Ops, op)
+	}
+	if d.err != nil {
+		return Trace[T]{}, d.err
+	}
+	return t, nil
+}
+
+// A traceDecoder decodes a trace, recording the first error.
+type traceDecoder[T any] struct {
+	b     []byte
+	value func(b []byte) (T, int, error)
+	err   error
+}
+
+func (d *traceDecoder[T]) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("sliceheap: DecodeTrace: "+format, args...)
+	}
+}
+
+func (d *traceDecoder[T]) uvarint() uint64 {
+	x, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail("invalid varint")
+		return 0
+	}
+	d.b = d.b[n:]
+	return x
+}
+
+func (d *traceDecoder[T]) index() int {
+	i := d.uvarint()
+	if i > math.MaxInt {
+		d.fail("index %d out of range", i)
+	}
+	return int(i)
+}
+
+func (d *traceDecoder[T]) decodeValue() T {
+	var x T
+	if d.err != nil {
+		return x
+	}
+	x, n, err := d.value(d.b)
+	switch {
+	case err != nil:
+		d.fail("%w", err)
+	case n <= 0 || n > len(d.b):
+		d.fail("element length %d out of range", n)
+	default:
+		d.b = d.b[n:]
+	}
+	return x
+}
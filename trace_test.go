@@ -0,0 +1,131 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	h := []int{4, 8, 6}
+	r := NewRecorder(&h)
+	for _, x := range []int{3, 9, 1, 7} {
+		r.Push(x)
+	}
+	r.Pop()
+	r.Remove(2)
+	r.Update(0, 10)
+
+	tr := r.Trace()
+	if len(tr.Ops) != 7 || tr.Ops[5].Kind != OpRemove || tr.Ops[6].Kind.String() != "Fix" {
+		t.Fatalf("trace = %+v", tr.Ops)
+	}
+	got, err := Replay(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, h) {
+		t.Errorf("Replay = %v; want the recorded heap %v", got, h)
+	}
+}
+
+func TestReplayFindsBreak(t *testing.T) {
+	if debug {
+		t.Skip("debug checks panic at the break")
+	}
+	// Not irreflexive, so equal elements break the heap.
+	less := func(x, y int) bool { return x <= y }
+	var h []int
+	r := NewRecorderFunc(&h, less)
+	broke := -1
+	for k := 0; broke < 0 && k < 1000; k++ {
+		if len(h) > 0 && k%3 == 2 {
+			r.Pop()
+		} else {
+			r.Push(k * 7 % 10)
+		}
+		if VerifyFunc(h, less) != nil {
+			broke = k
+		}
+	}
+	if broke < 0 {
+		t.Fatal("heap did not break")
+	}
+
+	_, err := ReplayFunc(r.Trace(), less)
+	var re *ReplayError
+	var ie *InvariantError
+	if !errors.As(err, &re) || !errors.As(err, &ie) || re.Op != broke {
+		t.Errorf("ReplayFunc = %v; want an invariant error at operation %d", err, broke)
+	}
+}
+
+func TestOrderedHeapRecord(t *testing.T) {
+	h := OrderedHeap[int]{2, 5, 3}
+	r := h.Record()
+	r.Push(1)
+	r.Push(4)
+	if got := r.Pop(); got != 1 {
+		t.Fatalf("Pop = %d; want 1", got)
+	}
+	if h.Len() != 4 {
+		t.Fatalf("heap has %d elements; want 4", h.Len())
+	}
+	got, err := Replay(r.Trace())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, h) {
+		t.Errorf("Replay = %v; want the recorded heap %v", got, h)
+	}
+}
+
+func appendInt(b []byte, x int) []byte { return binary.AppendVarint(b, int64(x)) }
+
+func decodeInt(b []byte) (int, int, error) {
+	x, n := binary.Varint(b)
+	if n <= 0 {
+		return 0, 0, errors.New("bad varint")
+	}
+	return int(x), n, nil
+}
+
+func TestTraceEncoding(t *testing.T) {
+	h := []int{4, 8, 6}
+	r := NewRecorder(&h)
+	for _, x := range []int{3, 900, -1, 7} {
+		r.Push(x)
+	}
+	r.Pop()
+	r.Remove(2)
+	r.Update(0, 10)
+	tr := r.Trace()
+
+	b := AppendTrace(nil, tr, appendInt)
+	got, err := DecodeTrace(b, decodeInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Start, tr.Start) || !slices.Equal(got.Ops, tr.Ops) {
+		t.Fatalf("DecodeTrace = %+v; want %+v", got, tr)
+	}
+	replayed, err := Replay(got)
+	if err != nil || !slices.Equal(replayed, h) {
+		t.Errorf("Replay = %v, %v; want %v", replayed, err, h)
+	}
+
+	if _, err := DecodeTrace(b[:len(b)-1], decodeInt); err == nil {
+		t.Error("DecodeTrace accepted a truncated element")
+	}
+	if _, err := DecodeTrace(append(b, 9), decodeInt); err == nil {
+		t.Error("DecodeTrace accepted an unknown operation")
+	}
+	if _, err := DecodeTrace([]byte{0xff}, decodeInt); err == nil {
+		t.Error("DecodeTrace accepted a truncated length")
+	}
+}
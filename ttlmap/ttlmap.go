@@ -0,0 +1,154 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ttlmap provides a map whose entries expire after a time-to-live.
+package ttlmap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap"
+	"github.com/buth/sliceheap/clock"
+)
+
+// A Map is a map from K to V whose entries expire once their time-to-live
+// has elapsed. Expired entries are never returned. They are removed from
+// memory as the map is used, and by Sweep, which can be run periodically by
+// RunSweeper to reclaim entries that are no longer accessed.
+//
+// The zero value is an empty map ready to use. A Map is safe for concurrent
+// use by multiple goroutines.
+type Map[K comparable, V any] struct {
+	// Clock is the source of time. If nil, clock.Real is used. It must
+	// not be changed after the map is first used.
+	Clock clock.Clock
+
+	mu sync.Mutex
+	m  map[K]entry[V]
+	h  []deadline[K] // min-heap of expiry times
+}
+
+type entry[V any] struct {
+	v   V
+	exp time.Time
+}
+
+// A deadline records when an entry expires. A deadline is stale if its key
+// has since been deleted or set again with a different expiry.
+type deadline[K comparable] struct {
+	exp time.Time
+	k   K
+}
+
+func less[K comparable](x, y deadline[K]) bool {
+	return x.exp.Before(y.exp)
+}
+
+// Set sets the value for k to v, expiring after ttl.
+// The complexity is amortized O(log n) where n = m.Len().
+func (m *Map[K, V]) Set(k K, v V, ttl time.Duration) {
+	now := m.clock().Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = make(map[K]entry[V])
+	}
+	m.expire(now)
+	exp := now.Add(ttl)
+	m.m[k] = entry[V]{v, exp}
+	sliceheap.PushFunc(&m.h, deadline[K]{exp, k}, less)
+	if len(m.h) > 2*len(m.m)+16 {
+		m.compact()
+	}
+}
+
+// Get returns the value for k and whether it was present and unexpired.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	now := m.clock().Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(now)
+	e, ok := m.m[k]
+	if !ok || !now.Before(e.exp) {
+		var zero V
+		return zero, false
+	}
+	return e.v, true
+}
+
+// Delete deletes the entry for k, if any.
+func (m *Map[K, V]) Delete(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.m, k)
+}
+
+// Len returns the number of unexpired entries in the map.
+func (m *Map[K, V]) Len() int {
+	now := m.clock().Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(now)
+	return len(m.m)
+}
+
+// Sweep removes all entries that have expired by now and returns the number
+// removed.
+func (m *Map[K, V]) Sweep() int {
+	now := m.clock().Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.expire(now)
+}
+
+// RunSweeper calls Sweep every interval until ctx is done.
+func (m *Map[K, V]) RunSweeper(ctx context.Context, interval time.Duration) {
+	t := m.clock().NewTimer(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C():
+			m.Sweep()
+			t.Reset(interval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Map[K, V]) clock() clock.Clock {
+	if m.Clock == nil {
+		return clock.Real
+	}
+	return m.Clock
+}
+
+// expire pops every deadline that has passed by now, deleting the entries
+// that are still current, and returns the number deleted.
+func (m *Map[K, V]) expire(now time.Time) int {
+	n := 0
+	for len(m.h) > 0 && !now.Before(m.h[0].exp) {
+		d := sliceheap.PopFunc(&m.h, less)
+		if e, ok := m.m[d.k]; ok && e.exp.Equal(d.exp) {
+			delete(m.m, d.k)
+			n++
+		}
+	}
+	return n
+}
+
+// compact removes stale deadlines from the heap.
+func (m *Map[K, V]) compact() {
+	h := m.h[:0]
+	for _, d := range m.h {
+		if e, ok := m.m[d.k]; ok && e.exp.Equal(d.exp) {
+			h = append(h, d)
+		}
+	}
+	clear(m.h[len(h):])
+	sliceheap.InitFunc(h, less)
+	m.h = h
+}
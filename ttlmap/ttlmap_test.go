@@ -0,0 +1,100 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ttlmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+)
+
+func TestMap(t *testing.T) {
+	var m Map[string, int]
+	m.Set("short", 1, 20*time.Millisecond)
+	m.Set("long", 2, time.Hour)
+	m.Set("reset", 3, 20*time.Millisecond)
+	m.Set("reset", 4, time.Hour) // extends the ttl
+	m.Set("deleted", 5, time.Hour)
+	m.Delete("deleted")
+
+	if v, ok := m.Get("short"); !ok || v != 1 {
+		t.Errorf("Get(short) = %d, %v; want 1, true", v, ok)
+	}
+	if n := m.Len(); n != 3 {
+		t.Errorf("Len() = %d; want 3", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := m.Get("short"); ok {
+		t.Error("Get(short) found expired entry")
+	}
+	if v, ok := m.Get("reset"); !ok || v != 4 {
+		t.Errorf("Get(reset) = %d, %v; want 4, true", v, ok)
+	}
+	if _, ok := m.Get("deleted"); ok {
+		t.Error("Get(deleted) found deleted entry")
+	}
+	if n := m.Len(); n != 2 {
+		t.Errorf("Len() = %d; want 2", n)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	var m Map[int, int]
+	for i := 0; i < 1000; i++ {
+		m.Set(i%10, i, time.Hour)
+	}
+	if len(m.h) > 2*len(m.m)+16 {
+		t.Errorf("heap holds %d deadlines for %d entries", len(m.h), len(m.m))
+	}
+	if v, ok := m.Get(3); !ok || v != 993 {
+		t.Errorf("Get(3) = %d, %v; want 993, true", v, ok)
+	}
+}
+
+func TestSweeper(t *testing.T) {
+	var m Map[int, int]
+	for i := 0; i < 10; i++ {
+		m.Set(i, i, 10*time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.RunSweeper(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	m.mu.Lock()
+	n := len(m.m)
+	m.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d entries remain after sweeping", n)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := &Map[string, int]{Clock: c}
+	m.Set("a", 1, time.Minute)
+	m.Set("b", 2, time.Hour)
+
+	c.Advance(time.Minute - time.Nanosecond)
+	if _, ok := m.Get("a"); !ok {
+		t.Error("entry expired before its ttl")
+	}
+	c.Advance(time.Nanosecond)
+	if _, ok := m.Get("a"); ok {
+		t.Error("entry present at its ttl")
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", m.Len())
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"errors"
+
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// An UpdatableQueue is a priority queue of items addressed by an ID, also
+// known as a priority map. Each ID appears at most once; pushing an item for
+// an ID already present replaces its item and restores the order. A map from
+// ID to heap index, kept up to date as items move, makes every operation
+// O(log n).
+//
+// An UpdatableQueue must be created with NewUpdatableQueue or
+// NewUpdatableQueueFunc; the zero value panics on use.
+type UpdatableQueue[ID comparable, T any] struct {
+	// Hooks observe items entering and leaving the queue. An item
+	// replaced by PushOrUpdate, DecreaseKey or IncreaseKey leaves with
+	// reason Replaced.
+	Hooks Hooks[T]
+
+	less  func(x, y T) bool
+	h     []idItem[ID, T]
+	index map[ID]int
+}
+
+type idItem[ID comparable, T any] struct {
+	id   ID
+	item T
+}
+
+// NewUpdatableQueue returns an empty queue that pops the least item first.
+func NewUpdatableQueue[ID comparable, T cmp.Ordered]() *UpdatableQueue[ID, T] {
+	return NewUpdatableQueueFunc[ID](cmp.Less[T])
+}
+
+// NewUpdatableQueueFunc is like [NewUpdatableQueue] but uses a less function to compare elements.
+func NewUpdatableQueueFunc[ID comparable, T any](less func(x, y T) bool) *UpdatableQueue[ID, T] {
+	return &UpdatableQueue[ID, T]{less: less, index: make(map[ID]int)}
+}
+
+func (q *UpdatableQueue[ID, T]) lessItem(x, y idItem[ID, T]) bool {
+	return q.less(x.item, y.item)
+}
+
+func (q *UpdatableQueue[ID, T]) setIndex(x idItem[ID, T], i int) {
+	if i < 0 {
+		delete(q.index, x.id)
+		return
+	}
+	q.index[x.id] = i
+}
+
+// Len returns the number of items in the queue.
+func (q *UpdatableQueue[ID, T]) Len() int {
+	return len(q.h)
+}
+
+// PushOrUpdate sets the item for id, inserting it if id is not present.
+// The complexity is O(log n) where n = q.Len().
+func (q *UpdatableQueue[ID, T]) PushOrUpdate(id ID, item T) {
+	if i, ok := q.index[id]; ok {
+		old := q.h[i].item
+		q.h[i].item = item
+		indexheap.Fix(q.h, i, q.lessItem, q.setIndex)
+		q.Hooks.leave(old, Replaced)
+		q.Hooks.push(item)
+		return
+	}
+	indexheap.Push(&q.h, idItem[ID, T]{id, item}, q.lessItem, q.setIndex)
+	q.Hooks.push(item)
+}
+
+// ErrNoID is returned by [UpdatableQueue.DecreaseKey] and
+// [UpdatableQueue.IncreaseKey] when the ID is not in the queue.
+var ErrNoID = errors.New("sliceheap: ID not in queue")
+
+// DecreaseKey sets the item for id to item, which must not order after the
+// current item, and moves it toward the top of the queue. Unlike
+// PushOrUpdate, it only sifts up. If item orders after the current item,
+// DecreaseKey returns [ErrKeyDirection]; if id is not present, it returns
+// [ErrNoID]. In either case it changes nothing.
+// The complexity is O(log n) where n = q.Len().
+func (q *UpdatableQueue[ID, T]) DecreaseKey(id ID, item T) error {
+	i, ok := q.index[id]
+	if !ok {
+		return ErrNoID
+	}
+	old := q.h[i].item
+	if q.less(old, item) {
+		return ErrKeyDirection
+	}
+	q.h[i].item = item
+	indexheap.Up(q.h, i, q.lessItem, q.setIndex)
+	q.Hooks.leave(old, Replaced)
+	q.Hooks.push(item)
+	return nil
+}
+
+// IncreaseKey sets the item for id to item, which must not order before
+// the current item, and moves it toward the bottom of the queue. Unlike
+// PushOrUpdate, it only sifts down. If item orders before the current
+// item, IncreaseKey returns [ErrKeyDirection]; if id is not present, it
+// returns [ErrNoID]. In either case it changes nothing.
+// The complexity is O(log n) where n = q.Len().
+func (q *UpdatableQueue[ID, T]) IncreaseKey(id ID, item T) error {
+	i, ok := q.index[id]
+	if !ok {
+		return ErrNoID
+	}
+	old := q.h[i].item
+	if q.less(item, old) {
+		return ErrKeyDirection
+	}
+	q.h[i].item = item
+	indexheap.Down(q.h, i, q.lessItem, q.setIndex)
+	q.Hooks.leave(old, Replaced)
+	q.Hooks.push(item)
+	return nil
+}
+
+// Contains reports whether id is present.
+func (q *UpdatableQueue[ID, T]) Contains(id ID) bool {
+	_, ok := q.index[id]
+	return ok
+}
+
+// Get returns the item for id and whether it is present.
+func (q *UpdatableQueue[ID, T]) Get(id ID) (T, bool) {
+	i, ok := q.index[id]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return q.h[i].item, true
+}
+
+// Remove removes id and returns its item, and reports whether it was
+// present.
+// The complexity is O(log n) where n = q.Len().
+func (q *UpdatableQueue[ID, T]) Remove(id ID) (T, bool) {
+	i, ok := q.index[id]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	x := indexheap.Remove(&q.h, i, q.lessItem, q.setIndex).item
+	q.Hooks.leave(x, Removed)
+	return x, true
+}
+
+// Peek returns the least item and its ID without removing them, and
+// reports false if the queue is empty.
+// The complexity is O(1).
+func (q *UpdatableQueue[ID, T]) Peek() (ID, T, bool) {
+	if len(q.h) == 0 {
+		var zero idItem[ID, T]
+		return zero.id, zero.item, false
+	}
+	return q.h[0].id, q.h[0].item, true
+}
+
+// Pop removes and returns the least item and its ID, and reports false if
+// the queue is empty.
+// The complexity is O(log n) where n = q.Len().
+func (q *UpdatableQueue[ID, T]) Pop() (ID, T, bool) {
+	if len(q.h) == 0 {
+		var zero idItem[ID, T]
+		return zero.id, zero.item, false
+	}
+	x := indexheap.Pop(&q.h, q.lessItem, q.setIndex)
+	q.Hooks.leave(x.item, Popped)
+	return x.id, x.item, true
+}
@@ -0,0 +1,124 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUpdatableQueue(t *testing.T) {
+	q := NewUpdatableQueue[string, int]()
+	if _, _, ok := q.Pop(); ok {
+		t.Fatal("Pop of empty queue succeeded")
+	}
+	q.PushOrUpdate("a", 5)
+	q.PushOrUpdate("b", 3)
+	q.PushOrUpdate("c", 8)
+	q.PushOrUpdate("a", 1) // reprioritize
+	q.PushOrUpdate("d", 4)
+
+	if q.Len() != 4 || !q.Contains("c") || q.Contains("z") {
+		t.Fatalf("Len() = %d; want 4 with c present and z absent", q.Len())
+	}
+	if x, ok := q.Get("a"); !ok || x != 1 {
+		t.Errorf("Get(a) = %d, %v; want 1, true", x, ok)
+	}
+	if x, ok := q.Remove("b"); !ok || x != 3 {
+		t.Errorf("Remove(b) = %d, %v; want 3, true", x, ok)
+	}
+	if _, ok := q.Remove("b"); ok || q.Contains("b") {
+		t.Error("b still present after Remove")
+	}
+	if id, x, _ := q.Peek(); id != "a" || x != 1 {
+		t.Errorf("Peek() = %s, %d; want a, 1", id, x)
+	}
+
+	var got string
+	for q.Len() > 0 {
+		id, _, _ := q.Pop()
+		got += id
+	}
+	if got != "adc" {
+		t.Errorf("popped %q; want \"adc\"", got)
+	}
+}
+
+func TestUpdatableQueueRandom(t *testing.T) {
+	q := NewUpdatableQueueFunc[int](func(x, y int) bool { return x > y })
+	model := make(map[int]int)
+	for range 2000 {
+		id := rand.Intn(50)
+		switch rand.Intn(3) {
+		case 0, 1:
+			x := rand.Intn(1000)
+			q.PushOrUpdate(id, x)
+			model[id] = x
+		case 2:
+			x, ok := q.Remove(id)
+			if want, wok := model[id]; ok != wok || x != want {
+				t.Fatalf("Remove(%d) = %d, %v; want %d, %v", id, x, ok, want, wok)
+			}
+			delete(model, id)
+		}
+		if err := VerifyFunc(q.h, q.lessItem); err != nil {
+			t.Fatal(err)
+		}
+		for i, e := range q.h {
+			if q.index[e.id] != i {
+				t.Fatalf("index[%d] = %d; want %d", e.id, q.index[e.id], i)
+			}
+		}
+	}
+	if q.Len() != len(model) {
+		t.Fatalf("Len() = %d; want %d", q.Len(), len(model))
+	}
+	prev := 1000
+	for q.Len() > 0 {
+		id, x, _ := q.Pop()
+		if x > prev || model[id] != x {
+			t.Fatalf("Pop() = %d, %d out of order or wrong", id, x)
+		}
+		prev = x
+	}
+}
+
+func TestUpdatableQueueChangeKey(t *testing.T) {
+	q := NewUpdatableQueue[string, int]()
+	for i, id := range []string{"a", "b", "c", "d"} {
+		q.PushOrUpdate(id, 10*(i+1))
+	}
+	if err := q.DecreaseKey("c", 35); err != ErrKeyDirection {
+		t.Errorf("DecreaseKey to a larger item = %v; want ErrKeyDirection", err)
+	}
+	if err := q.IncreaseKey("b", 15); err != ErrKeyDirection {
+		t.Errorf("IncreaseKey to a smaller item = %v; want ErrKeyDirection", err)
+	}
+	if err := q.DecreaseKey("e", 1); err != ErrNoID {
+		t.Errorf("DecreaseKey of a missing ID = %v; want ErrNoID", err)
+	}
+	if err := q.IncreaseKey("e", 1); err != ErrNoID {
+		t.Errorf("IncreaseKey of a missing ID = %v; want ErrNoID", err)
+	}
+	if x, _ := q.Get("c"); x != 30 {
+		t.Errorf("failed DecreaseKey changed c to %d", x)
+	}
+
+	if err := q.DecreaseKey("d", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.IncreaseKey("a", 25); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for q.Len() > 0 {
+		id, _, _ := q.Pop()
+		got = append(got, id)
+	}
+	if want := "d b a c"; strings.Join(got, " ") != want {
+		t.Errorf("popped %v; want %s", got, want)
+	}
+}
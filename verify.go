@@ -0,0 +1,41 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// An InvariantError describes a violation of the heap invariant: a child
+// that is less than its parent.
+type InvariantError struct {
+	Parent, Child           int // indices of the offending elements
+	ParentValue, ChildValue any
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("sliceheap: heap invariant violated: [%d] = %v > [%d] = %v",
+		e.Parent, e.ParentValue, e.Child, e.ChildValue)
+}
+
+// Verify checks that h satisfies the heap invariants, and returns an
+// *InvariantError describing the first violation, in index order, if it
+// does not. It is intended for tests and debugging assertions.
+// The complexity is O(n) where n = len(h).
+func Verify[T cmp.Ordered](h []T) error {
+	return VerifyFunc(h, cmp.Less[T])
+}
+
+// VerifyFunc is like [Verify] but uses a less function to compare elements.
+func VerifyFunc[T any](h []T, less func(x, y T) bool) error {
+	for j := 1; j < len(h); j++ {
+		i := (j - 1) / 2 // parent
+		if less(h[j], h[i]) {
+			return &InvariantError{Parent: i, Child: j, ParentValue: h[i], ChildValue: h[j]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	if err := Verify([]int(nil)); err != nil {
+		t.Errorf("Verify(nil) = %v", err)
+	}
+	if err := Verify([]int{1, 2, 3, 4, 5}); err != nil {
+		t.Errorf("Verify of sorted slice = %v", err)
+	}
+
+	err := Verify([]int{1, 2, 3, 4, 0})
+	var ie *InvariantError
+	if !errors.As(err, &ie) {
+		t.Fatalf("Verify of invalid heap = %v; want *InvariantError", err)
+	}
+	if ie.Parent != 1 || ie.Child != 4 || ie.ParentValue != 2 || ie.ChildValue != 0 {
+		t.Errorf("Verify = %+v; want parent [1] = 2, child [4] = 0", *ie)
+	}
+	want := "sliceheap: heap invariant violated: [1] = 2 > [4] = 0"
+	if err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+
+	// A max-heap is valid with the reversed order.
+	greater := func(x, y int) bool { return x > y }
+	if err := VerifyFunc([]int{5, 4, 3, 2, 1}, greater); err != nil {
+		t.Errorf("VerifyFunc of max-heap = %v", err)
+	}
+}
+
+func TestVerifyLarge(t *testing.T) {
+	h := make([]int, 1<<20)
+	for i := range h {
+		h[i] = i
+	}
+	if err := Verify(h); err != nil {
+		t.Fatal(err)
+	}
+	h[len(h)-1] = -1
+	if err := Verify(h); err == nil {
+		t.Error("Verify missed violation at last element")
+	}
+}
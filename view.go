@@ -0,0 +1,113 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A View gives read-only access to a heap. The owner of a heap can hand out
+// a View to let other components inspect it without being able to break
+// its invariants. A View refers to the owner's slice, so it reflects later
+// changes to the heap, and must not be used concurrently with them.
+type View[T any] struct {
+	h    *[]T
+	less func(x, y T) bool
+}
+
+// NewView returns a view of the heap *h.
+func NewView[T cmp.Ordered](h *[]T) View[T] {
+	return NewViewFunc(h, cmp.Less[T])
+}
+
+// NewViewFunc is like [NewView] but uses a less function to compare elements.
+func NewViewFunc[T any](h *[]T, less func(x, y T) bool) View[T] {
+	return View[T]{h, less}
+}
+
+// Len returns the number of elements in the heap.
+func (v View[T]) Len() int {
+	return len(*v.h)
+}
+
+// Peek returns the minimum element of the heap, and reports false if the
+// heap is empty.
+// The complexity is O(1).
+func (v View[T]) Peek() (T, bool) {
+	if len(*v.h) == 0 {
+		var zero T
+		return zero, false
+	}
+	return (*v.h)[0], true
+}
+
+// Ascend returns an iterator over the elements of the heap in ascending
+// order. The heap is read when iteration starts, not when Ascend is
+// called, and is not modified.
+// The complexity is O(k log k) to yield k elements.
+func (v View[T]) Ascend() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ascend(*v.h, v.less)(yield)
+	}
+}
+
+// ascend returns an iterator over the elements of the heap h in ascending
+// order, without modifying h.
+func ascend[T any](h []T, less func(x, y T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if len(h) == 0 {
+			return
+		}
+		// The next element is always the least among the children of
+		// those already yielded.
+		lessIndex := func(i, j int) bool { return less(h[i], h[j]) }
+		next := []int{0}
+		for len(next) > 0 {
+			i := PopFunc(&next, lessIndex)
+			if !yield(h[i]) {
+				return
+			}
+			for _, c := range [2]int{2*i + 1, 2*i + 2} {
+				if c < len(h) {
+					PushFunc(&next, c, lessIndex)
+				}
+			}
+		}
+	}
+}
+
+// Contains reports whether the heap holds an element equal to x, meaning
+// that neither is less than the other. Subtrees whose roots are greater
+// than x are skipped.
+// The complexity is O(n) where n = v.Len(), and less when x is small.
+func (v View[T]) Contains(x T) bool {
+	h := *v.h
+	if len(h) == 0 {
+		return false
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if v.less(x, h[i]) {
+			continue
+		}
+		if !v.less(h[i], x) {
+			return true
+		}
+		for _, c := range [2]int{2*i + 1, 2*i + 2} {
+			if c < len(h) {
+				stack = append(stack, c)
+			}
+		}
+	}
+	return false
+}
+
+// Verify is like [VerifyFunc] for the viewed heap.
+func (v View[T]) Verify() error {
+	return VerifyFunc(*v.h, v.less)
+}
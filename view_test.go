@@ -0,0 +1,73 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sliceheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	var h []int
+	v := NewView(&h)
+	if _, ok := v.Peek(); ok || v.Len() != 0 || v.Contains(0) {
+		t.Fatal("view of empty heap is not empty")
+	}
+	if got := slices.Collect(v.Ascend()); len(got) != 0 {
+		t.Fatalf("Ascend of empty heap = %v", got)
+	}
+
+	var want []int
+	for range 200 {
+		x := rand.Intn(100) * 2
+		Push(&h, x)
+		want = append(want, x)
+	}
+	slices.Sort(want)
+	before := slices.Clone(h)
+
+	if got, _ := v.Peek(); got != want[0] || v.Len() != len(want) {
+		t.Errorf("Peek() = %d, Len() = %d; want %d, %d", got, v.Len(), want[0], len(want))
+	}
+	if got := slices.Collect(v.Ascend()); !slices.Equal(got, want) {
+		t.Errorf("Ascend() = %v; want %v", got, want)
+	}
+	for x := -1; x <= 200; x++ {
+		if got := v.Contains(x); got != slices.Contains(want, x) {
+			t.Errorf("Contains(%d) = %v", x, got)
+		}
+	}
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(h, before) {
+		t.Error("view modified the heap")
+	}
+
+	// The view reflects changes made by the owner.
+	Push(&h, -5)
+	if got, _ := v.Peek(); got != -5 {
+		t.Errorf("Peek() after Push = %d; want -5", got)
+	}
+	for x := range v.Ascend() {
+		if x != -5 {
+			t.Errorf("first of Ascend() = %d; want -5", x)
+		}
+		break
+	}
+}
+
+func TestViewAscendLate(t *testing.T) {
+	h := []int{3}
+	seq := NewView(&h).Ascend()
+	// Pushing may move the heap to a new backing array.
+	for _, x := range []int{5, 1, 4, 2} {
+		Push(&h, x)
+	}
+	if got := slices.Collect(seq); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Ascend created before the pushes yielded %v", got)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package watchdog detects operations that fail to complete by their
+// deadlines.
+package watchdog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+	"github.com/buth/sliceheap/internal/indexheap"
+)
+
+// A Watchdog tracks outstanding operations, each with a deadline by which it
+// must be acknowledged, and calls a function for those that are overdue.
+// All deadlines are kept in a single heap served by one goroutine and one
+// timer, so tracking many thousands of operations, such as in-flight RPCs,
+// costs far less than a timer per operation.
+//
+// A Watchdog must be created with New. It is safe for concurrent use by
+// multiple goroutines.
+type Watchdog[T any] struct {
+	// Clock is the source of time. If nil, clock.Real is used. It must
+	// not be changed after the first call to Watch.
+	Clock clock.Clock
+
+	overdue func(T)
+	wake    chan struct{}
+	done    chan struct{}
+	stopped sync.WaitGroup
+
+	mu sync.Mutex
+	h  []*Op[T]
+}
+
+// An Op is an operation being watched.
+type Op[T any] struct {
+	w        *Watchdog[T]
+	x        T
+	deadline time.Time
+	index    int // index in the heap, or -1 once acknowledged or overdue
+}
+
+func less[T any](x, y *Op[T]) bool {
+	return x.deadline.Before(y.deadline)
+}
+
+func setIndex[T any](op *Op[T], i int) {
+	op.index = i
+}
+
+// New returns a watchdog that calls overdue, from its own goroutine, with
+// each operation not acknowledged by its deadline. The caller must call
+// Stop to release the goroutine.
+func New[T any](overdue func(T)) *Watchdog[T] {
+	w := &Watchdog[T]{
+		overdue: overdue,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	w.stopped.Add(1)
+	go w.run()
+	return w
+}
+
+// Watch starts watching the operation x, which must be acknowledged by
+// deadline.
+// The complexity is O(log n) where n = w.Len().
+func (w *Watchdog[T]) Watch(x T, deadline time.Time) *Op[T] {
+	op := &Op[T]{w: w, x: x, deadline: deadline}
+	w.mu.Lock()
+	indexheap.Push(&w.h, op, less, setIndex)
+	first := op.index == 0
+	w.mu.Unlock()
+	if first {
+		w.poke()
+	}
+	return op
+}
+
+// Len returns the number of operations being watched.
+func (w *Watchdog[T]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.h)
+}
+
+// Stop stops the watchdog. No more operations are reported overdue after
+// Stop returns.
+func (w *Watchdog[T]) Stop() {
+	close(w.done)
+	w.stopped.Wait()
+}
+
+// poke wakes the goroutine to reconsider the earliest deadline.
+func (w *Watchdog[T]) poke() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watchdog[T]) clock() clock.Clock {
+	if w.Clock == nil {
+		return clock.Real
+	}
+	return w.Clock
+}
+
+func (w *Watchdog[T]) run() {
+	defer w.stopped.Done()
+	// Nothing is due before the first Watch, which may follow setting
+	// Clock.
+	select {
+	case <-w.wake:
+	case <-w.done:
+		return
+	}
+	clk := w.clock()
+	t := clk.NewTimer(0)
+	t.Stop()
+	var due []T
+	for {
+		w.mu.Lock()
+		now := clk.Now()
+		for len(w.h) > 0 && !w.h[0].deadline.After(now) {
+			due = append(due, indexheap.Pop(&w.h, less, setIndex).x)
+		}
+		next := time.Duration(-1)
+		if len(w.h) > 0 {
+			next = w.h[0].deadline.Sub(now)
+		}
+		w.mu.Unlock()
+
+		for i, x := range due {
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			w.overdue(x)
+			var zero T
+			due[i] = zero
+		}
+		due = due[:0]
+
+		if next >= 0 {
+			t.Reset(next)
+		}
+		select {
+		case <-t.C():
+		case <-w.wake:
+			t.Stop()
+		case <-w.done:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// Ack acknowledges the operation, which is no longer watched. It reports
+// whether the operation was acknowledged in time; if not, it has been or is
+// being reported overdue.
+// The complexity is O(log n) where n = w.Len().
+func (op *Op[T]) Ack() bool {
+	w := op.w
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if op.index < 0 {
+		return false
+	}
+	indexheap.Remove(&w.h, op.index, less, setIndex)
+	return true
+}
+
+// Extend moves the deadline of the operation, which must still be
+// outstanding, and reports whether it was.
+// The complexity is O(log n) where n = w.Len().
+func (op *Op[T]) Extend(deadline time.Time) bool {
+	w := op.w
+	w.mu.Lock()
+	if op.index < 0 {
+		w.mu.Unlock()
+		return false
+	}
+	op.deadline = deadline
+	indexheap.Fix(w.h, op.index, less, setIndex)
+	first := op.index == 0
+	w.mu.Unlock()
+	if first {
+		w.poke()
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package watchdog
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buth/sliceheap/clock"
+)
+
+func TestWatchdog(t *testing.T) {
+	var mu sync.Mutex
+	var overdue []int
+	fired := make(chan struct{}, 10)
+	w := New(func(x int) {
+		mu.Lock()
+		overdue = append(overdue, x)
+		mu.Unlock()
+		fired <- struct{}{}
+	})
+	defer w.Stop()
+
+	now := time.Now()
+	ops := make([]*Op[int], 5)
+	for i := range ops {
+		ops[i] = w.Watch(i, now.Add(time.Duration(50+10*i)*time.Millisecond))
+	}
+	if !ops[1].Ack() || ops[1].Ack() {
+		t.Error("Ack of outstanding operation should succeed exactly once")
+	}
+	if !ops[3].Extend(now.Add(time.Hour)) {
+		t.Error("Extend of outstanding operation failed")
+	}
+	ops[4].Extend(now.Add(time.Millisecond)) // now the earliest
+
+	for range 3 {
+		select {
+		case <-fired:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for overdue operations")
+		}
+	}
+	mu.Lock()
+	got := slices.Clone(overdue)
+	mu.Unlock()
+	if want := []int{4, 0, 2}; !slices.Equal(got, want) {
+		t.Errorf("overdue %v; want %v", got, want)
+	}
+	if ops[0].Ack() {
+		t.Error("Ack of overdue operation succeeded")
+	}
+	if w.Len() != 1 || !ops[3].Ack() || w.Len() != 0 {
+		t.Error("operation 3 should be the only one outstanding")
+	}
+}
+
+func TestStop(t *testing.T) {
+	w := New(func(int) { t.Error("operation reported overdue after Stop") })
+	w.Watch(1, time.Now().Add(20*time.Millisecond))
+	w.Stop()
+	time.Sleep(40 * time.Millisecond)
+}
+
+func TestFakeClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	fired := make(chan int, 2)
+	w := New(func(x int) { fired <- x })
+	w.Clock = clk
+	defer w.Stop()
+
+	w.Watch(1, clk.Now().Add(time.Hour))
+	w.Watch(2, clk.Now().Add(2*time.Hour))
+	for clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(90 * time.Minute)
+	if x := <-fired; x != 1 {
+		t.Errorf("overdue %d; want 1", x)
+	}
+	select {
+	case x := <-fired:
+		t.Errorf("%d reported overdue before its deadline", x)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if w.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", w.Len())
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package wfq provides a weighted fair queue, which shares service among
+// many flows of items in proportion to their weights.
+package wfq
+
+import (
+	"fmt"
+
+	"github.com/buth/sliceheap"
+)
+
+// A Queue multiplexes items from flows identified by F. Each item is
+// stamped with a virtual finish time: the later of the current virtual time
+// and the finish time of the previous item from its flow, plus the item's
+// size divided by the flow's weight. Pop returns the item with the earliest
+// finish time and advances the virtual time to it (self-clocked fair
+// queueing). Over any busy period, backlogged flows are served in proportion
+// to their weights, and a flow that sends a burst cannot starve the others.
+//
+// The zero value is an empty queue in which every flow has weight 1.
+// A Queue is not safe for concurrent use.
+type Queue[F comparable, T any] struct {
+	weights map[F]float64
+	flows   map[F]*flow
+	h       []item[F, T]
+	vtime   float64
+	seq     uint64
+}
+
+// A flow is the state of a flow with items in the queue.
+type flow struct {
+	finish  float64 // finish time of the flow's last item
+	pending int
+}
+
+type item[F comparable, T any] struct {
+	finish float64
+	seq    uint64 // tie-breaker: insertion order
+	flow   F
+	v      T
+}
+
+func less[F comparable, T any](x, y item[F, T]) bool {
+	if x.finish != y.finish {
+		return x.finish < y.finish
+	}
+	return x.seq < y.seq
+}
+
+// SetWeight sets the weight of flow, which must be positive. The new weight
+// applies to items pushed afterwards.
+func (q *Queue[F, T]) SetWeight(f F, w float64) {
+	if !(w > 0) {
+		panic(fmt.Sprintf("wfq: weight %v is not positive", w))
+	}
+	if q.weights == nil {
+		q.weights = make(map[F]float64)
+	}
+	q.weights[f] = w
+}
+
+// Weight returns the weight of flow f.
+func (q *Queue[F, T]) Weight(f F) float64 {
+	if w, ok := q.weights[f]; ok {
+		return w
+	}
+	return 1
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue[F, T]) Len() int {
+	return len(q.h)
+}
+
+// Push adds x to flow f. The size is the cost of serving x, for example its
+// length in bytes; use 1 to share items rather than bytes.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[F, T]) Push(f F, x T, size float64) {
+	if q.flows == nil {
+		q.flows = make(map[F]*flow)
+	}
+	fl, ok := q.flows[f]
+	if !ok {
+		fl = &flow{finish: q.vtime}
+		q.flows[f] = fl
+	}
+	fl.finish = max(fl.finish, q.vtime) + size/q.Weight(f)
+	fl.pending++
+	sliceheap.PushFunc(&q.h, item[F, T]{finish: fl.finish, seq: q.seq, flow: f, v: x}, less)
+	q.seq++
+}
+
+// Pop removes and returns the next item to serve and its flow. It reports
+// false if the queue is empty.
+// The complexity is O(log n) where n = q.Len().
+func (q *Queue[F, T]) Pop() (f F, x T, ok bool) {
+	if len(q.h) == 0 {
+		return f, x, false
+	}
+	it := sliceheap.PopFunc(&q.h, less)
+	q.vtime = it.finish
+	fl := q.flows[it.flow]
+	if fl.pending--; fl.pending == 0 {
+		// The flow's finish time is now at most the virtual time, so it
+		// need not be remembered.
+		delete(q.flows, it.flow)
+	}
+	return it.flow, it.v, true
+}
@@ -0,0 +1,71 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wfq
+
+import "testing"
+
+func TestWeightedShare(t *testing.T) {
+	var q Queue[string, int]
+	q.SetWeight("heavy", 3)
+	for i := 0; i < 300; i++ {
+		q.Push("heavy", i, 1)
+		q.Push("light", i, 1)
+	}
+
+	served := map[string]int{}
+	next := map[string]int{}
+	for i := 0; i < 200; i++ {
+		f, x, ok := q.Pop()
+		if !ok {
+			t.Fatal("Pop() on non-empty queue failed")
+		}
+		if x != next[f] {
+			t.Fatalf("flow %s served %d; want %d (FIFO within flow)", f, x, next[f])
+		}
+		next[f]++
+		served[f]++
+	}
+	if served["heavy"] != 150 || served["light"] != 50 {
+		t.Errorf("served %v; want heavy:150 light:50", served)
+	}
+}
+
+func TestBurstDoesNotStarve(t *testing.T) {
+	var q Queue[string, int]
+	for i := 0; i < 1000; i++ {
+		q.Push("bursty", i, 1)
+	}
+	// Serve part of the burst before the other flow arrives.
+	for i := 0; i < 10; i++ {
+		q.Pop()
+	}
+	q.Push("late", 0, 1)
+
+	for i := 0; i < 2; i++ {
+		if f, _, _ := q.Pop(); f == "late" {
+			return
+		}
+	}
+	t.Error("late flow not served within two pops")
+}
+
+func TestSize(t *testing.T) {
+	var q Queue[string, string]
+	q.Push("big", "b1", 10)
+	q.Push("big", "b2", 10)
+	for i := 0; i < 10; i++ {
+		q.Push("small", "s", 1)
+	}
+	var order string
+	for q.Len() > 0 {
+		_, x, _ := q.Pop()
+		order += x[:1]
+	}
+	// b1 and the last small item finish at the same virtual time; b1 was
+	// pushed first.
+	if want := "sssssssssbsb"; order != want {
+		t.Errorf("order %s; want %s", order, want)
+	}
+}